@@ -0,0 +1,158 @@
+package tview
+
+import (
+	"time"
+)
+
+// animationFrame is the interval at which frames of a resize animation or of
+// an animating primitive are drawn.
+const animationFrame = 25 * time.Millisecond
+
+// Rect is the position and size of a rectangle on the screen.
+type Rect struct {
+	X, Y, Width, Height int
+}
+
+// rectAnimation is a running resize animation started with
+// Application.AnimateResize().
+type rectAnimation struct {
+	from, to Rect
+	start    time.Time
+	duration time.Duration
+	done     func()
+
+	// Closed when the animation is cancelled.
+	stop chan struct{}
+}
+
+// rect returns the rectangle of the animation at the given time.
+func (r *rectAnimation) rect(now time.Time) Rect {
+	progress := 1.0
+	if r.duration > 0 {
+		progress = float64(now.Sub(r.start)) / float64(r.duration)
+	}
+	if progress >= 1 {
+		return r.to
+	}
+	if progress < 0 {
+		progress = 0
+	}
+	interpolate := func(from, to int) int {
+		return from + int(float64(to-from)*progress+0.5)
+	}
+	return Rect{
+		X:      interpolate(r.from.X, r.to.X),
+		Y:      interpolate(r.from.Y, r.to.Y),
+		Width:  interpolate(r.from.Width, r.to.Width),
+		Height: interpolate(r.from.Height, r.to.Height),
+	}
+}
+
+// AnimateResize moves and resizes the given primitive from its current
+// rectangle to the given rectangle over the given duration, redrawing the
+// screen regularly. When the animation has finished, the primitive has the
+// target rectangle and the "done" function (which may be nil) is called from
+// the event loop. A height or width of 0 collapses the primitive.
+//
+// Starting a new animation for a primitive which is still being animated
+// cancels the previous animation without calling its "done" function. The new
+// animation starts from the primitive's current, intermediate rectangle.
+//
+// The animated rectangle is applied right before the root primitive is drawn.
+// It is therefore only useful for primitives whose rectangle is not set by
+// their container while drawing, e.g. pages added with "resize" set to false
+// or the root primitive. For items of a Flex or a Grid, animate the layout
+// instead or resize the item in the "done" function.
+func (a *Application) AnimateResize(p Primitive, toRect Rect, d time.Duration, done func()) *Application {
+	x, y, width, height := p.GetRect()
+	animation := &rectAnimation{
+		from:     Rect{X: x, Y: y, Width: width, Height: height},
+		to:       toRect,
+		start:    time.Now(),
+		duration: d,
+		done:     done,
+		stop:     make(chan struct{}),
+	}
+
+	a.Lock()
+	if a.animations == nil {
+		a.animations = make(map[Primitive]*rectAnimation)
+	}
+	if previous, ok := a.animations[p]; ok {
+		close(previous.stop)
+	}
+	a.animations[p] = animation
+	a.Unlock()
+
+	go func() {
+		ticker := time.NewTicker(animationFrame)
+		defer ticker.Stop()
+		deadline := animation.start.Add(d)
+		for {
+			if !time.Now().Before(deadline) {
+				a.QueueUpdateDraw(func() {
+					a.finishAnimation(p, animation)
+				})
+				return
+			}
+			select {
+			case <-animation.stop:
+				return
+			case <-a.runContext.Done():
+				return
+			case <-ticker.C:
+				a.QueueUpdateDraw(func() {})
+			}
+		}
+	}()
+
+	return a
+}
+
+// finishAnimation sets the final rectangle of the given animation of the given
+// primitive and calls its "done" function unless it was cancelled in the
+// meantime.
+func (a *Application) finishAnimation(p Primitive, animation *rectAnimation) {
+	a.Lock()
+	if a.animations[p] != animation {
+		a.Unlock()
+		return // Cancelled.
+	}
+	delete(a.animations, p)
+	a.Unlock()
+
+	p.SetRect(animation.to.X, animation.to.Y, animation.to.Width, animation.to.Height)
+	if animation.done != nil {
+		animation.done()
+	}
+}
+
+// applyAnimations sets the current rectangles of all animated primitives. The
+// application must be locked.
+func (a *Application) applyAnimations() {
+	now := time.Now()
+	for p, animation := range a.animations {
+		rect := animation.rect(now)
+		p.SetRect(rect.X, rect.Y, rect.Width, rect.Height)
+	}
+}
+
+// animator is implemented by primitives which need to be redrawn regularly
+// while they are animating, e.g. Pages during a page transition.
+type animator interface {
+	animating() bool
+}
+
+// animating returns whether the given primitive or any of the primitives it
+// contains (see childPrimitives()) needs to be redrawn for another frame.
+func animating(primitive Primitive) bool {
+	if a, ok := primitive.(animator); ok && a.animating() {
+		return true
+	}
+	for _, child := range childPrimitives(primitive) {
+		if animating(child) {
+			return true
+		}
+	}
+	return false
+}
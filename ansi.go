@@ -90,136 +90,8 @@ func (a *ansi) Write(text []byte) (int, error) {
 					}
 					fmt.Fprint(a.buffer, strings.Repeat("\n", count))
 				case 'm': // Select Graphic Rendition.
-					var background, foreground string
-					params := a.csiParameter.String()
-					fields := strings.Split(params, ";")
-					if len(params) == 0 || len(fields) == 1 && fields[0] == "0" {
-						// Reset.
-						a.attributes = ""
-						if _, err := a.buffer.WriteString("[-:-:-]"); err != nil {
-							return 0, err
-						}
-						break
-					}
-					lookupColor := func(colorNumber int) string {
-						if colorNumber < 0 || colorNumber > 15 {
-							return "black"
-						}
-						return []string{
-							"black",
-							"maroon",
-							"green",
-							"olive",
-							"navy",
-							"purple",
-							"teal",
-							"silver",
-							"gray",
-							"red",
-							"lime",
-							"yellow",
-							"blue",
-							"fuchsia",
-							"aqua",
-							"white",
-						}[colorNumber]
-					}
-				FieldLoop:
-					for index, field := range fields {
-						switch field {
-						case "1", "01":
-							if !strings.ContainsRune(a.attributes, 'b') {
-								a.attributes += "b"
-							}
-						case "2", "02":
-							if !strings.ContainsRune(a.attributes, 'd') {
-								a.attributes += "d"
-							}
-						case "4", "04":
-							if !strings.ContainsRune(a.attributes, 'u') {
-								a.attributes += "u"
-							}
-						case "5", "05":
-							if !strings.ContainsRune(a.attributes, 'l') {
-								a.attributes += "l"
-							}
-						case "7", "07":
-							if !strings.ContainsRune(a.attributes, 'r') {
-								a.attributes += "r"
-							}
-						case "22":
-							if i := strings.IndexRune(a.attributes, 'b'); i >= 0 {
-								a.attributes = a.attributes[:i] + a.attributes[i+1:]
-							}
-							if i := strings.IndexRune(a.attributes, 'd'); i >= 0 {
-								a.attributes = a.attributes[:i] + a.attributes[i+1:]
-							}
-						case "24":
-							if i := strings.IndexRune(a.attributes, 'u'); i >= 0 {
-								a.attributes = a.attributes[:i] + a.attributes[i+1:]
-							}
-						case "25":
-							if i := strings.IndexRune(a.attributes, 'l'); i >= 0 {
-								a.attributes = a.attributes[:i] + a.attributes[i+1:]
-							}
-						case "30", "31", "32", "33", "34", "35", "36", "37":
-							colorNumber, _ := strconv.Atoi(field)
-							foreground = lookupColor(colorNumber - 30)
-						case "39":
-							foreground = "-"
-						case "40", "41", "42", "43", "44", "45", "46", "47":
-							colorNumber, _ := strconv.Atoi(field)
-							background = lookupColor(colorNumber - 40)
-						case "49":
-							background = "-"
-						case "90", "91", "92", "93", "94", "95", "96", "97":
-							colorNumber, _ := strconv.Atoi(field)
-							foreground = lookupColor(colorNumber - 82)
-						case "100", "101", "102", "103", "104", "105", "106", "107":
-							colorNumber, _ := strconv.Atoi(field)
-							background = lookupColor(colorNumber - 92)
-						case "38", "48":
-							var color string
-							if len(fields) > index+1 {
-								// 8-bit colors.
-								// 24-bit colors.
-								color = a.newMethod(fields, index, color, lookupColor)
-                if len(color) > 0 {
-                  if field == "38" {
-                    foreground = color
-                  } else {
-                    background = color
-                  }
-                }
-								if len(fields) > index+5 {
-                  field = fields[index+5]
-								color = a.newMethod(fields, index+5, color, lookupColor)
-                if len(color) > 0 {
-                  if field == "38" {
-                    foreground = color
-                  } else {
-                    background = color
-                  }
-                }
-
-								}
-							}
-							// if len(color) > 0 {
-							// 	if field == "38" {
-							// 		foreground = color
-							// 	} else {
-							// 		background = color
-							// 	}
-							// }
-							break FieldLoop
-						}
-					}
-					var colon string
-					if len(a.attributes) > 0 {
-						colon = ":"
-					}
-					if len(foreground) > 0 || len(background) > 0 || len(a.attributes) > 0 {
-						fmt.Fprintf(a.buffer, "[%s:%s%s%s]", foreground, background, colon, a.attributes)
+					if err := a.selectGraphicRendition(a.csiParameter.String()); err != nil {
+						return 0, err
 					}
 				}
 				a.state = ansiText
@@ -255,27 +127,174 @@ func (a *ansi) Write(text []byte) (int, error) {
 	return len(text), nil
 }
 
-func (*ansi) newMethod(fields []string, index int, color string, lookupColor func(colorNumber int) string) string {
-	if fields[index+1] == "5" && len(fields) > index+2 {
-		colorNumber, _ := strconv.Atoi(fields[index+2])
-		if colorNumber <= 15 {
-			color = lookupColor(colorNumber)
-		} else if colorNumber <= 231 {
+// ansiColors are the names of the 16 basic ANSI colors.
+var ansiColors = []string{
+	"black",
+	"maroon",
+	"green",
+	"olive",
+	"navy",
+	"purple",
+	"teal",
+	"silver",
+	"gray",
+	"red",
+	"lime",
+	"yellow",
+	"blue",
+	"fuchsia",
+	"aqua",
+	"white",
+}
+
+// ansiAttributes maps the SGR parameters which turn text attributes on to the
+// corresponding tview attribute flags.
+var ansiAttributes = map[int]rune{
+	1: 'b',
+	2: 'd',
+	3: 'i',
+	4: 'u',
+	5: 'l',
+	7: 'r',
+	9: 's',
+}
+
+// ansiAttributesOff maps the SGR parameters which turn text attributes off to
+// the tview attribute flags they remove.
+var ansiAttributesOff = map[int]string{
+	22: "bd",
+	23: "i",
+	24: "u",
+	25: "l",
+	27: "r",
+	29: "s",
+}
+
+// selectGraphicRendition translates the parameters of an SGR ("m") control
+// sequence into a color tag which is written to the buffer. 16-color,
+// 256-color, and 24-bit color sequences are supported. Unsupported parameters
+// are ignored. A malformed extended color parameter ends the processing of
+// the sequence.
+func (a *ansi) selectGraphicRendition(params string) error {
+	var (
+		background, foreground string
+		attributesChanged      bool
+	)
+	fields := strings.Split(params, ";")
+	for index := 0; index < len(fields); index++ {
+		code, err := strconv.Atoi(fields[index])
+		if fields[index] == "" {
+			code, err = 0, nil // An empty parameter is the same as 0.
+		}
+		if err != nil {
+			continue // Unsupported.
+		}
+		switch {
+		case code == 0:
+			a.attributes = ""
+			foreground, background, attributesChanged = "-", "-", true
+		case ansiAttributes[code] != 0:
+			if !strings.ContainsRune(a.attributes, ansiAttributes[code]) {
+				a.attributes += string(ansiAttributes[code])
+				attributesChanged = true
+			}
+		case ansiAttributesOff[code] != "":
+			for _, flag := range ansiAttributesOff[code] {
+				if i := strings.IndexRune(a.attributes, flag); i >= 0 {
+					a.attributes = a.attributes[:i] + a.attributes[i+1:]
+					attributesChanged = true
+				}
+			}
+		case code >= 30 && code <= 37:
+			foreground = ansiColors[code-30]
+		case code == 39:
+			foreground = "-"
+		case code >= 40 && code <= 47:
+			background = ansiColors[code-40]
+		case code == 49:
+			background = "-"
+		case code >= 90 && code <= 97:
+			foreground = ansiColors[code-82]
+		case code >= 100 && code <= 107:
+			background = ansiColors[code-92]
+		case code == 38 || code == 48:
+			color, consumed := ansiExtendedColor(fields[index+1:])
+			if consumed == 0 {
+				index = len(fields) // Malformed, ignore the rest.
+				break
+			}
+			index += consumed
+			if color == "" {
+				break
+			}
+			if code == 38 {
+				foreground = color
+			} else {
+				background = color
+			}
+		}
+	}
+
+	// Write the tag.
+	if foreground == "" && background == "" && !attributesChanged {
+		return nil
+	}
+	var attributes string
+	if attributesChanged {
+		attributes = ":" + a.attributes
+		if a.attributes == "" {
+			attributes = ":-"
+		}
+	}
+	_, err := fmt.Fprintf(a.buffer, "[%s:%s%s]", foreground, background, attributes)
+	return err
+}
+
+// ansiExtendedColor parses the parameters following an extended color SGR
+// parameter (38 or 48), i.e. "5;n" for 256-color and "2;r;g;b" for 24-bit
+// colors. It returns the color as a tview color name or an empty string if the
+// color is not supported, and the number of fields consumed (0 if the fields
+// are malformed).
+func ansiExtendedColor(fields []string) (color string, consumed int) {
+	if len(fields) == 0 {
+		return "", 0
+	}
+	switch fields[0] {
+	case "5": // 8-bit colors.
+		if len(fields) < 2 {
+			return "", 0
+		}
+		colorNumber, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return "", 2
+		}
+		if colorNumber >= 0 && colorNumber <= 15 {
+			color = ansiColors[colorNumber]
+		} else if colorNumber >= 16 && colorNumber <= 231 {
 			red := (colorNumber - 16) / 36
 			green := ((colorNumber - 16) / 6) % 6
 			blue := (colorNumber - 16) % 6
 			color = fmt.Sprintf("#%02x%02x%02x", 255*red/5, 255*green/5, 255*blue/5)
-		} else if colorNumber <= 255 {
+		} else if colorNumber >= 232 && colorNumber <= 255 {
 			grey := 255 * (colorNumber - 232) / 23
 			color = fmt.Sprintf("#%02x%02x%02x", grey, grey, grey)
 		}
-	} else if fields[index+1] == "2" && len(fields) > index+4 {
-		red, _ := strconv.Atoi(fields[index+2])
-		green, _ := strconv.Atoi(fields[index+3])
-		blue, _ := strconv.Atoi(fields[index+4])
-		color = fmt.Sprintf("#%02x%02x%02x", red, green, blue)
+		return color, 2
+	case "2": // 24-bit colors.
+		if len(fields) < 4 {
+			return "", 0
+		}
+		var rgb [3]int
+		for i := range rgb {
+			value, err := strconv.Atoi(fields[i+1])
+			if err != nil || value < 0 || value > 255 {
+				return "", 4
+			}
+			rgb[i] = value
+		}
+		return fmt.Sprintf("#%02x%02x%02x", rgb[0], rgb[1], rgb[2]), 4
 	}
-	return color
+	return "", 0
 }
 
 // TranslateANSI replaces ANSI escape sequences found in the provided string
@@ -2,26 +2,63 @@ package tview
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"runtime/debug"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gdamore/tcell/v2"
-	"github.com/gookit/goutil/errorx"
 )
 
 const (
 	// The size of the event/update/redraw channels.
 	queueSize = 100
 
-	// The minimum time between two consecutive redraws.
+	// The default minimum time between two consecutive redraws caused by
+	// resize events, see Application.SetRedrawThrottle().
 	redrawPause = 50 * time.Millisecond
 )
 
+// ErrApplicationClosed is returned when sending to an application which is
+// shutting down or was closed.
+var ErrApplicationClosed = errors.New("application is shutting down or was closed")
+
+// ErrApplicationNotRunning is returned by Application.SwapScreen() if the
+// application is not running.
+var ErrApplicationNotRunning = errors.New("application is not running")
+
+// PanicError is returned by Application.Run() if a panic occurred in the event
+// loop and a panic handler was installed with Application.SetPanicHandler().
+type PanicError struct {
+	// The value passed to panic().
+	Recovered any
+
+	// The stack trace of the goroutine which panicked.
+	Stack []byte
+}
+
+// Error returns a description of the panic.
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic in application: %v", e.Recovered)
+}
+
+// Unwrap returns the recovered value if it is an error, nil otherwise.
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Recovered.(error)
+	return err
+}
+
 // DoubleClickInterval specifies the maximum time between clicks to register a
 // double click rather than click.
 var DoubleClickInterval = 500 * time.Millisecond
 
+// DefaultMaxNotifications is the number of notifications an application keeps
+// by default. See Application.SetMaxNotifications().
+var DefaultMaxNotifications = 100
+
 // MouseAction indicates one of the actions the mouse is logically doing.
 type MouseAction int16
 
@@ -44,6 +81,9 @@ const (
 	MouseScrollDown
 	MouseScrollLeft
 	MouseScrollRight
+	MouseLeftDrag   // The mouse moved while the left button is held, see Application.SetDragThreshold().
+	MouseMiddleDrag // The mouse moved while the middle button is held.
+	MouseRightDrag  // The mouse moved while the right button is held.
 )
 
 // queuedUpdate represented the execution of f queued by
@@ -86,6 +126,46 @@ type Application struct {
 	// Whether or not the application resizes the root primitive.
 	rootFullscreen bool
 
+	// An optional function which is called when the root primitive changes.
+	rootChanged func(old, new Primitive)
+
+	// The screens pushed with PushScreen(), from bottom to top. The top screen
+	// is the root primitive. Empty if the screen stack is not used.
+	screens []*stackedScreen
+
+	// Whether or not the screens below the top screen are drawn.
+	drawLowerScreens bool
+
+	// The minimum screen size required to draw the root primitive. If the
+	// screen is smaller, a message is shown instead.
+	minWidth, minHeight int
+
+	// Set to true while the screen is smaller than the minimum size.
+	tooSmall bool
+
+	// If set to true, the outlines of all primitives are drawn on top of the
+	// root primitive.
+	layoutDebug bool
+
+	// If set to true, SetRoot() focuses the first focusable leaf primitive
+	// instead of the root primitive.
+	autoFocusFirst bool
+
+	// Guards drawing and redraw.
+	drawMutex sync.Mutex
+
+	// Set to true while the screen is being drawn. Calls to draw() made in the
+	// meantime, e.g. from draw callbacks, would otherwise deadlock.
+	drawing bool
+
+	// Set to true if draw() was called while drawing. The running draw then
+	// draws the screen once more.
+	redraw bool
+
+	// Set to 1 if draw calls made while drawing are not reported to the
+	// logger, see SetConcurrentDraw(). Accessed atomically.
+	concurrentDraw int32
+
 	// Set to true if mouse events are enabled.
 	enableMouse bool
 
@@ -94,6 +174,10 @@ type Application struct {
 	// be forwarded).
 	inputCapture func(event *tcell.EventKey) *tcell.EventKey
 
+	// An optional registry of key bindings which are triggered after the
+	// input capture function and before the focused primitive.
+	keyMap *KeyMap
+
   // An optional callback function which is invoked before the application's
 	// focus changes.
 	beforeFocus func(p Primitive) bool
@@ -102,6 +186,10 @@ type Application struct {
 	afterFocus func(p Primitive)
   onPaste func(screen tcell.Screen, ev *tcell.EventPaste)
 
+	// An optional callback function which is invoked after the application's
+	// screen was replaced with SetScreen() while running.
+	onScreenReplaced func(oldScreen, newScreen tcell.Screen)
+
 	// An optional callback function which is invoked just before the root
 	// primitive is drawn.
 	beforeDraw func(screen tcell.Screen) bool
@@ -111,6 +199,107 @@ type Application struct {
 	// was drawn.
 	afterDraw func(screen tcell.Screen)
 
+	// The interval of the shared clock which redraws the screen periodically
+	// while the application is running. 0 disables the clock.
+	tickInterval time.Duration
+
+	// An optional callback function which is invoked on every tick of the
+	// shared clock, just before the screen is redrawn.
+	tick func(now time.Time)
+
+	// An optional callback function which is invoked after idleDuration has
+	// passed without user input.
+	idle         func()
+	idleDuration time.Duration
+
+	// Signals the event loop that the idle callback was changed.
+	idleReset chan struct{}
+
+	// The running resize animations, see AnimateResize().
+	animations map[Primitive]*rectAnimation
+
+	// The timer which draws the next frame of animating primitives, e.g. page
+	// transitions. nil if no frame is scheduled.
+	frameTimer *time.Timer
+
+	// Closed when Run() returns. nil if Run() was never called.
+	runDone chan struct{}
+
+	// Guards sending on the channels above against closing them in Close().
+	// Senders hold the read lock, Close() holds the write lock.
+	sendLock sync.RWMutex
+
+	// Set to true once Close() has closed the channels.
+	closed bool
+
+	// An optional function which receives internal diagnostic messages, of
+	// type func(format string, args ...any). It is accessed atomically so that
+	// it can be used while the application's lock is held.
+	logger atomic.Value
+
+	// An optional function which receives events the event loop doesn't
+	// handle itself.
+	rawEventHook func(event tcell.Event) (handled bool)
+
+	// The most recent notifications, oldest first, and the maximum number of
+	// notifications kept.
+	notifications    []Notification
+	maxNotifications int
+
+	// An optional callback function which is invoked for every notification.
+	notify func(notification Notification)
+
+	// The toasts shown with ShowNotification(), oldest first.
+	toasts []*Toast
+
+	// The time the mouse pointer must rest on a primitive before its tooltip
+	// is shown, the style of tooltips, and whether that style was set with
+	// SetTooltipStyle().
+	tooltipDelay    time.Duration
+	tooltipStyle    tcell.Style
+	tooltipStyleSet bool
+
+	// The theme last set with SetTheme(), nil if none was set.
+	theme *Theme
+
+	// The tooltip which is currently shown (nil if none), the tooltip which
+	// is waiting for the tooltip delay to pass (nil if none), and the timer
+	// which shows it.
+	tooltip        *tooltip
+	tooltipPending *tooltip
+	tooltipTimer   *time.Timer
+
+	// The minimum time between two consecutive redraws caused by resize
+	// events, and the timer which delivers the last resize event of a burst.
+	redrawThrottle time.Duration
+	redrawTimer    *time.Timer
+
+	// The maximum number of redraws per second (0 for no limit), the time of
+	// the last redraw, and the timer which performs a redraw which was skipped
+	// because of the limit.
+	maxFPS    int
+	lastDraw  time.Time
+	drawTimer *time.Timer
+
+	// The statistics returned by GetDrawStats().
+	drawStats DrawStats
+
+	// An optional function which is called when the event loop panics,
+	// instead of panicking again.
+	panicHandler func(recovered any)
+
+	// The overlay shown with ShowKeyHelp(), nil if it is not shown.
+	keyHelp *keyHelp
+
+	// Whether or not the focused primitive is highlighted, and the style of
+	// the highlight (tcell.StyleDefault to reverse the existing colors).
+	focusIndicator      bool
+	focusIndicatorStyle tcell.Style
+
+	// The style of screen cells which are not covered by any primitive, see
+	// SetScreenStyle().
+	screenStyle tcell.Style
+
 	// Used to send screen events from separate goroutine to main event loop
 	events chan tcell.Event
 
@@ -123,6 +312,10 @@ type Application struct {
 	// stop the application.
 	screenReplacement chan tcell.Screen
 
+	// A screen sent to the screenReplacement channel by SwapScreen() which was
+	// already initialized.
+	initializedScreen tcell.Screen
+
 	// An optional capture function which receives a mouse event and returns the
 	// event to be forwarded to the default mouse handler (nil if nothing should
 	// be forwarded).
@@ -132,11 +325,26 @@ type Application struct {
 	lastMouseX, lastMouseY  int              // The last position of the mouse.
 	mouseDownX, mouseDownY  int              // The position of the mouse when its button was last pressed.
 	lastMouseClick          time.Time        // The time when a mouse button was last clicked.
+	doubleClickInterval     time.Duration    // The maximum time between two clicks of a double click. 0 for DoubleClickInterval.
+	dragThreshold           int              // The distance the mouse may move with a button held before the click becomes a drag.
+	dragButtons             tcell.ButtonMask // The held buttons for which the mouse moved beyond the drag threshold since they were pressed.
 	lastMouseButtons        tcell.ButtonMask // The last mouse button state.
+	hovered                 []Primitive      // The primitives with enter or leave functions under the mouse pointer.
 }
 
+// Close stops the event loop and closes the application's channels. Any
+// further attempts to queue events or updates are ignored. Calling Close() more
+// than once has no effect.
 func (a *Application) Close() error {
+	// Cancelling first releases senders blocked on full channels.
 	a.runCancelFunc()
+
+	a.sendLock.Lock()
+	defer a.sendLock.Unlock()
+	if a.closed {
+		return nil
+	}
+	a.closed = true
 	close(a.events)
 	close(a.screenReplacement)
 	close(a.updates)
@@ -163,6 +371,58 @@ func (a *Application) Close() error {
 	return nil
 }
 
+// Shutdown stops the application and waits for Run() to return before tearing
+// down the application's channels with Close(). If the context expires before
+// Run() returns, the context's error is returned and the channels are left
+// intact. This function is safe to call from any goroutine, e.g. a signal
+// handler, but not from the event loop itself (e.g. a key handler), as it would
+// wait for itself.
+//
+// Updates queued with QueueUpdate() before Shutdown() was called are all
+// executed before the application stops. Updates queued afterwards are
+// discarded.
+func (a *Application) Shutdown(ctx context.Context) error {
+	a.RLock()
+	done := a.runDone
+	a.RUnlock()
+
+	if done != nil && a.runContext.Err() == nil {
+		// Stop after all previously queued updates. Cancelling the run context
+		// ends the event loop right after this update, discarding later ones.
+		a.QueueUpdate(func() {
+			a.Stop()
+			a.runCancelFunc()
+		})
+	}
+
+	if done != nil {
+		select {
+		case <-done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return a.Close()
+}
+
+// send calls f, which sends on one of the application's channels, while the
+// channels are guaranteed to stay open. f must also return when the provided
+// channel is closed, i.e. when the application is shutting down.
+// ErrApplicationClosed is returned if the application is shutting down or was
+// closed.
+func (a *Application) send(f func(done <-chan struct{}) bool) error {
+	a.sendLock.RLock()
+	defer a.sendLock.RUnlock()
+	if a.closed || a.runContext.Err() != nil {
+		return ErrApplicationClosed
+	}
+	if !f(a.runContext.Done()) {
+		return ErrApplicationClosed
+	}
+	return nil
+}
+
 // NewApplication creates and returns a new application.
 func NewApplication() *Application {
 	cancelContext, cancelFunc := context.WithCancel(context.Background())
@@ -172,6 +432,11 @@ func NewApplication() *Application {
 		events:            make(chan tcell.Event, queueSize),
 		updates:           make(chan queuedUpdate, queueSize),
 		screenReplacement: make(chan tcell.Screen, 1),
+		idleReset:         make(chan struct{}, 1),
+		maxNotifications:  DefaultMaxNotifications,
+		tooltipDelay:      DefaultTooltipDelay,
+		tooltipStyle:      tcell.StyleDefault.Foreground(Styles.PrimaryTextColor).Background(Styles.MoreContrastBackgroundColor),
+		redrawThrottle:    redrawPause,
 	}
 }
 
@@ -195,6 +460,114 @@ func (a *Application) GetInputCapture() func(event *tcell.EventKey) *tcell.Event
 	return a.inputCapture
 }
 
+// SetKeyMap installs a registry of key bindings. Key events which pass the
+// input capture function (see SetInputCapture()) are matched against the key
+// map first. If they trigger a binding, its action is invoked and the event is
+// not forwarded to the focused primitive. Like the input capture function, a
+// binding can intercept the Ctrl-C event which closes the application. Provide
+// nil to remove the key map.
+func (a *Application) SetKeyMap(keyMap *KeyMap) *Application {
+	a.Lock()
+	defer a.Unlock()
+	a.keyMap = keyMap
+	return a
+}
+
+// SetFocusIndicator sets whether the primitive which has focus is highlighted
+// after every redraw, independently of its own focus styles. This is useful
+// for primitives without a border whose focus would otherwise be invisible.
+// The highlight is drawn on the first column of the primitive's rectangle.
+// Primitives with a border are skipped as their border already indicates focus
+// (see Box.SetBorderFocusColor()).
+func (a *Application) SetFocusIndicator(enabled bool) *Application {
+	a.Lock()
+	defer a.Unlock()
+	a.focusIndicator = enabled
+	return a
+}
+
+// SetFocusIndicatorStyle sets the style of the focus indicator (see
+// SetFocusIndicator()). The default, tcell.StyleDefault, reverses the colors
+// of the highlighted cells.
+func (a *Application) SetFocusIndicatorStyle(style tcell.Style) *Application {
+	a.Lock()
+	defer a.Unlock()
+	a.focusIndicatorStyle = style
+	return a
+}
+
+// SetScreenStyle sets the style of the screen's cells which are not covered
+// by any primitive, e.g. around a root primitive which is not fullscreen. The
+// screen is blanked with this style when it is redrawn entirely, for example
+// after a resize. The default is tcell.StyleDefault, the terminal's own
+// colors. The screen is redrawn.
+func (a *Application) SetScreenStyle(style tcell.Style) *Application {
+	a.Lock()
+	a.screenStyle = style
+	if a.screen != nil {
+		clearScreen(a.screen, style)
+	}
+	a.Unlock()
+	return a.Draw()
+}
+
+// drawFocusIndicator highlights the first column of the given focused
+// primitive's rectangle using the given style. If the style is
+// tcell.StyleDefault, the colors of the existing cells are reversed.
+// Primitives with a border are not highlighted.
+func drawFocusIndicator(screen tcell.Screen, focus Primitive, style tcell.Style) {
+	if bordered, ok := focus.(interface{ IsBorder() bool }); ok && bordered.IsBorder() {
+		return
+	}
+	x, y, width, height := focus.GetRect()
+	if width <= 0 {
+		return
+	}
+	for row := y; row < y+height; row++ {
+		mainc, combc, cellStyle, _ := screen.GetContent(x, row)
+		if style == tcell.StyleDefault {
+			_, _, attributes := cellStyle.Decompose()
+			cellStyle = cellStyle.Reverse(attributes&tcell.AttrReverse == 0)
+		} else {
+			cellStyle = style
+		}
+		screen.SetContent(x, row, mainc, combc, cellStyle)
+	}
+}
+
+// ShowKeyHelp shows an overlay in the center of the screen which lists the
+// bindings of the key map (see SetKeyMap()) which are currently active, grouped
+// by the primitives they are limited to, followed by the global bindings. If
+// the focused primitive documents its own shortcuts (see KeyHelper), they are
+// listed, too. The overlay shows the bindings' labels (see KeyBinding.Label).
+//
+// The overlay is closed by the next key event which is not processed any
+// further. It is redrawn automatically when shown from a key binding's action.
+// Otherwise, call Draw() afterwards.
+func (a *Application) ShowKeyHelp() *Application {
+	a.Lock()
+	defer a.Unlock()
+	a.keyHelp = newKeyHelp(a.keyMap, a.focus)
+	return a
+}
+
+// HideKeyHelp closes the overlay shown with ShowKeyHelp(). Call Draw()
+// afterwards if this is not called from the event loop.
+func (a *Application) HideKeyHelp() *Application {
+	a.Lock()
+	defer a.Unlock()
+	a.keyHelp = nil
+	return a
+}
+
+// GetKeyMap returns the key map installed with SetKeyMap() or nil if there is
+// none.
+func (a *Application) GetKeyMap() *KeyMap {
+	a.RLock()
+	defer a.RUnlock()
+	return a.keyMap
+}
+
 // SetMouseCapture sets a function which captures mouse events (consisting of
 // the original tcell mouse event and the semantic mouse action) before they are
 // forwarded to the appropriate mouse event handler. This function can then
@@ -234,14 +607,75 @@ func (a *Application) SetScreen(screen tcell.Screen) *Application {
 	oldScreen := a.screen
 	a.Unlock()
 	oldScreen.Fini()
-	// check to see if the Application.Run is still valid
-	if a.runContext.Err() == nil {
-		a.screenReplacement <- screen
-	}
+	a.send(func(done <-chan struct{}) bool {
+		select {
+		case a.screenReplacement <- screen:
+			return true
+		case <-done:
+			return false
+		}
+	})
 
 	return a
 }
 
+// SwapScreen replaces the screen of the running application with the given
+// screen, e.g. to move the application to a different terminal. The new screen
+// is initialized before the old screen is finalized so if initialization fails,
+// the error is returned and the application keeps using the old screen. Mouse
+// events are enabled on the new screen if they were enabled with
+// EnableMouse(), the screen is redrawn, and the handler set with
+// SetOnScreenReplacedFunc() is called.
+//
+// ErrApplicationNotRunning is returned if the application is not running. Use
+// SetScreen() to provide a screen before Run() is called. This function must
+// not be called from the event loop (e.g. from a key handler) as the event
+// loop may be waiting for it.
+func (a *Application) SwapScreen(screen tcell.Screen) error {
+	if screen == nil {
+		return errors.New("no screen provided")
+	}
+	running := func() bool {
+		return a.runDone != nil && a.screen != nil && a.runContext.Err() == nil
+	}
+	a.RLock()
+	isRunning := running()
+	a.RUnlock()
+	if !isRunning {
+		return ErrApplicationNotRunning
+	}
+
+	// Initialize the new screen first so we can keep the old one on failure.
+	if err := screen.Init(); err != nil {
+		return err
+	}
+
+	a.Lock()
+	if !running() {
+		a.Unlock()
+		screen.Fini()
+		return ErrApplicationNotRunning
+	}
+	oldScreen := a.screen
+	a.initializedScreen = screen
+	a.Unlock()
+
+	oldScreen.Fini()
+	err := a.send(func(done <-chan struct{}) bool {
+		select {
+		case a.screenReplacement <- screen:
+			return true
+		case <-done:
+			return false
+		}
+	})
+	if err != nil {
+		screen.Fini()
+		return err
+	}
+	return nil
+}
+
 // EnableMouse enables mouse events or disables them (if "false" is provided).
 func (a *Application) EnableMouse(enable bool) *Application {
 	a.Lock()
@@ -259,14 +693,18 @@ func (a *Application) EnableMouse(enable bool) *Application {
 
 // Run starts the application and thus the event loop. This function returns
 // when Stop() was called.
-func (a *Application) Run() error {
+func (a *Application) Run() (runErr error) {
 	var (
 		err, appErr error
-		lastRedraw  time.Time   // The time the screen was last redrawn.
-		redrawTimer *time.Timer // A timer to schedule the next redraw.
+		lastRedraw time.Time // The time the screen was last redrawn.
 	)
 	a.Lock()
 
+	// Signal Shutdown() when we're done.
+	runDone := make(chan struct{})
+	a.runDone = runDone
+	defer close(runDone)
+
 	// Make a screen if there is none yet.
 	if a.screen == nil {
 		a.screen, err = tcell.NewScreen()
@@ -286,10 +724,20 @@ func (a *Application) Run() error {
 	// We catch panics to clean up because they mess up the terminal.
 	defer func() {
 		if p := recover(); p != nil {
-			if a.screen != nil {
-				a.screen.Fini()
+			stack := debug.Stack()
+			a.Lock()
+			screen, handler := a.screen, a.panicHandler
+			a.screen = nil
+			a.Unlock()
+			if screen != nil {
+				screen.Fini()
+			}
+			if handler == nil {
+				panic(p)
 			}
-			panic(p)
+			a.runCancelFunc()
+			handler(p)
+			runErr = &PanicError{Recovered: p, Stack: stack}
 		}
 	}()
 
@@ -329,6 +777,7 @@ func (a *Application) Run() error {
 
 			// A screen was finalized (event is nil). Wait for a new screen.
 			var ok bool
+			oldScreen := screen
 			select {
 			// exit when runContext complete
 			case <-a.runContext.Done():
@@ -343,21 +792,70 @@ func (a *Application) Run() error {
 				// We have a new screen. Keep going.
 				a.Lock()
 				a.screen = screen
+				initialized := a.initializedScreen == screen
+				a.initializedScreen = nil
 				enableMouse := a.enableMouse
+				onScreenReplaced := a.onScreenReplaced
 				a.Unlock()
 
 				// Initialize and draw this screen.
-				if err := screen.Init(); err != nil {
-					panic(err)
+				if !initialized {
+					if err := screen.Init(); err != nil {
+						panic(err)
+					}
 				}
 				if enableMouse {
 					screen.EnableMouse()
 				}
 				a.draw()
+
+				// Notify the application on the event loop.
+				if onScreenReplaced != nil {
+					newScreen := screen
+					a.QueueUpdateDraw(func() {
+						onScreenReplaced(oldScreen, newScreen)
+					})
+				}
 			}
 		}
 	}()
 
+	// Start the shared clock.
+	a.RLock()
+	tickInterval := a.tickInterval
+	a.RUnlock()
+	var tickC <-chan time.Time
+	if tickInterval > 0 {
+		ticker := time.NewTicker(tickInterval)
+		defer ticker.Stop()
+		tickC = ticker.C
+	}
+
+	// Start the idle timer. It is rearmed on user input.
+	var (
+		idleTimer *time.Timer
+		idleC     <-chan time.Time
+	)
+	resetIdle := func() {
+		if idleTimer != nil {
+			idleTimer.Stop()
+			idleTimer, idleC = nil, nil
+		}
+		a.RLock()
+		idleDuration, idle := a.idleDuration, a.idle
+		a.RUnlock()
+		if idle != nil && idleDuration > 0 {
+			idleTimer = time.NewTimer(idleDuration)
+			idleC = idleTimer.C
+		}
+	}
+	resetIdle()
+	defer func() {
+		if idleTimer != nil {
+			idleTimer.Stop()
+		}
+	}()
+
 	// Start event loop.
 EventLoop:
 	// check to see if the Application.Run is still valid
@@ -371,11 +869,28 @@ EventLoop:
 				break EventLoop
 			}
 
+			// User input resets the idle timer.
+			switch event.(type) {
+			case *tcell.EventKey, *tcell.EventMouse, *tcell.EventResize, *tcell.EventPaste:
+				resetIdle()
+			}
+
 			switch event := event.(type) {
 			case *tcell.EventKey:
+				// Any key closes the key help overlay.
+				a.Lock()
+				helpShown := a.keyHelp != nil
+				a.keyHelp = nil
+				a.Unlock()
+				if helpShown {
+					a.draw()
+					continue
+				}
+
 				a.RLock()
 				root := a.root
 				inputCapture := a.inputCapture
+				keyMap := a.keyMap
 				a.RUnlock()
 
 				// Intercept keys.
@@ -389,14 +904,26 @@ EventLoop:
 					draw = true
 				}
 
+				// Trigger key bindings.
+				if keyMap != nil && keyMap.dispatch(event, a.GetFocus()) {
+					a.draw()
+					continue
+				}
+
 				// Ctrl-C closes the application.
 				if event.Key() == tcell.KeyCtrlC {
 					a.Stop()
 					break
 				}
 
-				// Pass other key events to the root primitive.
-				if root != nil && root.HasFocus() {
+				// Pass other key events to an open context menu or the root
+				// primitive.
+				if menu, ok := a.GetFocus().(*ContextMenu); ok && menu.IsOpen() {
+					menu.InputHandler()(event, func(p Primitive) {
+						a.SetFocus(p)
+					})
+					draw = true
+				} else if root != nil && root.HasFocus() {
 					if handler := root.InputHandler(); handler != nil {
 						handler(event, func(p Primitive) {
 							a.SetFocus(p)
@@ -418,31 +945,36 @@ EventLoop:
       // }
       break
     }
-    fmt.Println("No paste handler", event)
+    if !a.handleRawEvent(event) {
+      a.logf("no paste handler for %v", event)
+    }
 
     // if event
 			case *tcell.EventResize:
-				if time.Since(lastRedraw) < redrawPause {
-					if redrawTimer != nil {
-						redrawTimer.Stop()
-					}
-					redrawTimer = time.AfterFunc(redrawPause,
+				// Resizes in quick succession are coalesced: only the last
+				// one is delivered again once the throttle has passed, so the
+				// final size is always drawn.
+				a.Lock()
+				if a.redrawTimer != nil {
+					a.redrawTimer.Stop()
+					a.redrawTimer = nil
+				}
+				if throttle, since := a.redrawThrottle, time.Since(lastRedraw); since < throttle {
+					a.redrawTimer = time.AfterFunc(throttle-since,
 						func() {
-							// check to see if the Application.Run is still valid
-							if a.runContext.Err() == nil {
-								a.events <- event
-							}
+							a.QueueEvent(event)
 						},
 					)
+					a.Unlock()
+					continue
 				}
-				a.RLock()
-				screen := a.screen
-				a.RUnlock()
+				screen, screenStyle := a.screen, a.screenStyle
+				a.Unlock()
 				if screen == nil {
 					continue
 				}
 				lastRedraw = time.Now()
-				screen.Clear()
+				clearScreen(screen, screenStyle)
 	resize := a.afterResize
     if resize != nil {
       resize(screen)
@@ -450,7 +982,8 @@ EventLoop:
 				a.draw()
 			case *tcell.EventMouse:
 				consumed, isMouseDownAction := a.fireMouseActions(event)
-				if consumed {
+				hoverChanged := a.updateHover(event.Position())
+				if a.updateTooltip(event) || consumed || hoverChanged {
 					a.draw()
 				}
 				a.lastMouseButtons = event.Buttons()
@@ -460,7 +993,42 @@ EventLoop:
 			case *tcell.EventError:
 				appErr = event
 				a.Stop()
+			case *tcell.EventInterrupt:
+				if barrier, ok := event.Data().(simulationBarrier); ok {
+					close(barrier) // See RunSimulation().
+				} else if !a.handleRawEvent(event) {
+					a.logf("unhandled event %T", event)
+				}
+			default:
+				if !a.handleRawEvent(event) {
+					a.logf("unhandled event %T", event)
+				}
+			}
+
+		// The shared clock ticked.
+		case now := <-tickC:
+			a.RLock()
+			tick := a.tick
+			a.RUnlock()
+			if tick != nil {
+				tick(now)
+			}
+			a.draw()
+
+		// The user has been idle for a while.
+		case <-idleC:
+			a.RLock()
+			idle := a.idle
+			a.RUnlock()
+			if idle != nil {
+				idle()
+				a.draw()
 			}
+			resetIdle()
+
+		// The idle callback was changed.
+		case <-a.idleReset:
+			resetIdle()
 
 		// If we have updates, now is the time to execute them.
 		case update, ok := <-a.updates:
@@ -479,7 +1047,9 @@ EventLoop:
 
 	// Wait for the event loop to finish.
 	wg.Wait()
+	a.Lock()
 	a.screen = nil
+	a.Unlock()
 
 	return appErr
 }
@@ -513,6 +1083,13 @@ func (a *Application) fireMouseActions(event *tcell.EventMouse) (consumed, isMou
 			targetPrimitive = a.mouseCapturingPrimitive
 		} else if targetPrimitive != nil {
 			primitive = targetPrimitive
+		} else if dropDown := openDropDown(a.GetFocus()); dropDown != nil {
+			// An open drop-down list floats on top so it gets mouse events
+			// first, even outside of its containers.
+			primitive = dropDown
+		} else if calendar := openCalendar(a.GetFocus()); calendar != nil && calendar.inGrid(event.Position()) {
+			// So does a calendar's pop-up month grid.
+			primitive = calendar
 		} else {
 			primitive = a.root
 		}
@@ -530,13 +1107,44 @@ func (a *Application) fireMouseActions(event *tcell.EventMouse) (consumed, isMou
 		a.mouseCapturingPrimitive = capturingPrimitive
 	}
 
+	a.RLock()
+	doubleClickInterval := a.doubleClickInterval
+	a.RUnlock()
+	if doubleClickInterval <= 0 {
+		doubleClickInterval = DoubleClickInterval
+	}
+
 	x, y := event.Position()
 	buttons := event.Buttons()
-	clickMoved := x != a.mouseDownX || y != a.mouseDownY
 	buttonChanges := buttons ^ a.lastMouseButtons
 
+	// Movements within the drag threshold don't turn a click into a drag.
+	a.RLock()
+	threshold := a.dragThreshold
+	a.RUnlock()
+	dx, dy := x-a.mouseDownX, y-a.mouseDownY
+	clickMoved := dx > threshold || -dx > threshold || dy > threshold || -dy > threshold
+	heldButtons := a.lastMouseButtons & (tcell.ButtonPrimary | tcell.ButtonMiddle | tcell.ButtonSecondary)
+	if clickMoved {
+		a.dragButtons |= heldButtons
+	}
+	clickMoved = clickMoved || a.dragButtons != 0
+
+	// Plain moves are always fired, drags in addition while a button is held.
 	if x != a.lastMouseX || y != a.lastMouseY {
 		fire(MouseMove)
+		for _, dragEvent := range []struct {
+			button tcell.ButtonMask
+			action MouseAction
+		}{
+			{tcell.ButtonPrimary, MouseLeftDrag},
+			{tcell.ButtonMiddle, MouseMiddleDrag},
+			{tcell.ButtonSecondary, MouseRightDrag},
+		} {
+			if a.dragButtons&buttons&dragEvent.button != 0 {
+				fire(dragEvent.action)
+			}
+		}
 		a.lastMouseX = x
 		a.lastMouseY = y
 	}
@@ -555,7 +1163,7 @@ func (a *Application) fireMouseActions(event *tcell.EventMouse) (consumed, isMou
 			} else {
 				fire(buttonEvent.up)
 				if !clickMoved {
-					if a.lastMouseClick.Add(DoubleClickInterval).Before(time.Now()) {
+					if a.lastMouseClick.Add(doubleClickInterval).Before(time.Now()) {
 						fire(buttonEvent.click)
 						a.lastMouseClick = time.Now()
 					} else {
@@ -580,48 +1188,167 @@ func (a *Application) fireMouseActions(event *tcell.EventMouse) (consumed, isMou
 		}
 	}
 
+	a.dragButtons &= buttons
+
 	return consumed, isMouseDownAction
 }
 
-// Stop stops the application, causing Run() to return.
-func (a *Application) Stop() {
-	a.Lock()
-	defer a.Unlock()
-	screen := a.screen
-	if screen == nil {
-		return
-	}
-	a.screen = nil
-	screen.Fini()
-
-	// check to see if the Application.Run is still valid
-	if a.runContext.Err() == nil {
-		a.screenReplacement <- nil
-	}
+// hoverable is implemented by primitives which embed a Box and may therefore
+// have functions which are called when the mouse pointer enters or leaves them
+// or a tooltip.
+type hoverable interface {
+	hoverFuncs() (enter, leave func())
+	GetTooltip() string
 }
 
-// Suspend temporarily suspends the application by exiting terminal UI mode and
-// invoking the provided function "f". When "f" returns, terminal UI mode is
-// entered again and the application resumes.
-//
-// A return value of true indicates that the application was suspended and "f"
-// was called. If false is returned, the application was already suspended,
-// terminal UI mode was not exited, and "f" was not called.
-func (a *Application) Suspend(f func()) bool {
+// updateHover determines the primitives under the mouse pointer at the given
+// position and calls the enter and leave functions of those which the pointer
+// entered or left (see Box.SetMouseEnterFunc()). It returns whether any such
+// function was called.
+func (a *Application) updateHover(x, y int) (changed bool) {
 	a.RLock()
-	screen := a.screen
+	root := a.root
 	a.RUnlock()
-	if screen == nil {
-		return false // Screen has not yet been initialized.
+
+	// Find the primitives under the pointer.
+	var hovered []Primitive
+	var find func(p Primitive)
+	find = func(p Primitive) {
+		if p == nil {
+			return
+		}
+		if box, ok := p.(interface{ IsVisible() bool }); ok && !box.IsVisible() {
+			return
+		}
+		px, py, width, height := p.GetRect()
+		if x < px || x >= px+width || y < py || y >= py+height {
+			return
+		}
+		if h, ok := p.(hoverable); ok {
+			if enter, leave := h.hoverFuncs(); enter != nil || leave != nil || h.GetTooltip() != "" {
+				hovered = append(hovered, p)
+			}
+		}
+		for _, child := range childPrimitives(p) {
+			find(child)
+		}
 	}
+	find(root)
 
-	// Enter suspended mode.
-	if err := screen.Suspend(); err != nil {
-		return false // Suspension failed.
+	contains := func(primitives []Primitive, p Primitive) bool {
+		for _, primitive := range primitives {
+			if primitive == p {
+				return true
+			}
+		}
+		return false
 	}
 
-	// Wait for "f" to return.
-	f()
+	// Leave the innermost primitives first, enter the outermost ones first.
+	for index := len(a.hovered) - 1; index >= 0; index-- {
+		if p := a.hovered[index]; !contains(hovered, p) {
+			if _, leave := p.(hoverable).hoverFuncs(); leave != nil {
+				leave()
+				changed = true
+			}
+		}
+	}
+	for _, p := range hovered {
+		if !contains(a.hovered, p) {
+			if enter, _ := p.(hoverable).hoverFuncs(); enter != nil {
+				enter()
+				changed = true
+			}
+		}
+	}
+	a.hovered = hovered
+
+	return
+}
+
+// SetDoubleClickInterval sets the maximum time between two clicks for them to
+// be registered as a double click, for this application only. A value of 0
+// (the default) uses the package-wide DoubleClickInterval.
+func (a *Application) SetDoubleClickInterval(interval time.Duration) *Application {
+	a.Lock()
+	defer a.Unlock()
+	a.doubleClickInterval = interval
+	return a
+}
+
+// SetDragThreshold sets the number of cells the mouse may move (horizontally or
+// vertically) while a button is held before the movement is treated as a drag.
+// As long as the mouse stays within this distance of where the button was
+// pressed, releasing the button still results in a click, and no drag actions
+// are fired. Once the threshold was exceeded, every movement fires the drag
+// action of the held button (MouseLeftDrag, MouseMiddleDrag, or
+// MouseRightDrag, in addition to MouseMove) until the button is released, and
+// releasing it does not result in a click. The default is 0, i.e. any movement
+// starts a drag.
+func (a *Application) SetDragThreshold(threshold int) *Application {
+	a.Lock()
+	defer a.Unlock()
+	if threshold < 0 {
+		threshold = 0
+	}
+	a.dragThreshold = threshold
+	return a
+}
+
+// GetDoubleClickInterval returns the maximum time between two clicks for them
+// to be registered as a double click.
+func (a *Application) GetDoubleClickInterval() time.Duration {
+	a.RLock()
+	defer a.RUnlock()
+	if a.doubleClickInterval <= 0 {
+		return DoubleClickInterval
+	}
+	return a.doubleClickInterval
+}
+
+// Stop stops the application, causing Run() to return.
+func (a *Application) Stop() {
+	a.Lock()
+	defer a.Unlock()
+	screen := a.screen
+	if screen == nil {
+		return
+	}
+	a.screen = nil
+	screen.Fini()
+
+	a.send(func(done <-chan struct{}) bool {
+		select {
+		case a.screenReplacement <- nil:
+			return true
+		case <-done:
+			return false
+		}
+	})
+}
+
+// Suspend temporarily suspends the application by exiting terminal UI mode and
+// invoking the provided function "f". When "f" returns, terminal UI mode is
+// entered again and the application resumes.
+//
+// A return value of true indicates that the application was suspended and "f"
+// was called. If false is returned, the application was already suspended,
+// terminal UI mode was not exited, and "f" was not called.
+func (a *Application) Suspend(f func()) bool {
+	a.RLock()
+	screen := a.screen
+	a.RUnlock()
+	if screen == nil {
+		return false // Screen has not yet been initialized.
+	}
+
+	// Enter suspended mode.
+	if err := screen.Suspend(); err != nil {
+		return false // Suspension failed.
+	}
+
+	// Wait for "f" to return.
+	f()
 
 	// If the screen object has changed in the meantime, we need to do more.
 	a.RLock()
@@ -683,15 +1410,82 @@ func (a *Application) Draw(p ...Primitive) *Application {
 // function from a goroutine.
 //
 // It is safe to call this function during queued updates and direct event
-// handling.
+// handling. Calls made while the screen is being drawn (e.g. from a primitive's
+// Draw() function, from the callbacks set with SetBeforeDrawFunc() and
+// SetAfterDrawFunc(), or from another goroutine) return immediately. The screen
+// is then drawn once more after the current draw, no matter how many such
+// calls were made. These calls are reported to the logger (see SetLogger())
+// unless SetConcurrentDraw() was enabled.
 func (a *Application) ForceDraw() *Application {
 	return a.draw()
 }
 
-// draw actually does what Draw() promises to do.
+// SetConcurrentDraw sets whether calls to ForceDraw() which are made while the
+// screen is being drawn are expected. By default (false), they are reported to
+// the logger (see SetLogger()). If set to true, they are not. In both cases,
+// the screen is drawn once more after the current draw. Such calls never
+// deadlock.
+func (a *Application) SetConcurrentDraw(enabled bool) *Application {
+	var value int32
+	if enabled {
+		value = 1
+	}
+	atomic.StoreInt32(&a.concurrentDraw, value)
+	return a
+}
+
+// draw actually does what Draw() promises to do. If the screen is already
+// being drawn, another draw is requested from the running one.
 func (a *Application) draw() *Application {
+	a.drawMutex.Lock()
+	if a.drawing {
+		// We're called while drawing. Acquiring the lock would deadlock if
+		// this is the drawing goroutine.
+		a.redraw = true
+		a.drawMutex.Unlock()
+		if atomic.LoadInt32(&a.concurrentDraw) == 0 {
+			a.logf("draw call while drawing deferred")
+		}
+		return a
+	}
+	a.drawing = true
+	a.drawMutex.Unlock()
+	finished := false
+	defer func() {
+		if !finished {
+			// Drawing panicked. Don't block future draws.
+			a.drawMutex.Lock()
+			a.drawing, a.redraw = false, false
+			a.drawMutex.Unlock()
+		}
+	}()
+
+	for {
+		a.drawScreen()
+		a.drawMutex.Lock()
+		if !a.redraw {
+			a.drawing = false
+			a.drawMutex.Unlock()
+			finished = true
+			return a
+		}
+		a.redraw = false
+		a.drawMutex.Unlock()
+	}
+}
+
+// drawScreen draws the screen once.
+func (a *Application) drawScreen() {
 	a.Lock()
-	defer a.Unlock()
+	var start time.Time // Remains zero if nothing is drawn.
+	defer func() {
+		if !start.IsZero() {
+			a.lastDraw = start
+			a.drawStats.Draws++
+			a.drawStats.totalDuration += time.Since(start)
+		}
+		a.Unlock()
+	}()
 
 	screen := a.screen
 	root := a.root
@@ -701,7 +1495,42 @@ func (a *Application) draw() *Application {
 
 	// Maybe we're not ready yet or not anymore.
 	if screen == nil || root == nil {
-		return a
+		return
+	}
+
+	// Don't draw more often than allowed. The skipped draw is made up for.
+	if a.maxFPS > 0 {
+		interval := time.Second / time.Duration(a.maxFPS)
+		if wait := interval - time.Since(a.lastDraw); wait > 0 {
+			a.drawStats.Skipped++
+			if a.drawTimer == nil {
+				a.drawTimer = time.AfterFunc(wait, func() {
+					a.QueueUpdateDraw(func() {
+						a.Lock()
+						a.drawTimer = nil
+						a.Unlock()
+					})
+				})
+			}
+			return
+		}
+	}
+	start = time.Now()
+
+	// Is the screen large enough?
+	if width, height := screen.Size(); width < a.minWidth || height < a.minHeight {
+		a.tooSmall = true
+		clearScreen(screen, a.screenStyle)
+		screen.HideCursor()
+		message := fmt.Sprintf("Terminal too small (need %dx%d)", a.minWidth, a.minHeight)
+		Print(screen, message, 0, height/2, width, AlignCenter, Styles.PrimaryTextColor)
+		screen.Show()
+		return
+	}
+	if a.tooSmall {
+		// Remove the message.
+		a.tooSmall = false
+		clearScreen(screen, a.screenStyle)
 	}
 
 	// Resize if requested.
@@ -714,12 +1543,62 @@ func (a *Application) draw() *Application {
 	if before != nil {
 		if before(screen) {
 			screen.Show()
-			return a
+			return
 		}
 	}
 
+	// Apply running resize animations.
+	a.applyAnimations()
+
 	// Draw all primitives.
+	if a.drawLowerScreens && len(a.screens) > 1 {
+		for _, lower := range a.screens[:len(a.screens)-1] {
+			if fullscreen {
+				width, height := screen.Size()
+				lower.item.SetRect(0, 0, width, height)
+			}
+			lower.item.Draw(screen)
+			drawShadows(screen, lower.item)
+		}
+	}
 	root.Draw(screen)
+	drawShadows(screen, root)
+
+	// An open context menu, drop-down list, or calendar pop-up is drawn on
+	// top.
+	if menu, ok := a.focus.(*ContextMenu); ok && menu.IsOpen() {
+		menu.Draw(screen)
+	}
+	if dropDown := openDropDown(a.focus); dropDown != nil {
+		dropDown.drawList(screen)
+	} else if calendar := openCalendar(a.focus); calendar != nil {
+		calendar.drawPopup(screen)
+	}
+
+	// Toasts are drawn on top of everything else.
+	if len(a.toasts) > 0 {
+		drawToasts(screen, a.toasts)
+	}
+
+	// A tooltip is drawn next to the mouse pointer.
+	if a.tooltip != nil {
+		a.tooltip.draw(screen, a.tooltipStyle)
+	}
+
+	// The key help overlay hides everything.
+	if a.keyHelp != nil {
+		a.keyHelp.Draw(screen)
+	}
+
+	// Mark the focused primitive.
+	if a.focusIndicator && a.focus != nil {
+		drawFocusIndicator(screen, a.focus, a.focusIndicatorStyle)
+	}
+
+	// Outline the layout.
+	if a.layoutDebug {
+		drawLayoutOutlines(screen, root, 0)
+	}
 
 	// Call after handler if there is one.
 	if after != nil {
@@ -729,19 +1608,300 @@ func (a *Application) draw() *Application {
 	// Sync screen.
 	screen.Show()
 
-	return a
+	// Schedule the next frame of animating primitives.
+	if a.frameTimer == nil && animating(root) {
+		a.frameTimer = time.AfterFunc(animationFrame, func() {
+			a.Lock()
+			a.frameTimer = nil
+			a.Unlock()
+			a.QueueUpdateDraw(func() {})
+		})
+	}
+}
+
+// ScreenCell is the content of a single screen cell as returned by
+// CopyScreenRegion().
+type ScreenCell struct {
+	Rune      rune        // The primary rune.
+	Combining []rune      // Combining runes, if any.
+	Style     tcell.Style // The cell's style.
+	Width     int         // The display width of the cell's content.
+}
+
+// CopyScreenRegion returns the contents of a rectangular region of the current
+// screen, indexed by row, then column. The region is clamped to the screen's
+// bounds. nil is returned if there is no screen or the region is empty.
+//
+// The screen is read as part of the event loop (see QueueUpdate()) so this
+// function is safe to call from other goroutines. It must not be called from
+// the event loop itself (e.g. in a key handler) as it waits for the event loop.
+func (a *Application) CopyScreenRegion(x, y, width, height int) [][]ScreenCell {
+	copyRegion := func() [][]ScreenCell {
+		a.RLock()
+		defer a.RUnlock()
+		if a.screen == nil {
+			return nil
+		}
+
+		// Clamp to screen.
+		screenWidth, screenHeight := a.screen.Size()
+		if x < 0 {
+			width += x
+			x = 0
+		}
+		if y < 0 {
+			height += y
+			y = 0
+		}
+		if x+width > screenWidth {
+			width = screenWidth - x
+		}
+		if y+height > screenHeight {
+			height = screenHeight - y
+		}
+		if width <= 0 || height <= 0 {
+			return nil
+		}
+
+		// Copy cells.
+		cells := make([][]ScreenCell, height)
+		for row := range cells {
+			cells[row] = make([]ScreenCell, width)
+			for column := range cells[row] {
+				mainc, combc, style, w := a.screen.GetContent(x+column, y+row)
+				cells[row][column] = ScreenCell{
+					Rune:      mainc,
+					Combining: combc,
+					Style:     style,
+					Width:     w,
+				}
+			}
+		}
+		return cells
+	}
+
+	// If the application isn't running, we can read the screen directly.
+	a.RLock()
+	running := a.runDone != nil
+	a.RUnlock()
+	if !running || a.runContext.Err() != nil {
+		return copyRegion()
+	}
+
+	result := make(chan [][]ScreenCell, 1)
+	a.QueueUpdate(func() {
+		result <- copyRegion()
+	})
+	select {
+	case cells := <-result:
+		return cells
+	case <-a.runContext.Done():
+		return nil
+	}
+}
+
+// HitResult describes what was found at a screen position by GetHitAt().
+type HitResult struct {
+	// The highest level component found at the position.
+	Primitive Primitive
+
+	// The index of the row at the position, if the component consists of rows.
+	// For a List, this is the item index, for a TreeView, the index of the
+	// visible node, and for a Table, the table row. It is -1 otherwise.
+	Row int
+
+	// The index of the column at the position. This is only set for a Table.
+	// It is -1 otherwise.
+	Column int
 }
 
 // GetComponentAt returns the highest level component at the given coordinates
 // or zero if no component can be found.
+//
+// This function does not modify the screen or any primitives.
 func (a *Application) GetComponentAt(x, y int) *Primitive {
-	return getComponentAtRecursively(a.root, x, y, a)
+	return getComponentAtRecursively(a.root, x, y)
 }
 
-func getComponentAtRecursively(primitive Primitive, x, y int, a *Application) *Primitive {
-  if primitive == nil {
-    return nil
-  }
+// GetHitAt works like GetComponentAt() but also determines the location within
+// the found component, e.g. the List item or the TreeView node at the given
+// coordinates. This is useful to implement custom context menus. nil is
+// returned if no component can be found.
+func (a *Application) GetHitAt(x, y int) *HitResult {
+	found := getComponentAtRecursively(a.root, x, y)
+	if found == nil {
+		return nil
+	}
+
+	hit := &HitResult{Primitive: *found, Row: -1, Column: -1}
+	switch p := hit.Primitive.(type) {
+	case *List:
+		hit.Row = p.indexAtPoint(x, y)
+	case *TreeView:
+		hit.Row = p.nodeIndexAtPoint(x, y)
+	case *Table:
+		if p.InRect(x, y) {
+			hit.Row, hit.Column = p.cellAt(x, y)
+			if hit.Row < 0 {
+				hit.Row = -1
+			}
+		}
+	}
+
+	return hit
+}
+
+// SetLayoutDebug sets whether the outlines of all primitives are drawn on top
+// of the normal screen content, each labeled with the primitive's type. The
+// outlines' colors depend on the primitives' nesting depth. Primitives
+// contained in Flex, Grid, Pages, Frame, Form, and Modal primitives are
+// included. This is meant for debugging layouts. It does not affect the
+// primitives themselves or the handling of mouse events.
+func (a *Application) SetLayoutDebug(debug bool) *Application {
+	a.Lock()
+	defer a.Unlock()
+	a.layoutDebug = debug
+	return a
+}
+
+// layoutDebugColors are the colors of the outlines drawn for
+// SetLayoutDebug(), by nesting depth.
+var layoutDebugColors = []tcell.Color{
+	tcell.ColorRed,
+	tcell.ColorGreen,
+	tcell.ColorYellow,
+	tcell.ColorBlue,
+	tcell.ColorFuchsia,
+	tcell.ColorAqua,
+}
+
+// drawLayoutOutlines draws the outline of the given primitive and, recursively,
+// of the primitives it contains onto the screen. The depth is the nesting
+// depth of the primitive.
+func drawLayoutOutlines(screen tcell.Screen, primitive Primitive, depth int) {
+	if primitive == nil || !primitive.IsVisible() {
+		return
+	}
+	x, y, width, height := primitive.GetRect()
+	if width > 0 && height > 0 {
+		color := layoutDebugColors[depth%len(layoutDebugColors)]
+		setOutline := func(x, y int, r rune) {
+			_, _, style, _ := screen.GetContent(x, y)
+			screen.SetContent(x, y, r, nil, style.Foreground(color))
+		}
+		for index := x + 1; index < x+width-1; index++ {
+			setOutline(index, y, Borders.Horizontal)
+			setOutline(index, y+height-1, Borders.Horizontal)
+		}
+		for index := y + 1; index < y+height-1; index++ {
+			setOutline(x, index, Borders.Vertical)
+			setOutline(x+width-1, index, Borders.Vertical)
+		}
+		setOutline(x, y, Borders.TopLeft)
+		setOutline(x+width-1, y, Borders.TopRight)
+		setOutline(x, y+height-1, Borders.BottomLeft)
+		setOutline(x+width-1, y+height-1, Borders.BottomRight)
+
+		// The label.
+		label := strings.TrimPrefix(fmt.Sprintf("%T", primitive), "*tview.")
+		_, _, style, _ := screen.GetContent(x, y)
+		printWithStyle(screen, Escape(label), x+1, y, 0, width-2, AlignLeft, style.Foreground(color), false)
+	}
+
+	for _, child := range childPrimitives(primitive) {
+		drawLayoutOutlines(screen, child, depth+1)
+	}
+}
+
+// shadowed is implemented by primitives which may have a drop shadow, i.e. by
+// all primitives based on Box.
+type shadowed interface {
+	HasShadow() bool
+	drawShadow(screen tcell.Screen)
+}
+
+// drawShadows draws the drop shadows (see Box.SetShadow()) of the given
+// primitive and, recursively, of the visible primitives it contains.
+func drawShadows(screen tcell.Screen, primitive Primitive) {
+	if primitive == nil || !primitive.IsVisible() {
+		return
+	}
+	if s, ok := primitive.(shadowed); ok && s.HasShadow() {
+		s.drawShadow(screen)
+	}
+	for _, child := range childPrimitives(primitive) {
+		drawShadows(screen, child)
+	}
+}
+
+// childPrimitives returns the primitives directly contained in the given
+// primitive if it is one of the package's container primitives, i.e. Flex,
+// Grid, Pages (visible pages only), Frame, Form, Modal, SplitView, and Tabs.
+func childPrimitives(primitive Primitive) (children []Primitive) {
+	switch p := primitive.(type) {
+	case *Flex:
+		for _, item := range p.items {
+			if item.Item != nil {
+				children = append(children, item.Item)
+			}
+		}
+	case *Grid:
+		for _, item := range p.items {
+			if item.Item != nil && item.visible {
+				children = append(children, item.Item)
+			}
+		}
+	case *Pages:
+		for _, page := range p.pages {
+			if page.Visible {
+				children = append(children, page.Item)
+			}
+		}
+	case *Frame:
+		children = append(children, p.primitive)
+	case *Form:
+		for _, item := range p.items {
+			children = append(children, item)
+		}
+		for _, button := range p.buttons {
+			children = append(children, button)
+		}
+	case *Modal:
+		children = append(children, p.frame)
+	case *SplitView:
+		for _, pane := range []Primitive{p.first, p.second} {
+			if pane != nil {
+				children = append(children, pane)
+			}
+		}
+	case *Tabs:
+		children = append(children, p.pages)
+	}
+	return
+}
+
+// HighlightComponentAt draws the border of the highest level component at the
+// given coordinates onto the screen and returns that component (nil if none
+// can be found). This is meant for debugging layouts. Unlike GetComponentAt(),
+// it modifies the screen. The highlight disappears with the next redraw.
+func (a *Application) HighlightComponentAt(x, y int) *Primitive {
+	a.Lock()
+	defer a.Unlock()
+
+	found := getComponentAtRecursively(a.root, x, y)
+	if found == nil || a.screen == nil {
+		return found
+	}
+	(*found).DrawBorder(true, tcell.StyleDefault, a.screen)
+	a.screen.Show()
+
+	return found
+}
+
+func getComponentAtRecursively(primitive Primitive, x, y int) *Primitive {
+	if primitive == nil {
+		return nil
+	}
 	if !primitive.IsVisible() {
 		return nil
 	}
@@ -749,8 +1909,7 @@ func getComponentAtRecursively(primitive Primitive, x, y int, a *Application) *P
 	flex, isFlex := primitive.(*Flex)
 	if isFlex {
 		for _, child := range flex.items {
-      child.Item.DrawBorder(true, tcell.StyleDefault, a.screen)
-			found := getComponentAtRecursively(child.Item, x, y, a)
+			found := getComponentAtRecursively(child.Item, x, y)
 			if found != nil {
 				return found
 			}
@@ -761,7 +1920,7 @@ func getComponentAtRecursively(primitive Primitive, x, y int, a *Application) *P
 	grid, isGrid := primitive.(*Grid)
 	if isGrid {
 		for _, child := range grid.items {
-			found := getComponentAtRecursively(child.Item, x, y, a)
+			found := getComponentAtRecursively(child.Item, x, y)
 			if found != nil {
 				return found
 			}
@@ -773,7 +1932,7 @@ func getComponentAtRecursively(primitive Primitive, x, y int, a *Application) *P
 	if isPages {
 		for _, page := range pages.pages {
 			if page.Visible {
-				found := getComponentAtRecursively(page.Item, x, y, a)
+				found := getComponentAtRecursively(page.Item, x, y)
 				if found != nil {
 					return found
 				}
@@ -795,6 +1954,19 @@ func getSelfIfCoordinatesMatch(primitive Primitive, x, y int) *Primitive {
 
 	return nil
 }
+
+// clearScreen blanks the screen's buffer with the given style, which also
+// becomes the screen's default style. Unlike screen.Clear(), which makes tcell
+// erase the terminal and write every cell again with the next call to Show(),
+// this only marks cells as changed if they differ from what the terminal
+// currently shows. As the screen is then drawn into the same buffer, Show()
+// writes only the cells which differ from the previous frame and a full
+// redraw, e.g. after a resize, does not flicker.
+func clearScreen(screen tcell.Screen, style tcell.Style) {
+	screen.SetStyle(style)
+	screen.Fill(' ', style)
+}
+
 // Sync forces a full re-sync of the screen buffer with the actual screen during
 // the next event cycle. This is useful for when the terminal screen is
 // corrupted so you may want to offer your users a keyboard shortcut to refresh
@@ -812,9 +1984,14 @@ func (a *Application) Sync() *Application {
 			screen.Sync()
 		},
 	}
-	if a.runContext.Err() == nil {
-		a.updates <- msg
-	}
+	a.send(func(done <-chan struct{}) bool {
+		select {
+		case a.updates <- msg:
+			return true
+		case <-done:
+			return false
+		}
+	})
 	return a
 }
 
@@ -824,7 +2001,8 @@ func (a *Application) Sync() *Application {
 // (and an after-draw-handler will not be called).
 //
 // Note that the screen is not cleared by the application. To clear the screen,
-// you may call screen.Clear().
+// you may call screen.Fill(' ', tcell.StyleDefault). (screen.Clear() erases the
+// entire terminal before the next update, which may cause flicker.)
 //
 // Provide nil to uninstall the callback function.
 func (a *Application) SetBeforeDrawFunc(handler func(screen tcell.Screen) bool) *Application {
@@ -860,27 +2038,659 @@ func (a *Application) GetAfterDrawFunc() func(screen tcell.Screen) {
 	return a.afterDraw
 }
 
+// SetTickInterval sets the interval of a shared clock which redraws the screen
+// periodically while the application is running. Animated primitives and
+// clocks can hook into it with SetTickFunc() instead of running their own
+// timers. A value of 0 (the default) disables the clock.
+//
+// This function must be called before Run() to have an effect.
+func (a *Application) SetTickInterval(interval time.Duration) *Application {
+	a.Lock()
+	defer a.Unlock()
+	a.tickInterval = interval
+	return a
+}
+
+// GetTickInterval returns the interval set with SetTickInterval().
+func (a *Application) GetTickInterval() time.Duration {
+	a.RLock()
+	defer a.RUnlock()
+	return a.tickInterval
+}
+
+// SetTickFunc installs a callback function which is invoked once per tick of
+// the shared clock (see SetTickInterval()), right before the screen is redrawn.
+// It is called from the event loop, so primitives may be modified safely. The
+// callback is only invoked while the application is running and not anymore
+// after Stop() was called.
+//
+// Provide nil to uninstall the callback function.
+func (a *Application) SetTickFunc(handler func(now time.Time)) *Application {
+	a.Lock()
+	defer a.Unlock()
+	a.tick = handler
+	return a
+}
+
+// GetTickFunc returns the callback function installed with SetTickFunc() or nil
+// if none has been installed.
+func (a *Application) GetTickFunc() func(now time.Time) {
+	a.RLock()
+	defer a.RUnlock()
+	return a.tick
+}
+
+// SetIdleFunc installs a callback function which is invoked from the event loop
+// after the user has not generated any input (key, mouse, paste, or resize
+// events) for the duration d. It is invoked repeatedly, every d, until user
+// input resumes. The screen is redrawn after each invocation.
+//
+// Provide a nil function or a non-positive duration to uninstall the callback
+// function. The idle timer is stopped when the application stops.
+func (a *Application) SetIdleFunc(d time.Duration, f func()) *Application {
+	a.Lock()
+	a.idle = f
+	a.idleDuration = d
+	a.Unlock()
+
+	// Let the event loop pick up the change.
+	select {
+	case a.idleReset <- struct{}{}:
+	default:
+	}
+
+	return a
+}
+
+// SetRedrawThrottle sets the minimum time between two consecutive redraws
+// caused by resize events. Terminals often send many resize events while the
+// user resizes the window. When they arrive faster than this, the last event of
+// such a burst is delivered again once the time has passed so that the final
+// size is always drawn. Higher values save work for applications which are
+// expensive to draw, lower values make the layout follow the window size more
+// closely. A value of 0 draws on every resize event. The default is 50
+// milliseconds. Negative values are treated as 0.
+//
+// The value may be changed while the application is running. A pending
+// delivery of the last resize event is rescheduled with the new value.
+func (a *Application) SetRedrawThrottle(throttle time.Duration) *Application {
+	if throttle < 0 {
+		throttle = 0
+	}
+	a.Lock()
+	defer a.Unlock()
+	a.redrawThrottle = throttle
+	if a.redrawTimer != nil && a.redrawTimer.Stop() {
+		a.redrawTimer.Reset(throttle)
+	}
+	return a
+}
+
+// GetRedrawThrottle returns the minimum time between two consecutive redraws
+// caused by resize events, see SetRedrawThrottle().
+func (a *Application) GetRedrawThrottle() time.Duration {
+	a.RLock()
+	defer a.RUnlock()
+	return a.redrawThrottle
+}
+
+// DrawStats contains statistics about the redraws of an application, see
+// Application.GetDrawStats().
+type DrawStats struct {
+	// The number of times the screen was drawn.
+	Draws int
+
+	// The number of draws which were skipped because of the limit set with
+	// Application.SetMaxFPS().
+	Skipped int
+
+	// The average time it took to draw the screen.
+	AverageDuration time.Duration
+
+	// The total time spent drawing the screen.
+	totalDuration time.Duration
+}
+
+// SetMaxFPS limits the number of times the screen is redrawn per second. Draws
+// which are requested sooner after the last draw, e.g. by frequent calls to
+// QueueUpdateDraw(), are skipped. The last skipped draw is performed once
+// enough time has passed so that the screen always shows the latest state. A
+// value of 0 (the default) or less removes the limit.
+//
+// The number of skipped draws can be retrieved with GetDrawStats().
+func (a *Application) SetMaxFPS(fps int) *Application {
+	if fps < 0 {
+		fps = 0
+	}
+	a.Lock()
+	defer a.Unlock()
+	a.maxFPS = fps
+	return a
+}
+
+// GetMaxFPS returns the maximum number of redraws per second set with
+// SetMaxFPS(), 0 if there is no limit.
+func (a *Application) GetMaxFPS() int {
+	a.RLock()
+	defer a.RUnlock()
+	return a.maxFPS
+}
+
+// GetDrawStats returns the number of times the screen was drawn, the number of
+// draws which were skipped because of the limit set with SetMaxFPS(), and the
+// average time a draw took, since the application was created. This helps
+// finding code which requests redraws more often than necessary.
+//
+// This function must not be called while the screen is being drawn, e.g. from
+// a callback set with SetAfterDrawFunc().
+func (a *Application) GetDrawStats() DrawStats {
+	a.RLock()
+	defer a.RUnlock()
+	stats := a.drawStats
+	if stats.Draws > 0 {
+		stats.AverageDuration = stats.totalDuration / time.Duration(stats.Draws)
+	}
+	return stats
+}
+
+// SetPanicHandler installs a function which is called when a panic occurs in
+// the application's event loop, e.g. in an input handler or while drawing. The
+// terminal has already been restored when it is called so the handler may log
+// the panic or print to the terminal. If the handler returns normally, Run()
+// returns a *PanicError wrapping the recovered value instead of panicking
+// again. The application cannot be run again after that.
+//
+// If no handler is installed (the default), Run() restores the terminal and
+// panics again. Provide nil to uninstall the handler.
+func (a *Application) SetPanicHandler(handler func(recovered any)) *Application {
+	a.Lock()
+	defer a.Unlock()
+	a.panicHandler = handler
+	return a
+}
+
+// SetLogger installs a function which receives the application's internal
+// diagnostic messages, e.g. about events which could not be handled. The
+// arguments are the same as for fmt.Printf(). Nothing is ever printed to the
+// terminal directly as that would corrupt the screen. Messages are discarded
+// if no logger is installed (the default).
+//
+// Provide nil to uninstall the logger.
+func (a *Application) SetLogger(logger func(format string, args ...any)) *Application {
+	a.logger.Store(logger)
+	return a
+}
+
+// SetRawEventHook installs a function which receives all events the event loop
+// does not handle itself, for example custom event types posted to the screen
+// or event types added to tcell in the future. Paste events are passed to it if
+// no paste handler was installed with SetOnPasteFunc(). The function is called
+// from the event loop. If it returns true, the event is considered handled and
+// the screen is redrawn. Otherwise, the event is reported to the logger (see
+// SetLogger()).
+//
+// Provide nil to uninstall the hook.
+func (a *Application) SetRawEventHook(hook func(event tcell.Event) (handled bool)) *Application {
+	a.Lock()
+	defer a.Unlock()
+	a.rawEventHook = hook
+	return a
+}
+
+// handleRawEvent passes an event to the hook installed with SetRawEventHook()
+// and redraws the screen if it was handled. It returns whether the event was
+// handled.
+func (a *Application) handleRawEvent(event tcell.Event) bool {
+	a.RLock()
+	hook := a.rawEventHook
+	a.RUnlock()
+	if hook == nil || !hook(event) {
+		return false
+	}
+	a.draw()
+	return true
+}
+
+// logf sends a diagnostic message to the logger installed with SetLogger(), if
+// any.
+func (a *Application) logf(format string, args ...any) {
+	if logger, _ := a.logger.Load().(func(format string, args ...any)); logger != nil {
+		logger(format, args...)
+	}
+}
+
+// Notification severities.
+const (
+	SeverityInfo = iota
+	SeverityWarning
+	SeverityError
+)
+
+// Notification is a transient message for the user, see Application.Notify().
+type Notification struct {
+	Severity int       // One of the Severity constants.
+	Text     string    // The message.
+	Time     time.Time // The time the notification was issued.
+}
+
+// Notify issues a notification with the given severity (one of the Severity
+// constants) and text. The notification is stored with the most recent ones
+// (see GetNotifications()) and passed to the function installed with
+// SetNotifyFunc(). It is up to that function to display the notification, for
+// example in a status bar.
+//
+// This function may be called from any goroutine.
+func (a *Application) Notify(severity int, text string) *Application {
+	notification := Notification{
+		Severity: severity,
+		Text:     text,
+		Time:     time.Now(),
+	}
+
+	a.Lock()
+	if a.maxNotifications > 0 {
+		a.notifications = append(a.notifications, notification)
+		if len(a.notifications) > a.maxNotifications {
+			a.notifications = a.notifications[len(a.notifications)-a.maxNotifications:]
+		}
+	}
+	notify := a.notify
+	a.Unlock()
+
+	if notify != nil {
+		notify(notification)
+	}
+	return a
+}
+
+// SetNotifyFunc installs a callback function which is invoked for every
+// notification issued with Notify(). The function is called from the goroutine
+// which called Notify(). Unless that is the application's event loop (e.g. a
+// key handler), use QueueUpdateDraw() to change primitives in response.
+//
+// Provide nil to uninstall the callback function.
+func (a *Application) SetNotifyFunc(handler func(notification Notification)) *Application {
+	a.Lock()
+	defer a.Unlock()
+	a.notify = handler
+	return a
+}
+
+// GetNotifications returns the most recent notifications, oldest first.
+func (a *Application) GetNotifications() []Notification {
+	a.RLock()
+	defer a.RUnlock()
+	return append([]Notification(nil), a.notifications...)
+}
+
+// SetMaxNotifications sets the number of notifications which are kept by the
+// application. Older notifications are dropped. A value of 0 disables keeping
+// notifications. They are still passed to the function installed with
+// SetNotifyFunc(). The default is DefaultMaxNotifications.
+func (a *Application) SetMaxNotifications(max int) *Application {
+	a.Lock()
+	defer a.Unlock()
+	if max < 0 {
+		max = 0
+	}
+	a.maxNotifications = max
+	if len(a.notifications) > max {
+		a.notifications = a.notifications[len(a.notifications)-max:]
+	}
+	return a
+}
+
+// ClearNotifications removes all stored notifications.
+func (a *Application) ClearNotifications() *Application {
+	a.Lock()
+	defer a.Unlock()
+	a.notifications = nil
+	return a
+}
+
+// ShowContextMenu opens a context menu with the given items with its top-left
+// corner at the given screen position. If the menu doesn't fit onto the screen
+// there, it is flipped to the left of and/or above that position. While it is
+// open, the menu receives all key and mouse events. It is closed when the user
+// selects an item, presses Escape, or clicks outside of it. The focus is then
+// returned to the primitive which had it before. When an item is selected,
+// "onSelect" (if not nil) is called with the item's index.
+//
+// The menu is drawn with the next redraw. The returned menu may be used to
+// customize it further.
+func (a *Application) ShowContextMenu(x, y int, items []MenuItem, onSelect func(index int)) *ContextMenu {
+	menu := NewContextMenu()
+	for index, item := range items {
+		index := index
+		menu.AddItem(item.Label, func(source Primitive) {
+			if onSelect != nil {
+				onSelect(index)
+			}
+		})
+	}
+	menu.Open(x, y, a.GetFocus())
+
+	a.Lock()
+	a.mouseCapturingPrimitive = menu
+	a.Unlock()
+
+	a.SetFocus(menu)
+	return menu
+}
+
+// ShowNotification shows a toast with the given text in the given corner of
+// the screen, on top of the root primitive. The toast does not receive focus
+// and does not block input. It is removed automatically after the duration "d"
+// has passed (a duration of 0 or less keeps it until it is dismissed). Toasts
+// in the same corner are stacked. The returned toast may be used to change its
+// appearance or to dismiss it early, see Toast.Dismiss().
+//
+// Invalid corners are clamped to the nearest valid value, e.g. CornerTopLeft
+// for negative values.
+//
+// This function may be called from any goroutine but the screen is not
+// redrawn. Call Draw() afterwards or use QueueUpdateDraw().
+func (a *Application) ShowNotification(text string, d time.Duration, pos Corner) *Toast {
+	if pos < CornerTopLeft {
+		pos = CornerTopLeft
+	} else if pos > CornerBottomRight {
+		pos = CornerBottomRight
+	}
+	toast := NewToast(text)
+	toast.corner = pos
+	toast.app = a
+
+	a.Lock()
+	a.toasts = append(a.toasts, toast)
+	if d > 0 {
+		toast.timer = time.AfterFunc(d, func() {
+			a.QueueUpdateDraw(func() {
+				a.removeToast(toast)
+			})
+		})
+	}
+	a.Unlock()
+
+	return toast
+}
+
+// DismissNotification removes a toast shown with ShowNotification() before
+// its timeout has passed. Like ShowNotification(), this function does not
+// redraw the screen.
+func (a *Application) DismissNotification(toast *Toast) *Application {
+	a.removeToast(toast)
+	return a
+}
+
+// removeToast removes the given toast from the list of shown toasts and stops
+// its automatic removal.
+func (a *Application) removeToast(toast *Toast) {
+	a.Lock()
+	defer a.Unlock()
+	if toast.timer != nil {
+		toast.timer.Stop()
+		toast.timer = nil
+	}
+	for index, t := range a.toasts {
+		if t == toast {
+			a.toasts = append(a.toasts[:index], a.toasts[index+1:]...)
+			return
+		}
+	}
+}
+
 // SetRoot sets the root primitive for this application. If "fullscreen" is set
 // to true, the root primitive's position will be changed to fill the screen.
 //
 // This function must be called at least once or nothing will be displayed when
 // the application starts.
 //
-// It also calls SetFocus() on the primitive.
+// It also calls SetFocus() on the primitive (or on its first focusable leaf
+// primitive, see SetAutoFocusFirst()).
 func (a *Application) SetRoot(root Primitive, fullscreen bool) *Application {
 	a.Lock()
+	old := a.root
 	a.root = root
 	a.rootFullscreen = fullscreen
+	a.screens = nil
 	if a.screen != nil {
-		a.screen.Clear()
+		clearScreen(a.screen, a.screenStyle)
 	}
+	autoFocusFirst, rootChanged := a.autoFocusFirst, a.rootChanged
 	a.Unlock()
 
+	if rootChanged != nil && old != root {
+		rootChanged(old, root)
+	}
+
+	if autoFocusFirst && a.focusFirstLeaf(root) {
+		return a
+	}
 	a.SetFocus(root)
 
 	return a
 }
 
+// GetRoot returns the root primitive of this application, i.e. the primitive
+// set with SetRoot() or the top screen of the screen stack (see PushScreen()).
+// It returns nil if no root primitive was set.
+func (a *Application) GetRoot() Primitive {
+	a.RLock()
+	defer a.RUnlock()
+	return a.root
+}
+
+// SetRootChangedFunc sets a handler which is called whenever the root
+// primitive is replaced, either with SetRoot() or by changes to the screen
+// stack. The handler receives the previous and the new root primitive, either
+// of which may be nil. It is called after the change, before the focus is set
+// on the new root primitive, and without the application being locked, so it
+// may call the application's functions.
+//
+// Provide nil to uninstall the handler.
+func (a *Application) SetRootChangedFunc(handler func(old, new Primitive)) *Application {
+	a.Lock()
+	defer a.Unlock()
+	a.rootChanged = handler
+	return a
+}
+
+// SetTheme sets the theme from which primitives take their colors. Primitives
+// of the current root primitive, of the screens pushed with PushScreen(), and
+// of hidden pages are changed from the colors of the theme previously set with
+// this function (or of Styles if there was none) to the corresponding colors
+// of the new theme. Colors and styles which were set explicitly with the
+// primitives' setters are kept. Setters which change only parts of a style,
+// e.g. List.SetSelectedTextColor(), keep the entire style. The screen is
+// redrawn.
+//
+// Note that primitives take their colors from the package-level Styles
+// variable when they are created. This function sets Styles to the new theme,
+// i.e. it changes the colors of all primitives created afterwards, including
+// those of other applications.
+//
+// Primitives created by the application, e.g. tooltips, take their colors from
+// the theme, too, unless their style was set explicitly (see
+// SetTooltipStyle()). Provide a preset such as &DarkTheme or &LightTheme or a
+// theme of your own. The theme is copied.
+func (a *Application) SetTheme(theme *Theme) *Application {
+	a.Lock()
+	from := Styles
+	if a.theme != nil {
+		from = *a.theme
+	}
+	to := *theme
+	a.theme = &to
+	Styles = to
+	if !a.tooltipStyleSet {
+		a.tooltipStyle = tcell.StyleDefault.Foreground(to.PrimaryTextColor).Background(to.MoreContrastBackgroundColor)
+	}
+	roots := []Primitive{a.root}
+	for _, screen := range a.screens {
+		if screen.item != a.root {
+			roots = append(roots, screen.item)
+		}
+	}
+	a.Unlock()
+
+	for _, root := range roots {
+		applyTheme(root, &from, &to)
+	}
+	return a.Draw()
+}
+
+// SetAutoFocusFirst sets whether SetRoot() focuses the first focusable leaf
+// primitive of the new root instead of the root itself. The primitive tree is
+// traversed depth-first in the order of the container items, skipping
+// invisible primitives. Primitives for which the callback installed with
+// SetBeforeFocusFunc() returns false are skipped, too. If no leaf accepts the
+// focus, the root primitive is focused as usual.
+func (a *Application) SetAutoFocusFirst(autoFocusFirst bool) *Application {
+	a.Lock()
+	defer a.Unlock()
+	a.autoFocusFirst = autoFocusFirst
+	return a
+}
+
+// focusFirstLeaf sets the focus on the first visible leaf primitive below the
+// given primitive which accepts it. Returns whether such a primitive was found.
+func (a *Application) focusFirstLeaf(primitive Primitive) bool {
+	if primitive == nil || !primitive.IsVisible() {
+		return false
+	}
+	children := childPrimitives(primitive)
+	if len(children) == 0 {
+		return a.setFocus(primitive)
+	}
+	for _, child := range children {
+		if a.focusFirstLeaf(child) {
+			return true
+		}
+	}
+	return false
+}
+
+// stackedScreen is one screen on the application's screen stack.
+type stackedScreen struct {
+	item  Primitive // The screen's primitive.
+	focus Primitive // The primitive which had focus when the screen was covered.
+}
+
+// PushScreen puts a new screen on top of the application's screen stack and
+// makes it the root primitive, keeping the fullscreen setting of SetRoot().
+// The previous root primitive becomes the bottom of the stack if the stack was
+// empty. Only the top screen receives key and mouse events. The focus of the
+// covered screen is saved and restored when the new screen is popped with
+// PopScreen(). The new screen receives focus.
+//
+// Calling SetRoot() clears the screen stack.
+func (a *Application) PushScreen(p Primitive) *Application {
+	a.Lock()
+	if len(a.screens) == 0 && a.root != nil {
+		a.screens = append(a.screens, &stackedScreen{item: a.root})
+	}
+	if len(a.screens) > 0 {
+		a.screens[len(a.screens)-1].focus = a.focus
+	}
+	a.screens = append(a.screens, &stackedScreen{item: p})
+	a.Unlock()
+
+	a.setTopScreen(p, p)
+	return a
+}
+
+// PopScreen removes the top screen from the application's screen stack. The
+// screen below it becomes the root primitive again and the focus it had when
+// it was covered is restored. The bottom screen is never removed. The popped
+// screen is returned, or nil if there was nothing to pop.
+func (a *Application) PopScreen() Primitive {
+	a.Lock()
+	if len(a.screens) < 2 {
+		a.Unlock()
+		return nil
+	}
+	popped := a.screens[len(a.screens)-1].item
+	a.screens = a.screens[:len(a.screens)-1]
+	top := a.screens[len(a.screens)-1]
+	focus := top.focus
+	top.focus = nil
+	if focus == nil {
+		focus = top.item
+	}
+	a.Unlock()
+
+	a.setTopScreen(top.item, focus)
+	return popped
+}
+
+// ReplaceTopScreen replaces the top screen of the application's screen stack
+// with the given primitive which also receives focus. If the stack is empty,
+// this is the same as PushScreen().
+func (a *Application) ReplaceTopScreen(p Primitive) *Application {
+	a.Lock()
+	if len(a.screens) == 0 {
+		a.Unlock()
+		return a.PushScreen(p)
+	}
+	a.screens[len(a.screens)-1] = &stackedScreen{item: p}
+	a.Unlock()
+
+	a.setTopScreen(p, p)
+	return a
+}
+
+// GetScreenCount returns the number of screens on the application's screen
+// stack, including the bottom screen. It is 0 if PushScreen() was never called
+// (or SetRoot() was called since).
+func (a *Application) GetScreenCount() int {
+	a.RLock()
+	defer a.RUnlock()
+	return len(a.screens)
+}
+
+// SetDrawLowerScreens sets whether the screens below the top screen of the
+// application's screen stack are drawn (from bottom to top) before the top
+// screen. This is useful if the top screen does not cover the entire screen,
+// e.g. for dialogs or during transitions. Lower screens never receive key or
+// mouse events. By default, only the top screen is drawn.
+func (a *Application) SetDrawLowerScreens(draw bool) *Application {
+	a.Lock()
+	defer a.Unlock()
+	a.drawLowerScreens = draw
+	return a
+}
+
+// setTopScreen makes the given primitive the root primitive and sets the focus
+// to the given primitive.
+func (a *Application) setTopScreen(root, focus Primitive) {
+	a.Lock()
+	old := a.root
+	a.root = root
+	if a.screen != nil {
+		clearScreen(a.screen, a.screenStyle)
+	}
+	rootChanged := a.rootChanged
+	a.Unlock()
+
+	if rootChanged != nil && old != root {
+		rootChanged(old, root)
+	}
+
+	a.SetFocus(focus)
+}
+
+// SetMinTerminalSize sets the minimum screen size required to draw the root
+// primitive. While the screen is smaller than this in either dimension, the
+// root primitive is not drawn and a message stating the required size is shown
+// instead. Normal drawing resumes as soon as the screen is large enough. A value
+// of 0 (the default) means that there is no minimum for that dimension.
+func (a *Application) SetMinTerminalSize(width, height int) *Application {
+	a.Lock()
+	defer a.Unlock()
+	a.minWidth, a.minHeight = width, height
+	return a
+}
+
 // ResizeToFullScreen resizes the given primitive such that it fills the entire
 // screen.
 func (a *Application) ResizeToFullScreen(p Primitive) *Application {
@@ -898,16 +2708,23 @@ func (a *Application) ResizeToFullScreen(p Primitive) *Application {
 // Blur() will be called on the previously focused primitive. Focus() will be
 // called on the new primitive.
 func (a *Application) SetFocus(p Primitive) *Application {
+	a.setFocus(p)
+	return a
+}
+
+// setFocus implements SetFocus(). It returns false if the callback installed
+// with SetBeforeFocusFunc() rejected the new focus.
+func (a *Application) setFocus(p Primitive) bool {
 	a.Lock()
 	if a.beforeFocus != nil {
 		a.Unlock()
 		ok := a.beforeFocus(p)
 		if !ok {
-			return a
+			return false
 		}
 		a.Lock()
 	}
-	if a.focus != nil {
+	if a.focus != nil && a.focus != p {
 		a.focus.Blur()
 	}
 	a.focus = p
@@ -926,6 +2743,20 @@ func (a *Application) SetFocus(p Primitive) *Application {
 		})
 	}
 
+	return true
+}
+
+// SetFocusWithoutScroll is like SetFocus() but scrolling containers (Grid and
+// Form) keep their scroll position instead of scrolling to reveal the newly
+// focused primitive. This lasts until the primitive which ends up with the
+// focus loses it again. It is useful when moving the focus programmatically,
+// e.g. during batch updates. To disable scrolling to a primitive permanently,
+// see Box.SetScrollOnFocus().
+func (a *Application) SetFocusWithoutScroll(p Primitive) *Application {
+	a.SetFocus(p)
+	if focus, ok := a.GetFocus().(interface{ suppressScrollOnFocus() }); ok {
+		focus.suppressScrollOnFocus()
+	}
 	return a
 }
 
@@ -961,6 +2792,45 @@ func (a *Application) SetOnPasteFunc(handler func(screen tcell.Screen, ev *tcell
 	a.onPaste = handler
 }
 
+// SetClipboard sets the clipboard used by primitives such as [InputField] and
+// [TextArea] for cut, copy, and paste operations. Use this to plug in the
+// operating system's clipboard. The clipboard is shared by all primitives of
+// all applications. Providing nil restores an empty in-memory clipboard.
+func (a *Application) SetClipboard(c Clipboard) *Application {
+	setClipboard(c)
+	return a
+}
+
+// GetClipboard returns the clipboard used by primitives for cut, copy, and
+// paste operations.
+func (a *Application) GetClipboard() Clipboard {
+	return getClipboard()
+}
+
+// SetOnScreenReplacedFunc installs a callback function which is invoked after
+// the application's screen was replaced with SetScreen() or SwapScreen() while
+// the application is running. It is called from the event loop once the new
+// screen has been initialized and drawn, receiving both the old and the new
+// screen. This is the place to reapply screen settings such as the cursor
+// style or to invalidate screen-dependent caches. The screen is redrawn
+// afterwards.
+//
+// Provide nil to uninstall the callback function.
+func (a *Application) SetOnScreenReplacedFunc(handler func(oldScreen, newScreen tcell.Screen)) *Application {
+	a.Lock()
+	defer a.Unlock()
+	a.onScreenReplaced = handler
+	return a
+}
+
+// GetOnScreenReplacedFunc returns the callback function installed with
+// SetOnScreenReplacedFunc() or nil if none has been installed.
+func (a *Application) GetOnScreenReplacedFunc() func(oldScreen, newScreen tcell.Screen) {
+	a.RLock()
+	defer a.RUnlock()
+	return a.onScreenReplaced
+}
+
 // QueueUpdate is used to synchronize access to primitives from non-main
 // goroutines. The provided function will be executed as part of the event loop
 // and thus will not cause race conditions with other such update functions or
@@ -975,11 +2845,7 @@ func (a *Application) SetOnPasteFunc(handler func(screen tcell.Screen, ev *tcell
 func (a *Application) QueueUpdate(f func()) *Application {
 	defer func() {
 		if err := recover(); err != nil {
-			if err == nil {
-				fmt.Println(errorx.WithStack(nil))
-			}
-			d := 2
-			d++
+			a.logf("panic while queueing update: %v\n%s", err, debug.Stack())
 			panic(err)
 		}
 	}()
@@ -989,10 +2855,14 @@ func (a *Application) QueueUpdate(f func()) *Application {
 		f:    f,
 		done: ch,
 	}
-	if a.runContext.Err() == nil {
-		a.updates <- msg
-		// <-ch
-	}
+	a.send(func(done <-chan struct{}) bool {
+		select {
+		case a.updates <- msg:
+			return true
+		case <-done:
+			return false
+		}
+	})
 	return a
 }
 
@@ -1006,13 +2876,19 @@ func (a *Application) QueueUpdateDraw(f func()) *Application {
 	return a
 }
 
-// QueueEvent sends an event to the Application event loop.
+// QueueEvent sends an event to the Application event loop. It returns
+// ErrApplicationClosed if the application is shutting down or was closed, in
+// which case the event is discarded. It is safe to call this function
+// concurrently with Close().
 //
 // It is not recommended for event to be nil.
-func (a *Application) QueueEvent(event tcell.Event) *Application {
-	// check to see if the Application.Run is still valid
-	if a.runContext.Err() == nil {
-		a.events <- event
-	}
-	return a
+func (a *Application) QueueEvent(event tcell.Event) error {
+	return a.send(func(done <-chan struct{}) bool {
+		select {
+		case a.events <- event:
+			return true
+		case <-done:
+			return false
+		}
+	})
 }
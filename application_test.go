@@ -0,0 +1,1237 @@
+package tview
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestSetOnScreenReplacedFunc(t *testing.T) {
+	app := NewApplication().SetRoot(NewBox(), true)
+	replaced := make(chan [2]tcell.Screen, 1)
+	app.SetOnScreenReplacedFunc(func(oldScreen, newScreen tcell.Screen) {
+		replaced <- [2]tcell.Screen{oldScreen, newScreen}
+	})
+	oldScreen := startApp(t, app, 20, 5)
+
+	newScreen := tcell.NewSimulationScreen("UTF-8")
+	app.SetScreen(newScreen)
+	select {
+	case screens := <-replaced:
+		if screens[0] != oldScreen {
+			t.Errorf("old screen is %v, expected %v", screens[0], oldScreen)
+		}
+		if screens[1] != newScreen {
+			t.Errorf("new screen is %v, expected %v", screens[1], newScreen)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("screen replaced callback was not called")
+	}
+}
+
+func TestRunSimulation(t *testing.T) {
+	field := NewInputField().SetLabel("Name: ")
+	app := NewApplication().SetRoot(field, true)
+	var before string
+	contents, err := app.RunSimulation(12, 2, func(sim tcell.SimulationScreen) {
+		// The root has already been drawn.
+		before = screenLine(sim, 0)
+		for _, r := range "Ann" {
+			sim.InjectKey(tcell.KeyRune, r, tcell.ModNone)
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if before != "Name:       " {
+		t.Errorf("row 0 was %q when the script started, expected the label", before)
+	}
+
+	// The returned contents include all injected events.
+	if len(contents) != 12*2 {
+		t.Fatalf("received %d cells, expected %d", len(contents), 12*2)
+	}
+	var line strings.Builder
+	for _, cell := range contents[:12] {
+		line.WriteRune(cell.Runes[0])
+	}
+	if line.String() != "Name: Ann   " {
+		t.Errorf("row 0 is %q, expected the typed text", line.String())
+	}
+	if text := field.GetText(); text != "Ann" {
+		t.Errorf("text is %q, expected %q", text, "Ann")
+	}
+
+	// The application cannot be simulated while it has a screen.
+	app = NewApplication().SetScreen(newTestScreen(t, 12, 2))
+	if _, err := app.RunSimulation(12, 2, nil); !errors.Is(err, ErrApplicationRunning) {
+		t.Errorf("RunSimulation() returned %v for an application with a screen", err)
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	app := NewApplication().SetRoot(NewTextView().SetText("日本e\u0301x"), true)
+
+	// Without a screen, there is nothing to capture.
+	if runes, styles := app.Snapshot(); runes != nil || styles != nil {
+		t.Error("Snapshot() returned contents without a screen")
+	}
+	if text := app.SnapshotString(); text != "" {
+		t.Errorf("SnapshotString() returned %q without a screen", text)
+	}
+
+	// Before the first draw, the screen is empty.
+	app.SetScreen(newTestScreen(t, 8, 2))
+	if text := app.SnapshotString(); text != "        \n        " {
+		t.Errorf("SnapshotString() returned %q before the first draw", text)
+	}
+	app.ForceDraw()
+
+	// Wide characters are included once, combining characters are kept.
+	if text := app.SnapshotString(); text != "日本e\u0301x  \n        " {
+		t.Errorf("SnapshotString() returned %q", text)
+	}
+
+	// Snapshot() has one element per cell, without combining characters.
+	runes, styles := app.Snapshot()
+	if len(runes) != 2 || len(styles) != 2 {
+		t.Fatalf("Snapshot() returned %d and %d rows, expected 2", len(runes), len(styles))
+	}
+	for y := range runes {
+		if len(runes[y]) != 8 || len(styles[y]) != 8 {
+			t.Fatalf("Snapshot() returned %d and %d cells in row %d, expected 8", len(runes[y]), len(styles[y]), y)
+		}
+	}
+	for x, expected := range map[int]rune{0: '日', 2: '本', 4: 'e', 5: 'x', 6: ' '} {
+		if runes[0][x] != expected {
+			t.Errorf("cell %d contains %q, expected %q", x, runes[0][x], expected)
+		}
+	}
+	if fg, _, _ := styles[0][4].Decompose(); fg != Styles.PrimaryTextColor {
+		t.Errorf("cell 4 has foreground %v, expected %v", fg, Styles.PrimaryTextColor)
+	}
+}
+
+func TestSetTickFunc(t *testing.T) {
+	const interval = 20 * time.Millisecond
+	ticks := make(chan time.Time, 100)
+	app := NewApplication().SetRoot(NewBox(), true).
+		SetTickInterval(interval).
+		SetTickFunc(func(now time.Time) {
+			ticks <- now
+		})
+	startApp(t, app, 20, 5)
+
+	// The tick fires repeatedly, at the configured interval.
+	var previous time.Time
+	for count := 0; count < 5; count++ {
+		select {
+		case now := <-ticks:
+			if !previous.IsZero() && now.Sub(previous) < interval/2 {
+				t.Errorf("ticks %s apart, expected about %s", now.Sub(previous), interval)
+			}
+			previous = now
+		case <-time.After(5 * time.Second):
+			t.Fatalf("tick %d did not fire", count)
+		}
+	}
+
+	// No more ticks after Stop().
+	app.Stop()
+	time.Sleep(2 * interval)
+	for len(ticks) > 0 {
+		<-ticks
+	}
+	select {
+	case <-ticks:
+		t.Error("tick fired after Stop()")
+	case <-time.After(5 * interval):
+	}
+}
+
+func TestCopyScreenRegion(t *testing.T) {
+	text := NewTextView().SetText("abcdef\nghijkl\nmnopqr")
+	app := NewApplication().SetRoot(text, true)
+	startApp(t, app, 10, 4)
+
+	cells := app.CopyScreenRegion(2, 1, 3, 2)
+	if len(cells) != 2 {
+		t.Fatalf("got %d rows, expected 2", len(cells))
+	}
+	for row, expected := range []string{"ijk", "opq"} {
+		var line []rune
+		for _, cell := range cells[row] {
+			line = append(line, cell.Rune)
+		}
+		if string(line) != expected {
+			t.Errorf("row %d is %q, expected %q", row, string(line), expected)
+		}
+	}
+
+	// The region is clamped to the screen.
+	cells = app.CopyScreenRegion(-2, 2, 20, 5)
+	if len(cells) != 2 || len(cells[0]) != 10 {
+		t.Errorf("clamped region is %d rows high, expected 2x10", len(cells))
+	}
+	if cells := app.CopyScreenRegion(10, 0, 5, 5); cells != nil {
+		t.Errorf("region outside the screen returned %d rows, expected none", len(cells))
+	}
+}
+
+func TestQueueEventDuringClose(t *testing.T) {
+	app := NewApplication()
+
+	// Queue events from many goroutines until the application is closed.
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+	for index := 0; index < cap(errs); index++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				if err := app.QueueEvent(tcell.NewEventInterrupt(nil)); err != nil {
+					errs <- err
+					return
+				}
+			}
+		}()
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	if err := app.Close(); err != nil {
+		t.Fatal(err)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if !errors.Is(err, ErrApplicationClosed) {
+			t.Errorf("QueueEvent() returned %v, expected %v", err, ErrApplicationClosed)
+		}
+	}
+	if err := app.QueueEvent(tcell.NewEventInterrupt(nil)); !errors.Is(err, ErrApplicationClosed) {
+		t.Errorf("QueueEvent() after Close() returned %v, expected %v", err, ErrApplicationClosed)
+	}
+}
+
+// testEvent is an event type the application doesn't know.
+type testEvent struct {
+	tcell.EventTime
+}
+
+func TestSetPanicHandler(t *testing.T) {
+	var (
+		recovered []any
+		wg        sync.WaitGroup
+	)
+	stop := make(chan struct{})
+	app := NewApplication().SetRoot(NewBox(), true)
+	app.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		// Another goroutine keeps accessing the application while the panic
+		// is handled.
+		started := make(chan struct{})
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+					app.QueueUpdateDraw(func() {})
+					app.Stop()
+					select {
+					case <-started:
+					default:
+						close(started)
+					}
+				}
+			}
+		}()
+		<-started
+		panic("input")
+	}).SetPanicHandler(func(p any) {
+		recovered = append(recovered, p)
+	})
+	screen := newTestScreen(t, 20, 5)
+	app.SetScreen(screen)
+	runErr := make(chan error, 1)
+	go func() {
+		runErr <- app.Run()
+	}()
+	waitForEvents(t, screen)
+
+	screen.InjectKey(tcell.KeyRune, 'a', tcell.ModNone)
+	var err error
+	select {
+	case err = <-runErr:
+	case <-time.After(5 * time.Second):
+		t.Fatal("application did not stop after the panic")
+	}
+	close(stop)
+	wg.Wait()
+
+	var panicErr *PanicError
+	if !errors.As(err, &panicErr) || panicErr.Recovered != "input" {
+		t.Errorf("Run() returned %v, expected a panic error", err)
+	}
+	if len(recovered) != 1 || recovered[0] != "input" {
+		t.Errorf("panic handler received %v, expected the panic once", recovered)
+	}
+	if runes, _ := app.Snapshot(); runes != nil {
+		t.Error("application still has a screen after the panic")
+	}
+}
+
+func TestSetLogger(t *testing.T) {
+	// Capture everything printed to stdout.
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = writer
+	defer func() {
+		os.Stdout = stdout
+	}()
+	printed := make(chan []byte, 1)
+	go func() {
+		output, _ := io.ReadAll(reader)
+		printed <- output
+	}()
+
+	var (
+		mutex    sync.Mutex
+		messages []string
+	)
+	app := NewApplication().SetRoot(NewBox(), true).
+		SetLogger(func(format string, args ...any) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			messages = append(messages, fmt.Sprintf(format, args...))
+		})
+	screen := startApp(t, app, 20, 5)
+	screen.PostEventWait(&testEvent{})
+	screen.PostEventWait(tcell.NewEventPaste(true))
+	waitForEvents(t, screen)
+
+	mutex.Lock()
+	if len(messages) != 2 {
+		t.Errorf("logger received %q, expected two messages", messages)
+	}
+	mutex.Unlock()
+
+	os.Stdout = stdout
+	writer.Close()
+	if output := <-printed; len(output) > 0 {
+		t.Errorf("printed %q to stdout, expected nothing", output)
+	}
+}
+
+func TestNotify(t *testing.T) {
+	app := NewApplication().SetMaxNotifications(3)
+	var received []Notification
+	app.SetNotifyFunc(func(notification Notification) {
+		received = append(received, notification)
+	})
+	for index := 0; index < 5; index++ {
+		app.Notify(SeverityWarning, fmt.Sprintf("message %d", index))
+	}
+
+	// The callback receives every notification.
+	if len(received) != 5 {
+		t.Fatalf("callback received %d notifications, expected 5", len(received))
+	}
+	for index, notification := range received {
+		if expected := fmt.Sprintf("message %d", index); notification.Text != expected {
+			t.Errorf("notification %d is %q, expected %q", index, notification.Text, expected)
+		}
+		if notification.Severity != SeverityWarning || notification.Time.IsZero() {
+			t.Errorf("notification %d has severity %d and time %v", index, notification.Severity, notification.Time)
+		}
+	}
+
+	// Only the most recent ones are kept, oldest first.
+	checkNotifications := func(expected ...string) {
+		t.Helper()
+		var texts []string
+		for _, notification := range app.GetNotifications() {
+			texts = append(texts, notification.Text)
+		}
+		if fmt.Sprint(texts) != fmt.Sprint(expected) {
+			t.Errorf("kept notifications %q, expected %q", texts, expected)
+		}
+	}
+	checkNotifications("message 2", "message 3", "message 4")
+
+	// Lowering the maximum drops the oldest ones.
+	app.SetMaxNotifications(2)
+	checkNotifications("message 3", "message 4")
+
+	// A maximum of 0 keeps nothing but still calls the callback.
+	app.SetMaxNotifications(0).Notify(SeverityError, "dropped")
+	checkNotifications()
+	if len(received) != 6 || received[5].Text != "dropped" {
+		t.Errorf("callback did not receive the notification while none are kept")
+	}
+
+	// Without a callback, notifications are still kept.
+	app.SetNotifyFunc(nil).SetMaxNotifications(1).Notify(SeverityInfo, "kept")
+	checkNotifications("kept")
+	if len(received) != 6 {
+		t.Errorf("removed callback was called")
+	}
+}
+
+func TestSetRawEventHook(t *testing.T) {
+	var (
+		mutex    sync.Mutex
+		hooked   []tcell.Event
+		messages int
+	)
+	app := NewApplication().SetRoot(NewBox(), true).
+		SetLogger(func(format string, args ...any) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			messages++
+		}).
+		SetRawEventHook(func(event tcell.Event) bool {
+			mutex.Lock()
+			defer mutex.Unlock()
+			if _, ok := event.(*testEvent); !ok {
+				return false
+			}
+			hooked = append(hooked, event)
+			return true
+		})
+	screen := startApp(t, app, 20, 5)
+
+	// The custom event reaches the hook, which handles it.
+	event := &testEvent{}
+	screen.PostEventWait(event)
+	waitForEvents(t, screen)
+	mutex.Lock()
+	if len(hooked) != 1 || hooked[0] != event {
+		t.Errorf("hook received %v, expected the custom event", hooked)
+	}
+	if messages != 0 {
+		t.Errorf("handled event was reported to the logger")
+	}
+	mutex.Unlock()
+
+	// Without the hook, the event is only logged.
+	app.SetRawEventHook(nil)
+	screen.PostEventWait(&testEvent{})
+	waitForEvents(t, screen)
+	mutex.Lock()
+	if len(hooked) != 1 {
+		t.Errorf("removed hook received %d events, expected 1", len(hooked))
+	}
+	if messages != 1 {
+		t.Errorf("logger received %d messages, expected 1", messages)
+	}
+	mutex.Unlock()
+}
+
+func TestSetMinTerminalSize(t *testing.T) {
+	app := NewApplication().
+		SetRoot(NewTextView().SetText("content"), true).
+		SetMinTerminalSize(30, 3)
+	screen := startApp(t, app, 40, 5)
+	resize := func(width, height int) {
+		t.Helper()
+		screen.SetSize(width, height)
+		screen.PostEventWait(tcell.NewEventResize(width, height))
+		waitForEvents(t, screen)
+		app.ForceDraw()
+	}
+	contains := func(text string) bool {
+		_, height := screen.Size()
+		for y := 0; y < height; y++ {
+			if strings.Contains(screenLine(screen, y), text) {
+				return true
+			}
+		}
+		return false
+	}
+
+	// Large enough: the root is drawn.
+	if !contains("content") || contains("too small") {
+		t.Error("root was not drawn on a large enough screen")
+	}
+
+	// Too narrow or too low: only the message is shown.
+	for _, size := range [][2]int{{29, 5}, {40, 2}} {
+		resize(size[0], size[1])
+		if contains("content") {
+			t.Errorf("root was drawn on a %dx%d screen", size[0], size[1])
+		}
+		if !contains("Terminal too small") {
+			t.Errorf("no message on a %dx%d screen", size[0], size[1])
+		}
+	}
+
+	// Exactly the minimum: the root is drawn again and the message is gone.
+	resize(30, 3)
+	if !contains("content") || contains("too small") {
+		t.Error("root was not drawn again on a screen of the minimum size")
+	}
+}
+
+func TestDrawFromAfterDrawFunc(t *testing.T) {
+	var (
+		mutex    sync.Mutex
+		messages []string
+		draws    int
+	)
+	app := NewApplication().SetRoot(NewBox(), true)
+	app.SetLogger(func(format string, args ...any) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		messages = append(messages, fmt.Sprintf(format, args...))
+	}).SetAfterDrawFunc(func(screen tcell.Screen) {
+		mutex.Lock()
+		draws++
+		first := draws == 1
+		mutex.Unlock()
+		if first {
+			app.Draw()      // Queued, draws again later.
+			app.ForceDraw() // Nested, draws again after this draw.
+		}
+	})
+	screen := startApp(t, app, 20, 5)
+
+	// The event loop is still responsive and the queued draw happened before
+	// the following update.
+	waitForEvents(t, screen)
+	done := make(chan struct{})
+	app.QueueUpdate(func() { close(done) })
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("queued update was not executed")
+	}
+	mutex.Lock()
+	defer mutex.Unlock()
+	if draws < 3 {
+		t.Errorf("screen was drawn %d times, expected the nested and the queued draw, too", draws)
+	}
+	var nested bool
+	for _, message := range messages {
+		if message == "draw call while drawing deferred" {
+			nested = true
+		}
+	}
+	if !nested {
+		t.Errorf("logger received %q, expected the nested draw call to be reported", messages)
+	}
+}
+
+func TestSetConcurrentDraw(t *testing.T) {
+	var (
+		mutex    sync.Mutex
+		messages int
+	)
+	draws := make(chan int, 10)
+	app := NewApplication().SetRoot(NewBox(), true).SetConcurrentDraw(true)
+	var count int
+	app.SetLogger(func(format string, args ...any) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		messages++
+	}).SetAfterDrawFunc(func(screen tcell.Screen) {
+		count++ // Only accessed while drawing.
+		if count > cap(draws) {
+			return
+		}
+		draws <- count
+		if count == 1 {
+			// Nested, made up for with a single draw after this one.
+			app.ForceDraw()
+			app.ForceDraw()
+			app.ForceDraw()
+		}
+	})
+	screen := startApp(t, app, 20, 5)
+
+	for draw := 1; draw <= 2; draw++ {
+		select {
+		case <-draws:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("draw %d did not happen", draw)
+		}
+	}
+	waitForEvents(t, screen)
+	select {
+	case draw := <-draws:
+		t.Errorf("draw %d happened, expected nested draw calls to be coalesced", draw)
+	default:
+	}
+	mutex.Lock()
+	defer mutex.Unlock()
+	if messages > 0 {
+		t.Errorf("nested draw call was reported to the logger")
+	}
+}
+
+func TestConcurrentForceDraw(t *testing.T) {
+	var block, count int32
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	app := NewApplication().SetRoot(NewBox(), true)
+	app.SetAfterDrawFunc(func(screen tcell.Screen) {
+		atomic.AddInt32(&count, 1)
+		if atomic.CompareAndSwapInt32(&block, 1, 0) {
+			// Block this draw until another goroutine asked for one.
+			close(entered)
+			<-release
+		}
+	})
+	screen := startApp(t, app, 20, 5)
+
+	atomic.StoreInt32(&block, 1)
+	atomic.StoreInt32(&count, 0)
+	app.QueueUpdateDraw(func() {})
+	select {
+	case <-entered:
+	case <-time.After(5 * time.Second):
+		t.Fatal("screen was not drawn")
+	}
+
+	// A draw requested from another goroutine while drawing is not lost.
+	done := make(chan struct{})
+	go func() {
+		app.ForceDraw()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("concurrent draw call blocked")
+	}
+	close(release)
+	waitForEvents(t, screen)
+	if n := atomic.LoadInt32(&count); n != 2 {
+		t.Errorf("screen was drawn %d times, expected 2", n)
+	}
+}
+
+func TestDrawAfterPanic(t *testing.T) {
+	var draws int
+	panicking := true
+	app := NewApplication().SetRoot(NewBox(), true).SetScreen(newTestScreen(t, 20, 5))
+	app.SetBeforeDrawFunc(func(screen tcell.Screen) bool {
+		if panicking {
+			panicking = false
+			panic("draw")
+		}
+		return false
+	}).SetAfterDrawFunc(func(screen tcell.Screen) {
+		draws++
+	})
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("drawing did not panic")
+			}
+		}()
+		app.ForceDraw()
+	}()
+	app.ForceDraw()
+	if draws != 1 {
+		t.Errorf("screen was drawn %d times after a panic, expected 1", draws)
+	}
+}
+
+func TestSetLayoutDebug(t *testing.T) {
+	inner := NewBox()
+	flex := NewFlex().
+		AddItem(NewBox(), 0, 1, false).
+		AddItem(inner, 0, 1, false)
+	flex.SetBorder(true)
+	app := NewApplication().SetRoot(flex, true)
+	screen := startApp(t, app, 20, 5)
+
+	// Without debugging, no outlines are drawn.
+	if line := []rune(screenLine(screen, 2)); strings.ContainsRune(string(line[1:19]), Borders.Vertical) {
+		t.Errorf("row 2 is %q without layout debugging, expected no outlines", string(line))
+	}
+
+	// With debugging, the root and its items are outlined.
+	app.SetLayoutDebug(true).ForceDraw()
+	mainc, _, style, _ := screen.GetContent(0, 0)
+	if mainc != Borders.TopLeft {
+		t.Errorf("root corner is %q, expected %q", mainc, Borders.TopLeft)
+	}
+	if fg, _, _ := style.Decompose(); fg != layoutDebugColors[0] {
+		t.Errorf("root outline color is %v, expected %v", fg, layoutDebugColors[0])
+	}
+	mainc, _, style, _ = screen.GetContent(10, 2)
+	if mainc != Borders.Vertical {
+		t.Errorf("left edge of the second item is %q, expected %q", mainc, Borders.Vertical)
+	}
+	if fg, _, _ := style.Decompose(); fg != layoutDebugColors[1] {
+		t.Errorf("item outline color is %v, expected %v", fg, layoutDebugColors[1])
+	}
+
+	// Hidden primitives are not outlined.
+	inner.SetVisible(false)
+	app.ForceDraw()
+	if mainc, _, _, _ := screen.GetContent(10, 2); mainc == Borders.Vertical {
+		t.Error("hidden item was outlined")
+	}
+}
+
+// snapshot returns the application's screen contents, read from within a
+// queued update.
+func snapshot(t *testing.T, app *Application) string {
+	t.Helper()
+	text := make(chan string, 1)
+	app.QueueUpdate(func() { text <- app.SnapshotString() })
+	select {
+	case s := <-text:
+		return s
+	case <-time.After(5 * time.Second):
+		t.Fatal("queued update was not executed")
+	}
+	return ""
+}
+
+func TestGetComponentAt(t *testing.T) {
+	left, right := NewBox(), NewBox()
+	right.SetBorder(true).SetBorderVisible(false)
+	flex := NewFlex().
+		AddItem(left, 0, 1, false).
+		AddItem(right, 0, 1, false)
+	app := NewApplication().SetRoot(flex, true)
+	startApp(t, app, 20, 5)
+
+	before := snapshot(t, app)
+	for _, test := range []struct {
+		x, y     int
+		expected Primitive
+	}{
+		{x: 2, y: 2, expected: left},
+		{x: 12, y: 2, expected: right},
+		{x: 10, y: 0, expected: right},
+		{x: 30, y: 2, expected: nil},
+	} {
+		found := app.GetComponentAt(test.x, test.y)
+		if test.expected == nil {
+			if found != nil {
+				t.Errorf("found %T at %d/%d, expected nothing", *found, test.x, test.y)
+			}
+			continue
+		}
+		if found == nil || *found != test.expected {
+			t.Errorf("did not find the expected box at %d/%d", test.x, test.y)
+		}
+	}
+	if after := snapshot(t, app); after != before {
+		t.Errorf("screen changed from\n%s\nto\n%s", before, after)
+	}
+}
+
+func TestHighlightComponentAt(t *testing.T) {
+	box := NewBox()
+	box.SetBorder(true).SetBorderVisible(false)
+	app := NewApplication().SetRoot(box, true)
+	screen := startApp(t, app, 20, 5)
+
+	corner := func() rune {
+		var r rune
+		done := make(chan struct{})
+		app.QueueUpdate(func() {
+			r, _, _, _ = screen.GetContent(0, 0)
+			close(done)
+		})
+		<-done
+		return r
+	}
+	if r := corner(); r != ' ' {
+		t.Fatalf("top left corner is %q before highlighting, expected an invisible border", r)
+	}
+
+	// The highlight is drawn immediately.
+	found := app.HighlightComponentAt(5, 2)
+	if found == nil || *found != box {
+		t.Fatal("did not find the box")
+	}
+	if r := corner(); r != Borders.TopLeft {
+		t.Errorf("top left corner is %q after highlighting, expected %q", r, Borders.TopLeft)
+	}
+
+	// The next redraw removes it.
+	app.Draw()
+	waitForEvents(t, screen)
+	if r := corner(); r != ' ' {
+		t.Errorf("top left corner is %q after redrawing, expected the highlight to be gone", r)
+	}
+}
+
+func TestPushPopScreen(t *testing.T) {
+	first, second := NewInputField(), NewInputField()
+	base := NewFlex().
+		AddItem(first, 1, 0, true).
+		AddItem(second, 1, 0, false)
+	app := NewApplication().SetRoot(base, true).SetFocus(second)
+
+	// The pushed screen becomes the root and receives focus.
+	dialog := NewButton("OK")
+	app.PushScreen(dialog)
+	if root := app.GetRoot(); root != dialog {
+		t.Errorf("root is %v after PushScreen(), expected the pushed screen", root)
+	}
+	if focus := app.GetFocus(); focus != dialog {
+		t.Errorf("focus is on %v after PushScreen(), expected the pushed screen", focus)
+	}
+
+	// Popping restores the previous screen and its focus.
+	if popped := app.PopScreen(); popped != dialog {
+		t.Errorf("PopScreen() returned %v, expected the pushed screen", popped)
+	}
+	if root := app.GetRoot(); root != base {
+		t.Errorf("root is %v after PopScreen(), expected the base screen", root)
+	}
+	if focus := app.GetFocus(); focus != second {
+		t.Errorf("focus is on %v after PopScreen(), expected the previously focused field", focus)
+	}
+
+	// The bottom screen is never popped.
+	if popped := app.PopScreen(); popped != nil {
+		t.Errorf("PopScreen() on the bottom screen returned %v, expected nil", popped)
+	}
+	if root := app.GetRoot(); root != base {
+		t.Errorf("root is %v after popping the bottom screen, expected the base screen", root)
+	}
+}
+
+func TestSetAutoFocusFirst(t *testing.T) {
+	hidden, first, second := NewInputField(), NewInputField(), NewInputField()
+	hidden.SetVisible(false)
+	root := NewFlex().
+		AddItem(hidden, 1, 0, false).
+		AddItem(NewFlex().
+			AddItem(first, 1, 0, false).
+			AddItem(second, 1, 0, false), 0, 1, false)
+
+	// By default, the root itself is focused.
+	app := NewApplication().SetRoot(root, true)
+	if focus := app.GetFocus(); focus != root {
+		t.Errorf("focus is on %v, expected the root", focus)
+	}
+
+	// With the option, the first visible field is focused.
+	app.SetAutoFocusFirst(true).SetRoot(root, true)
+	if focus := app.GetFocus(); focus != first {
+		t.Errorf("focus is on %v, expected the first visible field", focus)
+	}
+
+	// Fields rejected by the before-focus callback are skipped.
+	app.SetBeforeFocusFunc(func(p Primitive) bool {
+		return p != first
+	})
+	app.SetRoot(root, true)
+	if focus := app.GetFocus(); focus != second {
+		t.Errorf("focus is on %v, expected the second field", focus)
+	}
+}
+
+func TestShowNotificationInvalidCorner(t *testing.T) {
+	app := NewApplication().SetRoot(NewBox(), true)
+	screen := startApp(t, app, 20, 6)
+
+	// Invalid corners are clamped instead of panicking.
+	var low, high *Toast
+	app.QueueUpdateDraw(func() {
+		low = app.ShowNotification("low", 0, Corner(-1))
+		high = app.ShowNotification("high", 0, Corner(7))
+	})
+	waitForEvents(t, screen)
+	done := make(chan struct{})
+	app.QueueUpdate(func() {
+		defer close(done)
+		if x, y, _, _ := low.GetRect(); x != 0 || y != 0 {
+			t.Errorf("toast with a negative corner is at %d,%d, expected the top-left corner", x, y)
+		}
+		if x, y, width, height := high.GetRect(); x+width != 20 || y+height != 6 {
+			t.Errorf("toast with a large corner is at %d,%d, expected the bottom-right corner", x, y)
+		}
+	})
+	<-done
+}
+
+func TestDismissNotificationConcurrently(t *testing.T) {
+	app := NewApplication().SetRoot(NewBox(), true)
+	startApp(t, app, 20, 6)
+
+	// Dismissing toasts while their timers fire must not race.
+	var wg sync.WaitGroup
+	for index := 0; index < 10; index++ {
+		toast := app.ShowNotification("message", time.Millisecond, CornerTopRight)
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			toast.Dismiss()
+		}()
+		go func() {
+			defer wg.Done()
+			app.DismissNotification(toast)
+		}()
+	}
+	wg.Wait()
+
+	app.RLock()
+	count := len(app.toasts)
+	app.RUnlock()
+	if count != 0 {
+		t.Errorf("%d toasts are still shown, expected none", count)
+	}
+}
+
+func TestTooltipTimerReuse(t *testing.T) {
+	box := NewBox().SetTooltip("tip")
+	app := NewApplication().SetRoot(box, true).SetTooltipDelay(50 * time.Millisecond)
+	screen := startApp(t, app, 20, 6)
+
+	// Mouse motion reschedules the same timer.
+	var timer *time.Timer
+	for x := 0; x < 10; x++ {
+		screen.PostEvent(tcell.NewEventMouse(x, 2, tcell.ButtonNone, 0))
+		waitForEvents(t, screen)
+		done := make(chan struct{})
+		app.QueueUpdate(func() {
+			defer close(done)
+			if app.tooltipTimer == nil {
+				t.Error("no tooltip timer after mouse motion")
+			} else if timer != nil && app.tooltipTimer != timer {
+				t.Errorf("mouse motion %d created a new tooltip timer", x)
+			}
+			timer = app.tooltipTimer
+		})
+		<-done
+	}
+
+	// The tooltip of the last position is shown after the delay.
+	time.Sleep(100 * time.Millisecond)
+	waitForEvents(t, screen)
+	done := make(chan struct{})
+	app.QueueUpdate(func() {
+		defer close(done)
+		if app.tooltip == nil || app.tooltip.x != 9 || app.tooltip.y != 2 {
+			t.Errorf("tooltip is %v, expected it at 9,2", app.tooltip)
+		}
+	})
+	<-done
+}
+
+func TestSetTheme(t *testing.T) {
+	t.Cleanup(func() { Styles = DarkTheme })
+	Styles = DarkTheme
+
+	// Explicit colors are kept even if they equal colors of the old theme.
+	box := NewBox().SetBorderColor(tcell.ColorWhite)
+	checkbox := NewCheckbox()
+	button := NewButton("OK").SetLabelColor(tcell.ColorWhite)
+	dropDown := NewDropDown()
+	textView := NewTextView()
+	table := NewTable().
+		SetCell(0, 0, NewTableCell("themed")).
+		SetCell(0, 1, NewTableCell("set").SetTextColor(tcell.ColorWhite)).
+		SetCell(0, 2, &TableCell{Color: tcell.ColorRed})
+	node := NewTreeNode("node").SetColor(tcell.ColorWhite)
+	themed := NewTreeNode("themed")
+	node.AddChild(themed)
+	tree := NewTreeView().SetRoot(node)
+	flex := NewFlex().
+		AddItem(box, 1, 0, false).
+		AddItem(checkbox, 1, 0, false).
+		AddItem(button, 1, 0, false).
+		AddItem(dropDown, 1, 0, false).
+		AddItem(textView, 1, 0, false).
+		AddItem(table, 1, 0, false).
+		AddItem(tree, 2, 0, false)
+	app := NewApplication().SetRoot(flex, true)
+	app.SetTheme(&LightTheme)
+
+	if box.borderColor != tcell.ColorWhite || box.titleColor != LightTheme.TitleColor {
+		t.Errorf("box border and title colors are %v and %v, expected white and %v", box.borderColor, box.titleColor, LightTheme.TitleColor)
+	}
+
+	// Blue is both the contrast background and the inverse text color of the
+	// dark theme, green is both the tertiary text and the more contrasting
+	// background color. The field's role decides.
+	if checkbox.fieldBackgroundColor != LightTheme.ContrastBackgroundColor {
+		t.Errorf("checkbox field background is %v, expected %v", checkbox.fieldBackgroundColor, LightTheme.ContrastBackgroundColor)
+	}
+	if _, bg, _ := textView.currentMatchStyle.Decompose(); bg != LightTheme.TertiaryTextColor {
+		t.Errorf("current match background is %v, expected %v", bg, LightTheme.TertiaryTextColor)
+	}
+	if button.labelColor != tcell.ColorWhite || button.labelColorActivated != LightTheme.InverseTextColor || button.backgroundColor != LightTheme.ContrastBackgroundColor {
+		t.Errorf("button colors are %v, %v, and %v", button.labelColor, button.labelColorActivated, button.backgroundColor)
+	}
+	if dropDown.list.backgroundColor != LightTheme.MoreContrastBackgroundColor {
+		t.Errorf("drop-down list background is %v, expected %v", dropDown.list.backgroundColor, LightTheme.MoreContrastBackgroundColor)
+	}
+
+	for column, expected := range []tcell.Color{LightTheme.PrimaryTextColor, tcell.ColorWhite, tcell.ColorRed} {
+		if color := table.GetCell(0, column).Color; color != expected {
+			t.Errorf("cell %d has color %v, expected %v", column, color, expected)
+		}
+	}
+	if node.color != tcell.ColorWhite || themed.color != LightTheme.PrimaryTextColor {
+		t.Errorf("tree node colors are %v and %v, expected white and %v", node.color, themed.color, LightTheme.PrimaryTextColor)
+	}
+
+	// The border style follows the border color unless it was set.
+	if fg, bg, _ := box.borderStyle.Decompose(); fg != DarkTheme.BorderColor || bg != LightTheme.PrimitiveBackgroundColor {
+		t.Errorf("box border style has colors %v and %v, expected %v and %v", fg, bg, DarkTheme.BorderColor, LightTheme.PrimitiveBackgroundColor)
+	}
+	if fg, _, _ := checkbox.borderStyle.Decompose(); fg != LightTheme.BorderColor {
+		t.Errorf("checkbox border style has color %v, expected %v", fg, LightTheme.BorderColor)
+	}
+
+	// Another application's theme does not affect the colors this
+	// application changes from.
+	NewApplication().SetTheme(&DarkTheme)
+	custom := LightTheme
+	custom.PrimaryTextColor = tcell.ColorOrange
+	app.SetTheme(&custom)
+	if color := table.GetCell(0, 0).Color; color != tcell.ColorOrange {
+		t.Errorf("cell 0 has color %v, expected %v", color, tcell.ColorOrange)
+	}
+}
+
+func TestCalendarPopup(t *testing.T) {
+	calendar := NewCalendar().
+		SetLabel("Date ").
+		SetDate(time.Date(2024, time.March, 15, 0, 0, 0, 0, time.UTC))
+	shadowed := NewBox()
+	shadowed.SetShadow(true)
+	grid := NewGrid().SetRows(1, 1, 0).
+		AddItem(calendar, 0, 0, 1, 1, 0, 0, true).
+		AddItem(shadowed, 1, 0, 1, 1, 0, 0, false).
+		AddItem(NewTextView().SetText(strings.Repeat("x", 30)), 2, 0, 1, 1, 0, 0, false)
+	app := NewApplication().SetRoot(grid, true)
+	screen := startApp(t, app, 30, 12)
+
+	// The month grid is drawn on top of the primitives below the calendar,
+	// including their shadows.
+	if line := screenLine(screen, 1); !strings.Contains(line, "March 2024") {
+		t.Errorf("row below the date is %q, expected the month grid", line)
+	}
+	if line := screenLine(screen, 3); strings.Contains(line, "x") {
+		t.Errorf("row %q of the month grid is covered by the text view", line)
+	}
+	_, _, style, _ := screen.GetContent(6, 2)
+	if _, _, attr := style.Decompose(); attr&tcell.AttrDim != 0 {
+		t.Error("month grid is covered by a shadow")
+	}
+
+	// It receives mouse events before the primitives below it.
+	screen.InjectMouse(5+calendarWidth-1, 1, tcell.Button1, 0)
+	screen.InjectMouse(5+calendarWidth-1, 1, tcell.ButtonNone, 0)
+	waitForEvents(t, screen)
+	done := make(chan struct{})
+	app.QueueUpdate(func() {
+		defer close(done)
+		if month := calendar.GetDate().Month(); month != time.April {
+			t.Errorf("clicking the arrow moved to %v, expected April", month)
+		}
+	})
+	<-done
+}
+
+func TestResizeBurst(t *testing.T) {
+	box := NewBox().SetBorder(true)
+	app := NewApplication().SetRoot(box, true).SetRedrawThrottle(50 * time.Millisecond)
+	screen := startApp(t, app, 20, 5)
+
+	// Resize events arrive faster than the throttle.
+	draws := app.GetDrawStats().Draws
+	width, height := 20, 5
+	for index := 0; index < 10; index++ {
+		width, height = width+2, height+1
+		screen.SetSize(width, height)
+		screen.PostEvent(tcell.NewEventResize(width, height))
+	}
+	waitForEvents(t, screen)
+
+	// The last size is drawn once the throttle has passed.
+	time.Sleep(150 * time.Millisecond)
+	waitForEvents(t, screen)
+	done := make(chan struct{})
+	app.QueueUpdate(func() {
+		defer close(done)
+		if x, y, w, h := box.GetRect(); x != 0 || y != 0 || w != width || h != height {
+			t.Errorf("root is at %d,%d with size %dx%d, expected %dx%d", x, y, w, h, width, height)
+		}
+	})
+	<-done
+	if count := app.GetDrawStats().Draws - draws; count > 3 {
+		t.Errorf("a burst of 10 resize events caused %d draws, expected them to be coalesced", count)
+	}
+	if line := []rune(screenLine(screen, height-1)); line[0] != Borders.BottomLeft || line[width-1] != Borders.BottomRight {
+		t.Errorf("last row is %q, expected the bottom border of the final size", string(line))
+	}
+}
+
+// countingTty is a tcell.Tty of a fixed size which counts the bytes written to
+// it instead of writing them to a terminal.
+type countingTty struct {
+	width, height int
+	mutex         sync.Mutex
+	written       int
+	drained       chan struct{}
+}
+
+func (c *countingTty) Start() error {
+	c.drained = make(chan struct{})
+	return nil
+}
+
+func (c *countingTty) Stop() error {
+	return nil
+}
+
+func (c *countingTty) Drain() error {
+	close(c.drained)
+	return nil
+}
+
+func (c *countingTty) NotifyResize(cb func()) {}
+
+func (c *countingTty) WindowSize() (int, int, error) {
+	return c.width, c.height, nil
+}
+
+func (c *countingTty) Read(p []byte) (int, error) {
+	<-c.drained // There is no input.
+	return 0, nil
+}
+
+func (c *countingTty) Write(p []byte) (int, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.written += len(p)
+	return len(p), nil
+}
+
+func (c *countingTty) Close() error {
+	return nil
+}
+
+// reset returns the number of bytes written so far and starts counting anew.
+func (c *countingTty) reset() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	written := c.written
+	c.written = 0
+	return written
+}
+
+// newTtyScreen returns an initialized xterm screen of the given size which
+// writes to a countingTty.
+func newTtyScreen(tb testing.TB, width, height int) (tcell.Screen, *countingTty) {
+	tb.Helper()
+	tb.Setenv("LC_ALL", "en_US.UTF-8")
+	ti, err := tcell.LookupTerminfo("xterm")
+	if err != nil {
+		tb.Skip(err)
+	}
+	tty := &countingTty{width: width, height: height}
+	screen, err := tcell.NewTerminfoScreenFromTtyTerminfo(tty, ti)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	if err := screen.Init(); err != nil {
+		tb.Fatal(err)
+	}
+	tb.Cleanup(screen.Fini)
+	return screen, tty
+}
+
+// redrawBytes draws the given primitive after blanking the screen with the
+// given function and returns the number of bytes sent to the terminal.
+func redrawBytes(screen tcell.Screen, tty *countingTty, root Primitive, blank func()) int {
+	blank()
+	width, height := screen.Size()
+	root.SetRect(0, 0, width, height)
+	root.Draw(screen)
+	tty.reset()
+	screen.Show()
+	return tty.reset()
+}
+
+func TestClearScreenWrites(t *testing.T) {
+	screen, tty := newTtyScreen(t, 40, 10)
+	root := NewTextView().SetText(strings.Repeat("Some text which fills the screen. ", 12))
+	fill := func() { clearScreen(screen, tcell.StyleDefault) }
+
+	// Redrawing the same content only writes the cells which changed, i.e.
+	// none, while screen.Clear() writes all of them again. The screen looks
+	// the same either way.
+	redrawBytes(screen, tty, root, fill)
+	filled := redrawBytes(screen, tty, root, fill)
+	var lines []string
+	for y := 0; y < 10; y++ {
+		lines = append(lines, screenLine(screen, y))
+	}
+	cleared := redrawBytes(screen, tty, root, screen.Clear)
+	if filled*10 > cleared {
+		t.Errorf("redraw after clearScreen() wrote %d bytes, after Clear() %d, expected much fewer", filled, cleared)
+	}
+	for y, expected := range lines {
+		if line := screenLine(screen, y); line != expected {
+			t.Errorf("row %d is %q after Clear(), %q after clearScreen()", y, line, expected)
+		}
+	}
+}
+
+func TestSetScreenStyle(t *testing.T) {
+	style := tcell.StyleDefault.Background(tcell.ColorNavy)
+	box := NewBox()
+	box.SetRect(0, 0, 5, 2)
+	app := NewApplication().SetRoot(box, false)
+	screen := startApp(t, app, 20, 5)
+	app.SetScreenStyle(style)
+	waitForEvents(t, screen)
+
+	// The cells outside of the root primitive have the screen style.
+	done := make(chan struct{})
+	app.QueueUpdate(func() {
+		defer close(done)
+		if _, _, cellStyle, _ := screen.GetContent(10, 3); cellStyle != style {
+			t.Errorf("screen cell has style %v, expected %v", cellStyle, style)
+		}
+	})
+	<-done
+}
+
+func BenchmarkClearScreen(b *testing.B) {
+	screen, tty := newTtyScreen(b, 80, 24)
+	root := NewTextView().SetText(strings.Repeat("Some text which fills the screen. ", 60))
+	for _, blank := range []struct {
+		name string
+		f    func()
+	}{
+		{"Fill", func() { clearScreen(screen, tcell.StyleDefault) }},
+		{"Clear", screen.Clear},
+	} {
+		b.Run(blank.name, func(b *testing.B) {
+			var written int
+			for n := 0; n < b.N; n++ {
+				written += redrawBytes(screen, tty, root, blank.f)
+			}
+			b.ReportMetric(float64(written)/float64(b.N), "bytes/op")
+		})
+	}
+}
@@ -60,6 +60,15 @@ type Box struct {
 	// The alignment of the title.
 	titleAlign int
 
+	// An optional second title and its alignment, e.g. for a status shown
+	// opposite the title. Only visible if there is a border, too.
+	secondaryTitle      string
+	secondaryTitleAlign int
+
+	// Whether or not the titles are drawn on the bottom edge of the border
+	// instead of the top edge.
+	titleOnBottom bool
+
 	// Provides a way to find out if this box has focus. We always go through
 	// this interface because it may be overridden by implementing classes.
 	focus Focusable
@@ -77,6 +86,13 @@ type Box struct {
 	mouseHandler func(event *tcell.EventMouse) bool
 	mouseCapture func(action MouseAction, event *tcell.EventMouse) (MouseAction, *tcell.EventMouse)
 
+	// Optional functions which are called when the mouse pointer enters or
+	// leaves the box's rectangle.
+	mouseEnter, mouseLeave func()
+
+	// The text shown when the mouse pointer rests on the box.
+	tooltip string
+
 	// An optional function which is called before the box is drawn.
 	draw func(screen tcell.Screen, x, y, width, height int) (int, int, int, int)
   evented  EventedFunc
@@ -95,6 +111,40 @@ type Box struct {
 	onPaste      func([]rune)
 	focusManager *FocusManager
 	animating    bool
+
+	// An optional context menu which is opened when the box is right-clicked.
+	contextMenu *ContextMenu
+
+	// The primitive which embeds this box, as last provided to
+	// DrawForSubclass(). nil if the box hasn't been drawn yet.
+	subclass Primitive
+
+	// Whether or not a drop shadow is drawn to the bottom-right of the box.
+	shadow bool
+
+	// The style applied to the cells covered by the shadow.
+	shadowStyle tcell.Style
+
+	// An optional pattern of runes repeated across the inner rect and its
+	// style.
+	backgroundPattern      []rune
+	backgroundPatternStyle tcell.Style
+
+	// The colors of an optional background gradient across the inner rect
+	// (tcell.ColorDefault if there is none) and whether it runs from top to
+	// bottom instead of from left to right.
+	gradientFrom, gradientTo tcell.Color
+	gradientVertical         bool
+
+	// Whether or not containers refrain from scrolling to reveal this box
+	// while it has focus, permanently or until it loses focus (see
+	// Application.SetFocusWithoutScroll()).
+	noScrollOnFocus, scrollSuppressed bool
+
+	// The colors and styles of this primitive (or of the primitive embedding
+	// this box) which were set explicitly and are kept by
+	// Application.SetTheme().
+	overrides themeOverrides
 }
 
 // NewBox returns a Box without a border.
@@ -118,6 +168,7 @@ func NewBox() *Box {
 		borderVisible:           true,
 		borderStyles:            &Borders,
 		animating:               false,
+		shadowStyle:             tcell.StyleDefault.Foreground(tcell.ColorBlack).Background(tcell.ColorBlack).Dim(true),
 		nextFocusableComponents: make(map[FocusDirection][]Primitive),
 	}
 
@@ -144,6 +195,25 @@ func (b *Box) SetBorderPadding(top, bottom, left, right int) *Box {
 	return b
 }
 
+// SetPadding sets the number of blank cells between the box's border (or its
+// edge if it has no border) and its content on each side. The inner rectangle
+// returned by GetInnerRect(), which primitives embedding the box draw their
+// content into, is inset accordingly. The call is ignored if any of the values
+// is negative.
+//
+// This is the same as SetBorderPadding() except for the validation.
+func (b *Box) SetPadding(top, bottom, left, right int) *Box {
+	if top < 0 || bottom < 0 || left < 0 || right < 0 {
+		return b
+	}
+	return b.SetBorderPadding(top, bottom, left, right)
+}
+
+// GetPadding returns the box's padding, see SetPadding().
+func (b *Box) GetPadding() (top, bottom, left, right int) {
+	return b.paddingTop, b.paddingBottom, b.paddingLeft, b.paddingRight
+}
+
 // SetVisible sets whether the Box should be drawn onto the screen.
 func (b *Box) SetVisible(visible bool) {
 	b.visible = visible
@@ -160,16 +230,39 @@ func (b *Box) GetRect() (int, int, int, int) {
 	return b.x, b.y, b.width, b.height
 }
 
-// SetOnFocus sets the handler that gets called when Focus() gets called.
+// SetOnFocus sets the handler that gets called when Focus() gets called. It
+// is the same as SetFocusFunc().
 func (b *Box) SetOnFocus(handler func()) {
 	b.onFocus = handler
 }
 
-// SetOnBlur sets the handler that gets called when Blur() gets called.
+// SetOnBlur sets the handler that gets called when Blur() gets called. It is
+// the same as SetBlurFunc().
 func (b *Box) SetOnBlur(handler func()) {
 	b.onBlur = handler
 }
 
+// SetFocusFunc sets a handler which is called when the box receives focus. It
+// is called once when the box goes from not having focus to having focus, not
+// when the focus is set on it again while it already has it. Containers which
+// hand their focus on to one of their items (e.g. Flex or Form) don't receive
+// focus themselves, so set the handler on the items instead.
+//
+// Provide nil to remove the handler.
+func (b *Box) SetFocusFunc(handler func()) *Box {
+	b.onFocus = handler
+	return b
+}
+
+// SetBlurFunc sets a handler which is called when the box loses focus. It is
+// called once when the box goes from having focus to not having focus.
+//
+// Provide nil to remove the handler.
+func (b *Box) SetBlurFunc(handler func()) *Box {
+	b.onBlur = handler
+	return b
+}
+
 // GetInnerRect returns the position of the inner rectangle (x, y, width,
 // height), without the border and without any padding. Width and height values
 // will clamp to 0 and thus never be negative.
@@ -334,10 +427,39 @@ func (b *Box) WrapMouseHandler(
 		if event != nil && mouseHandler != nil {
 			consumed, capture = mouseHandler(action, event, setFocus)
 		}
+
+		// Open the context menu on right-clicks not handled otherwise.
+		if !consumed && event != nil && action == MouseRightClick && b.contextMenu != nil && b.InRect(event.Position()) {
+			var source Primitive = b
+			if b.subclass != nil {
+				source = b.subclass
+			}
+			x, y := event.Position()
+			b.contextMenu.Open(x, y, source)
+			setFocus(b.contextMenu)
+			consumed, capture = true, b.contextMenu
+		}
 		return
 	}
 }
 
+// SetContextMenu attaches a context menu to this box which is opened at the
+// mouse cursor when the user right-clicks the box (or any primitive embedding
+// it). The menu's actions receive the clicked primitive. Right-clicks are only
+// handled this way if the primitive doesn't handle them itself.
+//
+// Provide nil to remove the context menu.
+func (b *Box) SetContextMenu(menu *ContextMenu) *Box {
+	b.contextMenu = menu
+	return b
+}
+
+// GetContextMenu returns the context menu attached with SetContextMenu() or nil
+// if there is none.
+func (b *Box) GetContextMenu() *ContextMenu {
+	return b.contextMenu
+}
+
 // MouseHandler returns nil.
 func (b *Box) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
 	return b.WrapMouseHandler(
@@ -366,6 +488,48 @@ func (b *Box) SetMouseCapture(
 	return b
 }
 
+// SetMouseEnterFunc sets a function which is called when the mouse pointer
+// enters the box's rectangle, e.g. to highlight the box while the pointer
+// hovers over it. The function is called from the application's event loop.
+// The screen is redrawn afterwards.
+//
+// Hovering is tracked by walking down from the application's root through the
+// package's container primitives (Flex, Grid, Pages, Frame, Form, Modal,
+// SplitView, and Tabs). Boxes nested in other primitives, e.g. custom
+// containers, don't receive these events.
+func (b *Box) SetMouseEnterFunc(handler func()) *Box {
+	b.mouseEnter = handler
+	return b
+}
+
+// SetMouseLeaveFunc sets a function which is called when the mouse pointer
+// leaves the box's rectangle after it entered it (see SetMouseEnterFunc()).
+func (b *Box) SetMouseLeaveFunc(handler func()) *Box {
+	b.mouseLeave = handler
+	return b
+}
+
+// SetTooltip sets a text which the application shows next to the mouse pointer
+// when it rests on the box for a short time (see
+// Application.SetTooltipDelay()). The text may contain color tags and
+// newlines. An empty text (the default) disables the tooltip. If boxes are
+// nested, the tooltip of the innermost box is shown.
+func (b *Box) SetTooltip(text string) *Box {
+	b.tooltip = text
+	return b
+}
+
+// GetTooltip returns the text set with SetTooltip().
+func (b *Box) GetTooltip() string {
+	return b.tooltip
+}
+
+// hoverFuncs returns the functions set with SetMouseEnterFunc() and
+// SetMouseLeaveFunc().
+func (b *Box) hoverFuncs() (enter, leave func()) {
+	return b.mouseEnter, b.mouseLeave
+}
+
 // InRect returns true if the given coordinate is within the bounds of the box's
 // rectangle.
 func (b *Box) InRect(x, y int) bool {
@@ -382,9 +546,78 @@ func (b *Box) GetMouseCapture() func(action MouseAction, event *tcell.EventMouse
 // SetBackgroundColor sets the box's background color.
 func (b *Box) SetBackgroundColor(color tcell.Color) *Box {
 	b.backgroundColor = color
+	b.overrides.set(&b.backgroundColor)
+	return b
+}
+
+// SetBackgroundPattern fills the box's inner rect with the given runes,
+// repeated from left to right in every row, before any content is drawn. The
+// pattern starts at the top-left corner of the inner rect so it does not move
+// with the box's content. If the style's background color is
+// tcell.ColorDefault, the box's background color (or gradient, see
+// SetBackgroundGradient()) is used. Provide an empty pattern to remove it.
+func (b *Box) SetBackgroundPattern(pattern []rune, style tcell.Style) *Box {
+	b.backgroundPattern = pattern
+	b.backgroundPatternStyle = style
 	return b
 }
 
+// SetBackgroundGradient fills the box's inner rect with a background color
+// gradient from one color to another, from left to right or, if "vertical" is
+// true, from top to bottom. It is drawn before any content and below the
+// background pattern, if any (see SetBackgroundPattern()). The colors must be
+// RGB colors or named colors. Provide tcell.ColorDefault for either color to
+// remove the gradient.
+func (b *Box) SetBackgroundGradient(from, to tcell.Color, vertical bool) *Box {
+	b.gradientFrom, b.gradientTo, b.gradientVertical = from, to, vertical
+	return b
+}
+
+// drawBackgroundPattern draws the background gradient and pattern, if any,
+// into the box's inner rect.
+func (b *Box) drawBackgroundPattern(screen tcell.Screen) {
+	gradient := b.gradientFrom != tcell.ColorDefault && b.gradientTo != tcell.ColorDefault
+	if !gradient && len(b.backgroundPattern) == 0 {
+		return
+	}
+	x, y, width, height := b.GetInnerRect()
+	fromR, fromG, fromB := b.gradientFrom.RGB()
+	toR, toG, toB := b.gradientTo.RGB()
+	if fromR < 0 || toR < 0 {
+		gradient = false
+	}
+	steps := width - 1
+	if b.gradientVertical {
+		steps = height - 1
+	}
+	background := b.backgroundColor
+	for row := 0; row < height; row++ {
+		for column := 0; column < width; column++ {
+			if gradient {
+				position := column
+				if b.gradientVertical {
+					position = row
+				}
+				blend := func(from, to int32) int32 {
+					if steps <= 0 {
+						return from
+					}
+					return from + (to-from)*int32(position)/int32(steps)
+				}
+				background = tcell.NewRGBColor(blend(fromR, toR), blend(fromG, toG), blend(fromB, toB))
+			}
+			ch, style := ' ', tcell.StyleDefault.Background(background)
+			if len(b.backgroundPattern) > 0 {
+				ch, style = b.backgroundPattern[column%len(b.backgroundPattern)], b.backgroundPatternStyle
+				if _, bg, _ := style.Decompose(); bg == tcell.ColorDefault {
+					style = style.Background(background)
+				}
+			}
+			screen.SetContent(x+column, y+row, ch, nil, style)
+		}
+	}
+}
+
 // SetReverse turns on or off the reverse video attribute.
 func (b *Box) SetReverse(on bool) *Box {
 	b.reverse = on
@@ -432,6 +665,7 @@ func (b *Box) SetBorderVisible(visible bool) *Box {
 // SetBorderColor sets the box's border color.
 func (b *Box) SetBorderColor(color tcell.Color) *Box {
 	b.borderColor = color
+	b.overrides.set(&b.borderColor)
 	return b
 }
 
@@ -449,6 +683,7 @@ func (b *Box) GetBackgroundColor() tcell.Color {
 // SetBorderFocusColor sets the box's border color when focused.
 func (b *Box) SetBorderFocusColor(color tcell.Color) *Box {
 	b.borderFocusColor = color
+	b.overrides.set(&b.borderFocusColor)
 	return b
 }
 
@@ -503,9 +738,15 @@ func (b *Box) SetTitle(title string) *Box {
 	return b
 }
 
+// GetTitle returns the box's title.
+func (b *Box) GetTitle() string {
+	return b.title
+}
+
 // SetTitleColor sets the box's title color.
 func (b *Box) SetTitleColor(color tcell.Color) *Box {
 	b.titleColor = color
+	b.overrides.set(&b.titleColor)
 	return b
 }
 
@@ -516,6 +757,73 @@ func (b *Box) SetTitleAlign(align int) *Box {
 	return b
 }
 
+// SetShadow sets the flag indicating whether or not a one-cell drop shadow is
+// drawn to the right of and below the box. The shadow is not part of the box's
+// rectangle, i.e. it does not receive mouse events and does not affect the
+// inner rect.
+//
+// The shadow is drawn by the application after all primitives have been
+// drawn so that it is not covered by the box's siblings. It is therefore only
+// drawn for the root primitive and the primitives contained in it through the
+// package's container primitives, e.g. Flex, Grid, or Pages.
+func (b *Box) SetShadow(shadow bool) *Box {
+	b.shadow = shadow
+	return b
+}
+
+// SetShadowStyle sets the style which dims the cells covered by the shadow.
+// Their characters are kept while their colors are blended halfway towards the
+// style's foreground and background colors and the style's attributes are
+// added. A color of tcell.ColorDefault leaves the corresponding colors
+// unchanged. The default blends all colors with black and dims the cells.
+func (b *Box) SetShadowStyle(style tcell.Style) *Box {
+	b.shadowStyle = style
+	return b
+}
+
+// HasShadow returns whether or not a drop shadow is drawn for the box.
+func (b *Box) HasShadow() bool {
+	return b.shadow
+}
+
+// SetSecondaryTitle sets a second title which is drawn on the same edge as the
+// title (see SetTitle()) but aligned independently, one of AlignLeft,
+// AlignCenter, or AlignRight. For example, a box may show a left-aligned name
+// and a right-aligned status. It uses the title color.
+func (b *Box) SetSecondaryTitle(title string, align int) *Box {
+	b.secondaryTitle = title
+	b.secondaryTitleAlign = align
+	return b
+}
+
+// GetSecondaryTitle returns the box's secondary title and its alignment.
+func (b *Box) GetSecondaryTitle() (title string, align int) {
+	return b.secondaryTitle, b.secondaryTitleAlign
+}
+
+// SetTitleOnBottom sets whether the titles are drawn on the bottom edge of the
+// border instead of the top edge.
+func (b *Box) SetTitleOnBottom(onBottom bool) *Box {
+	b.titleOnBottom = onBottom
+	return b
+}
+
+// applyTheme implements the themeable interface.
+func (b *Box) applyTheme(from, to *Theme) {
+	b.overrides.color(&b.backgroundColor, to.PrimitiveBackgroundColor)
+	b.overrides.color(&b.borderColor, to.BorderColor)
+	b.overrides.color(&b.borderFocusColor, to.BorderFocusColor)
+	b.overrides.color(&b.titleColor, to.TitleColor)
+	fg, bg, _ := b.borderStyle.Decompose()
+	if !b.overrides.isSet(&b.borderColor) {
+		fg = to.BorderColor
+	}
+	if !b.overrides.isSet(&b.backgroundColor) {
+		bg = to.PrimitiveBackgroundColor
+	}
+	b.borderStyle = b.borderStyle.Foreground(fg).Background(bg)
+}
+
 // Draw draws this primitive onto the screen.
 func (b *Box) Draw(screen tcell.Screen) {
 	b.DrawForSubclass(screen, b)
@@ -527,6 +835,7 @@ func (b *Box) DrawForSubclass(screen tcell.Screen, p Primitive) {
 	if b.width <= 0 || b.height <= 0 || !b.visible {
 		return
 	}
+	b.subclass = p
 
 	borderVisible := b.borderVisible
 
@@ -542,6 +851,10 @@ func (b *Box) DrawForSubclass(screen tcell.Screen, p Primitive) {
 		}
 	}
 
+	// Fill the inner rect with the background pattern.
+	b.innerX = -1
+	b.drawBackgroundPattern(screen)
+
 	// Draw border.
 	b.DrawBorder(borderVisible, background, screen)
 
@@ -588,6 +901,22 @@ func (b *Box) DrawForSubclass(screen tcell.Screen, p Primitive) {
 	return
 }
 
+// drawShadow dims the screen cells directly to the right of and below the
+// box's rectangle, offset by one cell.
+func (b *Box) drawShadow(screen tcell.Screen) {
+	dim := func(x, y int) {
+		mainc, combc, style, _ := screen.GetContent(x, y)
+		screen.SetContent(x, y, mainc, combc, blendStyle(style, b.shadowStyle))
+	}
+	right, bottom := b.x+b.width, b.y+b.height
+	for y := b.y + 1; y <= bottom; y++ {
+		dim(right, y)
+	}
+	for x := b.x + 1; x < right; x++ {
+		dim(x, bottom)
+	}
+}
+
 func (b *Box) DrawBorder(borderVisible bool, background tcell.Style, screen tcell.Screen) bool {
 	// background = tcell.StyleDefault.Background(0)
 	if b.border && b.width >= 2 && b.height >= 1 {
@@ -727,36 +1056,32 @@ func (b *Box) DrawBorder(borderVisible bool, background tcell.Style, screen tcel
 			}
 		}
 
-		if b.title != "" && b.width >= 4 {
-			_, _ = Print(
-				screen,
-				b.title,
-				b.x+1,
-				b.y,
-				b.width-2,
-				b.titleAlign,
-				b.titleColor,
-			)
-			// if len(b.title)-printed > 0 && printed > 0 {
-			// 	_, _, style, _ := screen.GetContent(b.x+b.width-2, b.y)
-			// 	fg, _, _ := style.Decompose()
-			// 	Print(
-			// 		screen,
-			// 		string(SemigraphicsHorizontalEllipsis),
-			// 		b.x+b.width-2,
-			// 		b.y,
-			// 		1,
-			// 		AlignLeft,
-			// 		fg,
-			// 	)
-			// }
+		if b.width >= 4 {
+			row := b.y
+			if b.titleOnBottom {
+				row = b.y + b.height - 1
+			}
+			b.drawTitle(screen, b.title, b.titleAlign, row)
+			b.drawTitle(screen, b.secondaryTitle, b.secondaryTitleAlign, row)
 		}
 	}
 	return false
 }
 
+// drawTitle draws a title into the given row of the border, between the
+// corners. A title which doesn't fit is truncated and ends with an ellipsis.
+func (b *Box) drawTitle(screen tcell.Screen, title string, align, row int) {
+	if title == "" {
+		return
+	}
+	printWithEllipsis(screen, title, b.x+1, row, b.width-2, align, tcell.StyleDefault.Foreground(b.titleColor), true)
+}
+
 // Focus is called when this primitive receives focus.
 func (b *Box) Focus(delegate func(p Primitive)) {
+	if b.hasFocus {
+		return
+	}
 	b.hasFocus = true
 	if b.onFocus != nil {
 		b.onFocus()
@@ -765,12 +1090,59 @@ func (b *Box) Focus(delegate func(p Primitive)) {
 
 // Blur is called when this primitive loses focus.
 func (b *Box) Blur() {
+	b.scrollSuppressed = false
+	if !b.hasFocus {
+		return
+	}
 	b.hasFocus = false
 	if b.onBlur != nil {
 		b.onBlur()
 	}
 }
 
+// SetScrollOnFocus sets whether scrolling containers (Grid and Form) scroll
+// to reveal this box when it or one of its contained primitives has focus. The
+// default is true. Set it to false to keep such a container's scroll position
+// when the box receives focus, e.g. when moving the focus programmatically.
+func (b *Box) SetScrollOnFocus(scroll bool) *Box {
+	b.noScrollOnFocus = !scroll
+	return b
+}
+
+// GetScrollOnFocus returns whether scrolling containers scroll to reveal this
+// box when it has focus. It returns false while this is suppressed by
+// Application.SetFocusWithoutScroll().
+func (b *Box) GetScrollOnFocus() bool {
+	return !b.noScrollOnFocus && !b.scrollSuppressed
+}
+
+// suppressScrollOnFocus keeps containers from scrolling to reveal this box
+// until it loses focus.
+func (b *Box) suppressScrollOnFocus() {
+	b.scrollSuppressed = true
+}
+
+// scrollsToFocus returns whether a scrolling container should scroll to reveal
+// the given contained primitive which has focus. This is not the case if the
+// primitive or any primitive between it and the focused primitive has disabled
+// this (see Box.SetScrollOnFocus()).
+func scrollsToFocus(item Primitive) bool {
+	for item != nil {
+		if s, ok := item.(interface{ GetScrollOnFocus() bool }); ok && !s.GetScrollOnFocus() {
+			return false
+		}
+		var focused Primitive
+		for _, child := range childPrimitives(item) {
+			if child.HasFocus() {
+				focused = child
+				break
+			}
+		}
+		item = focused
+	}
+	return true
+}
+
 // SetNextFocusableComponents decides which components are to be focused using
 // a certain focus direction. If more than one component is passed, the
 // priority goes from left-most to right-most. A component will be skipped if
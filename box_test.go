@@ -0,0 +1,59 @@
+package tview
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestBoxShadow(t *testing.T) {
+	background := tcell.NewRGBColor(200, 200, 200)
+	shadowed := NewBox().SetBackgroundColor(tcell.ColorBlue).SetShadow(true)
+	sibling := NewBox().SetBackgroundColor(background)
+	flex := NewFlex().
+		AddItem(shadowed, 4, 0, false).
+		AddItem(sibling, 0, 1, false)
+	app := NewApplication().SetRoot(flex, true)
+	screen := startApp(t, app, 10, 4)
+
+	// The cells to the right of the box are dimmed although the sibling was
+	// drawn after the box.
+	for y := 1; y < 4; y++ {
+		_, _, style, _ := screen.GetContent(4, y)
+		_, bg, attr := style.Decompose()
+		if attr&tcell.AttrDim == 0 {
+			t.Errorf("shadow cell (4,%d) is not dimmed", y)
+		}
+		if expected := tcell.NewRGBColor(100, 100, 100); bg != expected {
+			t.Errorf("shadow cell (4,%d) has background %v, expected %v", y, bg, expected)
+		}
+	}
+
+	// The cell above the shadow and the box itself are unchanged.
+	if _, _, style, _ := screen.GetContent(4, 0); style != tcell.StyleDefault.Background(background) {
+		t.Errorf("cell (4,0) has style %v, expected the sibling's style", style)
+	}
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 4; x++ {
+			if _, _, style, _ := screen.GetContent(x, y); style != tcell.StyleDefault.Background(tcell.ColorBlue) {
+				t.Errorf("box cell (%d,%d) has style %v, expected the box's style", x, y, style)
+			}
+		}
+	}
+	if x, y, width, height := shadowed.GetInnerRect(); x != 0 || y != 0 || width != 4 || height != 4 {
+		t.Errorf("inner rect is %d,%d %dx%d, expected the box's rect", x, y, width, height)
+	}
+}
+
+func TestBoxTitleEllipsis(t *testing.T) {
+	screen := newTestScreen(t, 8, 3)
+	box := NewBox().SetBorder(true).SetTitle("日本語タイトル")
+	box.SetRect(0, 0, 8, 3)
+	box.Draw(screen)
+
+	// The partially fitting wide character is dropped and the ellipsis
+	// follows the last printed character.
+	if line := screenLine(screen, 0); line != "┌日 本 …─┐" {
+		t.Errorf("title row is %q, expected a truncated title", line)
+	}
+}
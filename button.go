@@ -25,6 +25,17 @@ type Button struct {
 	// An optional function which is called when the button was selected.
 	selected func()
 
+	// If set to true, the button cannot be selected, is skipped by forms when
+	// moving the focus, and is drawn in the disabled style.
+	disabled bool
+
+	// Like disabled but controlled by forms (see
+	// Form.SetButtonRequiresValid()).
+	invalid bool
+
+	// The style of the button's label when it is disabled.
+	disabledStyle tcell.Style
+
 	// An optional function which is called when the user leaves the button. A
 	// key is provided indicating which key was pressed to leave (tab or
 	// backtab).
@@ -33,7 +44,8 @@ type Button struct {
 
 // NewButton returns a new input field.
 func NewButton(label string) *Button {
-	box := NewBox().SetBackgroundColor(Styles.ContrastBackgroundColor)
+	box := NewBox()
+	box.backgroundColor = Styles.ContrastBackgroundColor
 	box.SetRect(0, 0, TaggedStringWidth(label)+4, 1)
 	return &Button{
 		Box:                      box,
@@ -41,6 +53,7 @@ func NewButton(label string) *Button {
 		labelColor:               Styles.PrimaryTextColor,
 		labelColorActivated:      Styles.InverseTextColor,
 		backgroundColorActivated: Styles.PrimaryTextColor,
+		disabledStyle:            tcell.StyleDefault.Foreground(Styles.ContrastSecondaryTextColor).Background(Styles.ContrastBackgroundColor),
 	}
 }
 
@@ -58,6 +71,7 @@ func (b *Button) GetLabel() string {
 // SetLabelColor sets the color of the button text.
 func (b *Button) SetLabelColor(color tcell.Color) *Button {
 	b.labelColor = color
+	b.overrides.set(&b.labelColor)
 	return b
 }
 
@@ -65,6 +79,7 @@ func (b *Button) SetLabelColor(color tcell.Color) *Button {
 // in focus.
 func (b *Button) SetLabelColorActivated(color tcell.Color) *Button {
 	b.labelColorActivated = color
+	b.overrides.set(&b.labelColorActivated)
 	return b
 }
 
@@ -72,6 +87,31 @@ func (b *Button) SetLabelColorActivated(color tcell.Color) *Button {
 // the button is in focus.
 func (b *Button) SetBackgroundColorActivated(color tcell.Color) *Button {
 	b.backgroundColorActivated = color
+	b.overrides.set(&b.backgroundColorActivated)
+	return b
+}
+
+// SetDisabled sets whether the button is disabled. A disabled button ignores
+// the Enter key and mouse clicks, is skipped when a Form or Modal moves the
+// focus between its buttons, and its label is drawn in the disabled style (see
+// SetDisabledStyle()).
+func (b *Button) SetDisabled(disabled bool) *Button {
+	b.disabled = disabled
+	return b
+}
+
+// IsDisabled returns whether the button is disabled, either explicitly (see
+// SetDisabled()) or by a form because not all form items are valid (see
+// Form.SetButtonRequiresValid()).
+func (b *Button) IsDisabled() bool {
+	return b.disabled || b.invalid
+}
+
+// SetDisabledStyle sets the style of the button's label when it is disabled.
+// Its background color is only used if it differs from the default color.
+func (b *Button) SetDisabledStyle(style tcell.Style) *Button {
+	b.disabledStyle = style
+	b.overrides.set(&b.disabledStyle)
 	return b
 }
 
@@ -93,6 +133,16 @@ func (b *Button) SetExitFunc(handler func(key tcell.Key)) *Button {
 	return b
 }
 
+// applyTheme implements the themeable interface.
+func (b *Button) applyTheme(from, to *Theme) {
+	b.Box.applyTheme(from, to)
+	b.overrides.color(&b.backgroundColor, to.ContrastBackgroundColor)
+	b.overrides.color(&b.labelColor, to.PrimaryTextColor)
+	b.overrides.color(&b.labelColorActivated, to.InverseTextColor)
+	b.overrides.color(&b.backgroundColorActivated, to.PrimaryTextColor)
+	b.overrides.style(&b.disabledStyle, tcell.StyleDefault.Foreground(to.ContrastSecondaryTextColor).Background(to.ContrastBackgroundColor))
+}
+
 // Draw draws this primitive onto the screen.
 func (b *Button) Draw(screen tcell.Screen) {
 	// Draw the box.
@@ -112,6 +162,11 @@ func (b *Button) Draw(screen tcell.Screen) {
 	x, y, width, height := b.GetInnerRect()
 	if width > 0 && height > 0 {
 		y = y + height/2
+		if b.IsDisabled() {
+			_, background, _ := b.disabledStyle.Decompose()
+			printWithStyle(screen, b.label, x, y, 0, width, AlignCenter, b.disabledStyle, background == tcell.ColorDefault)
+			return
+		}
 		labelColor := b.labelColor
 		if b.HasFocus() {
 			labelColor = b.labelColorActivated
@@ -126,7 +181,7 @@ func (b *Button) InputHandler() func(event *tcell.EventKey, setFocus func(p Prim
 		// Process key event.
 		switch key := event.Key(); key {
 		case tcell.KeyEnter: // Selected.
-			if b.selected != nil {
+			if b.selected != nil && !b.IsDisabled() {
 				b.selected()
 			}
 		case tcell.KeyBacktab, tcell.KeyTab, tcell.KeyEscape: // Leave. No action.
@@ -147,7 +202,7 @@ func (b *Button) MouseHandler() func(action MouseAction, event *tcell.EventMouse
 		// Process mouse event.
 		if action == MouseLeftClick {
 			setFocus(b)
-			if b.selected != nil {
+			if b.selected != nil && !b.IsDisabled() {
 				b.selected()
 			}
 			consumed = true
@@ -0,0 +1,553 @@
+package tview
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// The size of the month grid of a Calendar: a row with the month and the year,
+// a row with the names of the weekdays, and six rows of days. Each day takes
+// two cells, followed by a space.
+const (
+	calendarWidth  = 20
+	calendarHeight = 8
+)
+
+// Calendar is a date picker which shows the days of one month in a grid. The
+// user navigates between the days with the keyboard or the mouse and selects
+// the current date with Enter, the space bar, or a mouse click (see
+// SetSelectedFunc()).
+//
+// If the calendar has a label (see SetLabel()) or if it is less than eight
+// rows high, the first row shows the label and the current date (see
+// SetDateFormat()) and the month grid is drawn below it. If there is not enough
+// space for the grid, it is only drawn while the calendar has focus, on top of
+// the primitives below (or above) it. This allows using a calendar as an item
+// of a Form.
+//
+// The following keys can be used:
+//
+//   - Left arrow, right arrow: Move to the previous or next day.
+//   - Up arrow, down arrow: Move to the previous or next week.
+//   - Page up, page down: Move to the previous or next month.
+//   - Shift-, Ctrl-, or Alt-Page up/down: Move to the previous or next year.
+//   - Home, End: Move to the first or last day of the month.
+//   - Enter, space: Select the current date.
+//   - Tab, Backtab, Escape: Done (see SetDoneFunc()).
+//
+// The arrows in the month grid's first row switch months when clicked. Dates
+// outside the range set with SetRange() are dimmed and cannot be navigated to.
+type Calendar struct {
+	*Box
+
+	// The current date, at midnight.
+	date time.Time
+
+	// The first day of the week, shown in the leftmost column.
+	firstWeekday time.Weekday
+
+	// The earliest and the latest date which can be selected. Zero values
+	// mean that there is no limit.
+	minDate, maxDate time.Time
+
+	// The layout with which the current date is shown in the first row, see
+	// time.Time.Format().
+	dateFormat string
+
+	// The text to be displayed before the date.
+	label string
+
+	// The screen width of the label area. A value of 0 means use the width of
+	// the label text.
+	labelWidth int
+
+	// Set to true if the label width was set with SetLabelWidth(). It then
+	// takes precedence over the label width provided by a form.
+	fixedLabelWidth bool
+
+	// The label color, also used for the month and the names of the weekdays.
+	labelColor tcell.Color
+
+	// The background color of the date and of the month grid.
+	fieldBackgroundColor tcell.Color
+
+	// The text color of the date and of the month grid.
+	fieldTextColor tcell.Color
+
+	// The position of the month grid as of the last call to Draw(). gridX is
+	// negative if the grid was not drawn.
+	gridX, gridY int
+
+	// Whether the month grid didn't fit below the date during the last call to
+	// Draw() and is drawn by the application on top of everything else (see
+	// drawPopup()), and the position of the date at that time.
+	popup          bool
+	fieldX, fieldY int
+
+	// An optional function which is called when the user navigates to a date.
+	changed func(date time.Time)
+
+	// An optional function which is called when the user selects a date.
+	selected func(date time.Time)
+
+	// An optional function which is called when the user indicated that they
+	// are done. The key which was pressed is provided (tab, shift-tab, or
+	// escape).
+	done func(tcell.Key)
+
+	// A callback function set by the Form class and called when the user leaves
+	// this form item.
+	finished func(tcell.Key)
+}
+
+// NewCalendar returns a new calendar showing today's date. Weeks start on
+// Monday.
+func NewCalendar() *Calendar {
+	return &Calendar{
+		Box:                  NewBox(),
+		date:                 calendarDate(time.Now()),
+		firstWeekday:         time.Monday,
+		dateFormat:           "2006-01-02",
+		labelColor:           Styles.SecondaryTextColor,
+		fieldBackgroundColor: Styles.ContrastBackgroundColor,
+		fieldTextColor:       Styles.PrimaryTextColor,
+		gridX:                -1,
+	}
+}
+
+// calendarDate returns the given time at midnight of the same day.
+func calendarDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// addMonths returns the given date moved by the given number of months. The
+// day is clamped to the length of the resulting month.
+func addMonths(date time.Time, months int) time.Time {
+	first := time.Date(date.Year(), date.Month()+time.Month(months), 1, 0, 0, 0, 0, date.Location())
+	last := first.AddDate(0, 1, -1).Day()
+	day := date.Day()
+	if day > last {
+		day = last
+	}
+	return first.AddDate(0, 0, day-1)
+}
+
+// SetDate sets the current date. The time of day is ignored. The date is
+// clamped to the range set with SetRange().
+func (c *Calendar) SetDate(date time.Time) *Calendar {
+	c.date = c.clamp(calendarDate(date))
+	return c
+}
+
+// GetDate returns the current date, at midnight.
+func (c *Calendar) GetDate() time.Time {
+	return c.date
+}
+
+// SetFirstWeekday sets the day shown in the leftmost column of the month grid.
+// The default is time.Monday.
+func (c *Calendar) SetFirstWeekday(weekday time.Weekday) *Calendar {
+	c.firstWeekday = weekday % 7
+	return c
+}
+
+// SetRange sets the earliest and the latest date which can be selected. A zero
+// time.Time means that there is no limit in that direction. The current date
+// is clamped to the new range.
+func (c *Calendar) SetRange(min, max time.Time) *Calendar {
+	c.minDate, c.maxDate = time.Time{}, time.Time{}
+	if !min.IsZero() {
+		c.minDate = calendarDate(min)
+	}
+	if !max.IsZero() {
+		c.maxDate = calendarDate(max)
+	}
+	c.date = c.clamp(c.date)
+	return c
+}
+
+// SetDateFormat sets the layout with which the current date is shown next to
+// the label (see time.Time.Format()). The default is "2006-01-02".
+func (c *Calendar) SetDateFormat(layout string) *Calendar {
+	c.dateFormat = layout
+	return c
+}
+
+// SetLabel sets the text to be displayed before the date.
+func (c *Calendar) SetLabel(label string) *Calendar {
+	c.label = label
+	return c
+}
+
+// GetLabel returns the text to be displayed before the date.
+func (c *Calendar) GetLabel() string {
+	return c.label
+}
+
+// SetLabelWidth sets the screen width of the label. A value of 0 will cause the
+// primitive to use the width of the label string. A width greater than 0 also
+// overrides the label width a Form would otherwise assign to this primitive.
+func (c *Calendar) SetLabelWidth(width int) *Calendar {
+	c.labelWidth = width
+	c.fixedLabelWidth = width > 0
+	return c
+}
+
+// GetLabelWidth returns the screen width of the label. A value of 0 means the
+// width of the label string is used.
+func (c *Calendar) GetLabelWidth() int {
+	return c.labelWidth
+}
+
+// hasFixedLabelWidth implements the fixedLabelWidthItem interface.
+func (c *Calendar) hasFixedLabelWidth() bool {
+	return c.fixedLabelWidth
+}
+
+// SetLabelColor sets the color of the label, of the month, and of the names of
+// the weekdays.
+func (c *Calendar) SetLabelColor(color tcell.Color) *Calendar {
+	c.labelColor = color
+	c.overrides.set(&c.labelColor)
+	return c
+}
+
+// SetFieldBackgroundColor sets the background color of the date and of the
+// month grid.
+func (c *Calendar) SetFieldBackgroundColor(color tcell.Color) *Calendar {
+	c.fieldBackgroundColor = color
+	c.overrides.set(&c.fieldBackgroundColor)
+	return c
+}
+
+// SetFieldTextColor sets the text color of the date and of the month grid.
+func (c *Calendar) SetFieldTextColor(color tcell.Color) *Calendar {
+	c.fieldTextColor = color
+	c.overrides.set(&c.fieldTextColor)
+	return c
+}
+
+// SetFormAttributes sets attributes shared by all form items.
+func (c *Calendar) SetFormAttributes(labelWidth int, labelColor, bgColor, fieldTextColor, fieldBgColor tcell.Color) FormItem {
+	if !c.fixedLabelWidth {
+		c.labelWidth = labelWidth
+	}
+	c.labelColor = labelColor
+	c.backgroundColor = bgColor
+	c.fieldTextColor = fieldTextColor
+	c.fieldBackgroundColor = fieldBgColor
+	return c
+}
+
+// GetFieldWidth returns this primitive's field width, the width of the month
+// grid.
+func (c *Calendar) GetFieldWidth() int {
+	return calendarWidth
+}
+
+// SetChangedFunc sets a handler which is called when the user navigates to a
+// date. The handler function receives the new current date.
+func (c *Calendar) SetChangedFunc(handler func(date time.Time)) *Calendar {
+	c.changed = handler
+	return c
+}
+
+// SetSelectedFunc sets a handler which is called when the user selects a date
+// by pressing Enter or the space bar or by clicking on a day.
+func (c *Calendar) SetSelectedFunc(handler func(date time.Time)) *Calendar {
+	c.selected = handler
+	return c
+}
+
+// SetDoneFunc sets a handler which is called when the user is done using the
+// calendar. The callback function is provided with the key that was pressed,
+// which is one of the following:
+//
+//   - KeyEscape: Abort.
+//   - KeyTab: Move to the next field.
+//   - KeyBacktab: Move to the previous field.
+func (c *Calendar) SetDoneFunc(handler func(key tcell.Key)) *Calendar {
+	c.done = handler
+	return c
+}
+
+// SetFinishedFunc sets a callback invoked when the user leaves this form item.
+func (c *Calendar) SetFinishedFunc(handler func(key tcell.Key)) FormItem {
+	c.finished = handler
+	return c
+}
+
+// clamp returns the given date clamped to the selectable range.
+func (c *Calendar) clamp(date time.Time) time.Time {
+	if !c.minDate.IsZero() && date.Before(c.minDate) {
+		return c.minDate
+	}
+	if !c.maxDate.IsZero() && date.After(c.maxDate) {
+		return c.maxDate
+	}
+	return date
+}
+
+// inRange returns whether the given date can be selected.
+func (c *Calendar) inRange(date time.Time) bool {
+	return c.clamp(date).Equal(date)
+}
+
+// moveTo makes the given date, clamped to the selectable range, the current
+// date and notifies the changed handler.
+func (c *Calendar) moveTo(date time.Time) {
+	date = c.clamp(date)
+	if date.Equal(c.date) {
+		return
+	}
+	c.date = date
+	if c.changed != nil {
+		c.changed(date)
+	}
+}
+
+// selectDate notifies the selected handler about the current date.
+func (c *Calendar) selectDate() {
+	if c.selected != nil {
+		c.selected(c.date)
+	}
+}
+
+// firstColumn returns the column of the first day of the current month.
+func (c *Calendar) firstColumn() int {
+	first := time.Date(c.date.Year(), c.date.Month(), 1, 0, 0, 0, 0, c.date.Location())
+	return (int(first.Weekday()) - int(c.firstWeekday) + 7) % 7
+}
+
+// applyTheme implements the themeable interface.
+func (c *Calendar) applyTheme(from, to *Theme) {
+	c.Box.applyTheme(from, to)
+	c.overrides.color(&c.labelColor, to.SecondaryTextColor)
+	c.overrides.color(&c.fieldBackgroundColor, to.ContrastBackgroundColor)
+	c.overrides.color(&c.fieldTextColor, to.PrimaryTextColor)
+}
+
+// Draw draws this primitive onto the screen.
+func (c *Calendar) Draw(screen tcell.Screen) {
+	c.Box.DrawForSubclass(screen, c)
+	c.gridX = -1
+	c.popup = false
+
+	x, y, width, height := c.GetInnerRect()
+	rightLimit := x + width
+	if height < 1 || rightLimit <= x {
+		return
+	}
+	fieldStyle := tcell.StyleDefault.Background(c.fieldBackgroundColor).Foreground(c.fieldTextColor)
+
+	// Without a label and with enough space, there is only the month grid.
+	if c.label == "" && height >= calendarHeight {
+		c.drawGrid(screen, x, y, fieldStyle)
+		return
+	}
+
+	// Draw label.
+	if c.labelWidth > 0 {
+		labelWidth := c.labelWidth
+		if labelWidth > rightLimit-x {
+			labelWidth = rightLimit - x
+		}
+		Print(screen, c.label, x, y, labelWidth, AlignLeft, c.labelColor)
+		x += labelWidth
+	} else {
+		_, drawnWidth := Print(screen, c.label, x, y, rightLimit-x, AlignLeft, c.labelColor)
+		x += drawnWidth
+	}
+
+	// Draw the current date.
+	fieldWidth := calendarWidth
+	if fieldWidth > rightLimit-x {
+		fieldWidth = rightLimit - x
+	}
+	for index := 0; index < fieldWidth; index++ {
+		screen.SetContent(x+index, y, ' ', nil, fieldStyle)
+	}
+	printWithEllipsis(screen, c.date.Format(c.dateFormat), x, y, fieldWidth, AlignLeft, fieldStyle, false)
+
+	// Draw the month grid below the date if it fits or if we have focus.
+	if height > calendarHeight {
+		c.drawGrid(screen, x, y+1, fieldStyle)
+	} else if c.HasFocus() {
+		c.popup, c.fieldX, c.fieldY = true, x, y
+	}
+}
+
+// openCalendar returns the calendar whose month grid is shown as a pop-up if
+// it is the given primitive, or nil otherwise.
+func openCalendar(p Primitive) *Calendar {
+	if calendar, ok := p.(*Calendar); ok && calendar.popup && calendar.HasFocus() {
+		return calendar
+	}
+	return nil
+}
+
+// drawPopup draws the month grid as a floating overlay below the date, or
+// above it if there is not enough space below.
+func (c *Calendar) drawPopup(screen tcell.Screen) {
+	gridX, gridY := c.fieldX, c.fieldY+1
+	screenWidth, screenHeight := screen.Size()
+	if gridY+calendarHeight > screenHeight && c.fieldY-calendarHeight >= 0 {
+		gridY = c.fieldY - calendarHeight // Flip above the date.
+	}
+	if gridX+calendarWidth > screenWidth {
+		gridX = screenWidth - calendarWidth
+	}
+	if gridX < 0 {
+		gridX = 0
+	}
+	c.drawGrid(screen, gridX, gridY, tcell.StyleDefault.Background(c.fieldBackgroundColor).Foreground(c.fieldTextColor))
+}
+
+// drawGrid draws the month grid of the current date with its top-left corner
+// at the given position.
+func (c *Calendar) drawGrid(screen tcell.Screen, x, y int, style tcell.Style) {
+	c.gridX, c.gridY = x, y
+	for row := 0; row < calendarHeight; row++ {
+		for column := 0; column < calendarWidth; column++ {
+			screen.SetContent(x+column, y+row, ' ', nil, style)
+		}
+	}
+
+	// The month and the arrows to switch months.
+	labelStyle := style.Foreground(c.labelColor)
+	title := fmt.Sprintf("%s %d", c.date.Month(), c.date.Year())
+	printWithStyle(screen, title, x+1, y, 0, calendarWidth-2, AlignCenter, labelStyle.Bold(true), false)
+	screen.SetContent(x, y, '◀', nil, labelStyle)
+	screen.SetContent(x+calendarWidth-1, y, '▶', nil, labelStyle)
+
+	// The names of the weekdays.
+	for column := 0; column < 7; column++ {
+		weekday := (c.firstWeekday + time.Weekday(column)) % 7
+		printWithStyle(screen, weekday.String()[:2], x+column*3, y+1, 0, 2, AlignLeft, labelStyle, false)
+	}
+
+	// The days.
+	today := calendarDate(time.Now().In(c.date.Location()))
+	first := time.Date(c.date.Year(), c.date.Month(), 1, 0, 0, 0, 0, c.date.Location())
+	offset := c.firstColumn()
+	for date := first; date.Month() == first.Month(); date = date.AddDate(0, 0, 1) {
+		position := offset + date.Day() - 1
+		dayStyle := style
+		if date.Equal(c.date) {
+			dayStyle = style.Background(c.fieldTextColor).Foreground(c.fieldBackgroundColor)
+		} else if !c.inRange(date) {
+			dayStyle = style.Dim(true)
+		}
+		if date.Equal(today) {
+			dayStyle = dayStyle.Underline(true)
+		}
+		day := fmt.Sprintf("%2d", date.Day())
+		printWithStyle(screen, day, x+position%7*3, y+2+position/7, 0, 2, AlignLeft, dayStyle, false)
+	}
+}
+
+// dayAt returns the date of the month grid found at the given screen position
+// or false if there is no day at that position.
+func (c *Calendar) dayAt(x, y int) (time.Time, bool) {
+	column, row := x-c.gridX, y-c.gridY-2
+	if c.gridX < 0 || row < 0 || row >= calendarHeight-2 || column < 0 || column >= calendarWidth || column%3 == 2 {
+		return time.Time{}, false
+	}
+	day := row*7 + column/3 - c.firstColumn() + 1
+	date := time.Date(c.date.Year(), c.date.Month(), day, 0, 0, 0, 0, c.date.Location())
+	if day < 1 || date.Month() != c.date.Month() {
+		return time.Time{}, false
+	}
+	return date, true
+}
+
+// inGrid returns whether the given screen position is inside the month grid
+// as drawn during the last call to Draw().
+func (c *Calendar) inGrid(x, y int) bool {
+	return c.gridX >= 0 && x >= c.gridX && x < c.gridX+calendarWidth && y >= c.gridY && y < c.gridY+calendarHeight
+}
+
+// InputHandler returns the handler for this primitive.
+func (c *Calendar) InputHandler() func(event *tcell.EventKey, setFocus func(p Primitive)) {
+	return c.WrapInputHandler(func(event *tcell.EventKey, setFocus func(p Primitive)) {
+		months := 1
+		if event.Modifiers()&(tcell.ModShift|tcell.ModCtrl|tcell.ModAlt) != 0 {
+			months = 12
+		}
+		switch key := event.Key(); key {
+		case tcell.KeyLeft:
+			c.moveTo(c.date.AddDate(0, 0, -1))
+		case tcell.KeyRight:
+			c.moveTo(c.date.AddDate(0, 0, 1))
+		case tcell.KeyUp:
+			c.moveTo(c.date.AddDate(0, 0, -7))
+		case tcell.KeyDown:
+			c.moveTo(c.date.AddDate(0, 0, 7))
+		case tcell.KeyPgUp:
+			c.moveTo(addMonths(c.date, -months))
+		case tcell.KeyPgDn:
+			c.moveTo(addMonths(c.date, months))
+		case tcell.KeyHome:
+			c.moveTo(c.date.AddDate(0, 0, 1-c.date.Day()))
+		case tcell.KeyEnd:
+			c.moveTo(addMonths(c.date.AddDate(0, 0, 1-c.date.Day()), 1).AddDate(0, 0, -1))
+		case tcell.KeyEnter:
+			c.selectDate()
+		case tcell.KeyRune:
+			if event.Rune() == ' ' {
+				c.selectDate()
+			}
+		case tcell.KeyTab, tcell.KeyBacktab, tcell.KeyEscape: // We're done.
+			if c.done != nil {
+				c.done(key)
+			}
+			if c.finished != nil {
+				c.finished(key)
+			}
+		}
+	})
+}
+
+// MouseHandler returns the mouse handler for this primitive.
+func (c *Calendar) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+	return c.WrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+		x, y := event.Position()
+		inGrid := c.inGrid(x, y)
+		if !inGrid && !c.InRect(x, y) {
+			return false, nil
+		}
+
+		switch action {
+		case MouseLeftDown:
+			setFocus(c)
+			consumed = true
+		case MouseLeftClick:
+			setFocus(c)
+			consumed = true
+			if !inGrid {
+				break
+			}
+			if y == c.gridY && x == c.gridX {
+				c.moveTo(addMonths(c.date, -1))
+			} else if y == c.gridY && x == c.gridX+calendarWidth-1 {
+				c.moveTo(addMonths(c.date, 1))
+			} else if date, ok := c.dayAt(x, y); ok && c.inRange(date) {
+				c.moveTo(date)
+				c.selectDate()
+			}
+		case MouseScrollUp:
+			if inGrid {
+				c.moveTo(addMonths(c.date, -1))
+				consumed = true
+			}
+		case MouseScrollDown:
+			if inGrid {
+				c.moveTo(addMonths(c.date, 1))
+				consumed = true
+			}
+		}
+		return
+	})
+}
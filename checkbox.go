@@ -6,15 +6,30 @@ import (
 	"github.com/gdamore/tcell/v2"
 )
 
+// CheckedState is the state of a Checkbox.
+type CheckedState int
+
+// Checkbox states. The indeterminate state is only available in tri-state mode
+// (see Checkbox.SetTriState()).
+const (
+	CheckboxUnchecked CheckedState = iota
+	CheckboxChecked
+	CheckboxIndeterminate
+)
+
 // Checkbox implements a simple box for boolean values which can be checked and
-// unchecked.
+// unchecked. In tri-state mode, it may also be in an indeterminate state, e.g.
+// for a "select all" checkbox over a partially selected group.
 //
 // See https://github.com/rivo/tview/wiki/Checkbox for an example.
 type Checkbox struct {
 	*Box
 
-	// Whether or not this box is checked.
-	checked bool
+	// The state of this box.
+	state CheckedState
+
+	// Whether or not the box may be in the indeterminate state.
+	triState bool
 
 	// The text to be displayed before the input area.
 	label string
@@ -23,6 +38,10 @@ type Checkbox struct {
 	// the label text.
 	labelWidth int
 
+	// Set to true if the label width was set with SetLabelWidth(). It then
+	// takes precedence over the label width provided by a form.
+	fixedLabelWidth bool
+
 	// The label color.
 	labelColor tcell.Color
 
@@ -35,6 +54,9 @@ type Checkbox struct {
 	// The string use to display a checked box.
 	checkedString string
 
+	// The rune used to display an indeterminate box.
+	indeterminateRune rune
+
 	// An optional function which is called when the user changes the checked
 	// state of this checkbox.
 	changed func(checked bool)
@@ -57,18 +79,65 @@ func NewCheckbox() *Checkbox {
 		fieldBackgroundColor: Styles.ContrastBackgroundColor,
 		fieldTextColor:       Styles.PrimaryTextColor,
 		checkedString:        "X",
+		indeterminateRune:    '-',
 	}
 }
 
 // SetChecked sets the state of the checkbox.
 func (c *Checkbox) SetChecked(checked bool) *Checkbox {
-	c.checked = checked
+	if checked {
+		c.state = CheckboxChecked
+	} else {
+		c.state = CheckboxUnchecked
+	}
 	return c
 }
 
-// IsChecked returns whether or not the box is checked.
+// IsChecked returns whether or not the box is checked. An indeterminate box is
+// not checked.
 func (c *Checkbox) IsChecked() bool {
-	return c.checked
+	return c.state == CheckboxChecked
+}
+
+// SetTriState sets whether the checkbox may be in the indeterminate state. The
+// indeterminate state can only be set programmatically with SetState(). When
+// the user toggles the checkbox, it alternates between checked and unchecked,
+// an indeterminate box becoming checked. Turning tri-state mode off changes an
+// indeterminate box to unchecked.
+func (c *Checkbox) SetTriState(triState bool) *Checkbox {
+	c.triState = triState
+	if !triState && c.state == CheckboxIndeterminate {
+		c.state = CheckboxUnchecked
+	}
+	return c
+}
+
+// SetState sets the state of the checkbox. CheckboxIndeterminate is treated as
+// CheckboxUnchecked unless tri-state mode is on (see SetTriState()).
+func (c *Checkbox) SetState(state CheckedState) *Checkbox {
+	if state == CheckboxIndeterminate && !c.triState {
+		state = CheckboxUnchecked
+	}
+	c.state = state
+	return c
+}
+
+// GetState returns the state of the checkbox.
+func (c *Checkbox) GetState() CheckedState {
+	return c.state
+}
+
+// toggle changes the state of the checkbox in response to user input and
+// notifies the changed handler.
+func (c *Checkbox) toggle() {
+	if c.state == CheckboxChecked {
+		c.state = CheckboxUnchecked
+	} else {
+		c.state = CheckboxChecked
+	}
+	if c.changed != nil {
+		c.changed(c.state == CheckboxChecked)
+	}
 }
 
 // SetLabel sets the text to be displayed before the input area.
@@ -83,27 +152,43 @@ func (c *Checkbox) GetLabel() string {
 }
 
 // SetLabelWidth sets the screen width of the label. A value of 0 will cause the
-// primitive to use the width of the label string.
+// primitive to use the width of the label string. A width greater than 0 also
+// overrides the label width a Form would otherwise assign to this primitive.
 func (c *Checkbox) SetLabelWidth(width int) *Checkbox {
 	c.labelWidth = width
+	c.fixedLabelWidth = width > 0
 	return c
 }
 
+// GetLabelWidth returns the screen width of the label. A value of 0 means the
+// width of the label string is used.
+func (c *Checkbox) GetLabelWidth() int {
+	return c.labelWidth
+}
+
+// hasFixedLabelWidth implements the fixedLabelWidthItem interface.
+func (c *Checkbox) hasFixedLabelWidth() bool {
+	return c.fixedLabelWidth
+}
+
 // SetLabelColor sets the color of the label.
 func (c *Checkbox) SetLabelColor(color tcell.Color) *Checkbox {
 	c.labelColor = color
+	c.overrides.set(&c.labelColor)
 	return c
 }
 
 // SetFieldBackgroundColor sets the background color of the input area.
 func (c *Checkbox) SetFieldBackgroundColor(color tcell.Color) *Checkbox {
 	c.fieldBackgroundColor = color
+	c.overrides.set(&c.fieldBackgroundColor)
 	return c
 }
 
 // SetFieldTextColor sets the text color of the input area.
 func (c *Checkbox) SetFieldTextColor(color tcell.Color) *Checkbox {
 	c.fieldTextColor = color
+	c.overrides.set(&c.fieldTextColor)
 	return c
 }
 
@@ -114,9 +199,18 @@ func (c *Checkbox) SetCheckedString(checked string) *Checkbox {
 	return c
 }
 
+// SetIndeterminateRune sets the rune to be displayed when the checkbox is in
+// the indeterminate state (defaults to '-').
+func (c *Checkbox) SetIndeterminateRune(r rune) *Checkbox {
+	c.indeterminateRune = r
+	return c
+}
+
 // SetFormAttributes sets attributes shared by all form items.
 func (c *Checkbox) SetFormAttributes(labelWidth int, labelColor, bgColor, fieldTextColor, fieldBgColor tcell.Color) FormItem {
-	c.labelWidth = labelWidth
+	if !c.fixedLabelWidth {
+		c.labelWidth = labelWidth
+	}
 	c.labelColor = labelColor
 	c.backgroundColor = bgColor
 	c.fieldTextColor = fieldTextColor
@@ -155,6 +249,14 @@ func (c *Checkbox) SetFinishedFunc(handler func(key tcell.Key)) FormItem {
 	return c
 }
 
+// applyTheme implements the themeable interface.
+func (c *Checkbox) applyTheme(from, to *Theme) {
+	c.Box.applyTheme(from, to)
+	c.overrides.color(&c.labelColor, to.SecondaryTextColor)
+	c.overrides.color(&c.fieldBackgroundColor, to.ContrastBackgroundColor)
+	c.overrides.color(&c.fieldTextColor, to.PrimaryTextColor)
+}
+
 // Draw draws this primitive onto the screen.
 func (c *Checkbox) Draw(screen tcell.Screen) {
 	c.Box.DrawForSubclass(screen, c)
@@ -186,8 +288,14 @@ func (c *Checkbox) Draw(screen tcell.Screen) {
 	}
 	checkboxWidth := stringWidth(c.checkedString)
 	checkedString := c.checkedString
-	if !c.checked {
+	switch c.state {
+	case CheckboxUnchecked:
 		checkedString = strings.Repeat(" ", checkboxWidth)
+	case CheckboxIndeterminate:
+		checkedString = string(c.indeterminateRune)
+		if width := stringWidth(checkedString); width < checkboxWidth {
+			checkedString += strings.Repeat(" ", checkboxWidth-width)
+		}
 	}
 	printWithStyle(screen, checkedString, x, y, 0, checkboxWidth, AlignLeft, fieldStyle, false)
 }
@@ -201,10 +309,7 @@ func (c *Checkbox) InputHandler() func(event *tcell.EventKey, setFocus func(p Pr
 			if key == tcell.KeyRune && event.Rune() != ' ' {
 				break
 			}
-			c.checked = !c.checked
-			if c.changed != nil {
-				c.changed(c.checked)
-			}
+			c.toggle()
 		case tcell.KeyTab, tcell.KeyBacktab, tcell.KeyEscape: // We're done.
 			if c.done != nil {
 				c.done(key)
@@ -228,10 +333,7 @@ func (c *Checkbox) MouseHandler() func(action MouseAction, event *tcell.EventMou
 		// Process mouse event.
 		if action == MouseLeftClick && y == rectY {
 			setFocus(c)
-			c.checked = !c.checked
-			if c.changed != nil {
-				c.changed(c.checked)
-			}
+			c.toggle()
 			consumed = true
 		}
 
@@ -0,0 +1,69 @@
+package tview
+
+import "sync"
+
+// Clipboard is the storage used by primitives for cut, copy, and paste
+// operations. All primitives of the package share a single clipboard so that
+// text cut or copied in one primitive can be pasted into another. The default
+// clipboard keeps its text in memory, i.e. the operating system's clipboard is
+// not used. To use the operating system's clipboard, provide your own
+// implementation to [Application.SetClipboard].
+//
+// Implementations must be safe for concurrent use.
+type Clipboard interface {
+	// Get returns the text currently stored in the clipboard.
+	Get() string
+
+	// Set replaces the text stored in the clipboard.
+	Set(text string)
+}
+
+// memoryClipboard is a Clipboard which keeps its text in memory.
+type memoryClipboard struct {
+	sync.Mutex
+	text string
+}
+
+// NewMemoryClipboard returns a new clipboard which keeps its text in memory.
+// This is the default clipboard.
+func NewMemoryClipboard() Clipboard {
+	return &memoryClipboard{}
+}
+
+// Get returns the text currently stored in the clipboard.
+func (c *memoryClipboard) Get() string {
+	c.Lock()
+	defer c.Unlock()
+	return c.text
+}
+
+// Set replaces the text stored in the clipboard.
+func (c *memoryClipboard) Set(text string) {
+	c.Lock()
+	defer c.Unlock()
+	c.text = text
+}
+
+var (
+	// The clipboard shared by all primitives.
+	clipboard      = NewMemoryClipboard()
+	clipboardMutex sync.RWMutex
+)
+
+// getClipboard returns the clipboard shared by all primitives.
+func getClipboard() Clipboard {
+	clipboardMutex.RLock()
+	defer clipboardMutex.RUnlock()
+	return clipboard
+}
+
+// setClipboard replaces the clipboard shared by all primitives. A nil value
+// restores an empty in-memory clipboard.
+func setClipboard(c Clipboard) {
+	if c == nil {
+		c = NewMemoryClipboard()
+	}
+	clipboardMutex.Lock()
+	defer clipboardMutex.Unlock()
+	clipboard = c
+}
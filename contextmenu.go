@@ -0,0 +1,247 @@
+package tview
+
+import (
+	"github.com/gdamore/tcell/v2"
+)
+
+// contextMenuItem represents one item in a ContextMenu.
+type contextMenuItem struct {
+	Label  string                 // The text shown for the item.
+	Action func(source Primitive) // The function called when the item is selected.
+}
+
+// MenuItem describes one item of a context menu opened with
+// Application.ShowContextMenu().
+type MenuItem struct {
+	Label string // The text shown for the item.
+}
+
+// ContextMenu is a small pop-up list of actions which is opened at the mouse
+// cursor. It is typically attached to a primitive with Box.SetContextMenu() so
+// that it opens when the user right-clicks that primitive. The actions receive
+// the primitive the menu was opened for.
+//
+// While it is open, the context menu receives the focus and is drawn on top of
+// the application's root primitive. It closes when an item is selected, when
+// the user presses Escape, or when the user clicks outside of it. The focus is
+// then returned to the primitive the menu was opened for.
+type ContextMenu struct {
+	*Box
+
+	// The list which displays the items.
+	list *List
+
+	// The menu items.
+	items []*contextMenuItem
+
+	// Whether or not the menu is currently open.
+	open bool
+
+	// The position the menu was opened at.
+	anchorX, anchorY int
+
+	// The primitive the menu was opened for.
+	source Primitive
+}
+
+// NewContextMenu returns a new, empty context menu.
+func NewContextMenu() *ContextMenu {
+	m := &ContextMenu{
+		Box: NewBox(),
+		list: NewList().
+			ShowSecondaryText(false).
+			SetHighlightFullLine(true),
+	}
+	m.list.SetBackgroundColor(Styles.ContrastBackgroundColor)
+	m.SetBorder(true).SetBackgroundColor(Styles.ContrastBackgroundColor)
+	return m
+}
+
+// AddItem adds an item to the menu. The action is called with the primitive
+// the menu was opened for when the user selects the item. It may be nil.
+func (m *ContextMenu) AddItem(label string, action func(source Primitive)) *ContextMenu {
+	m.items = append(m.items, &contextMenuItem{
+		Label:  label,
+		Action: action,
+	})
+	m.list.AddItem(label, "", 0, nil)
+	return m
+}
+
+// GetItemCount returns the number of items in the menu.
+func (m *ContextMenu) GetItemCount() int {
+	return len(m.items)
+}
+
+// Clear removes all items from the menu.
+func (m *ContextMenu) Clear() *ContextMenu {
+	m.items = nil
+	m.list.Clear()
+	return m
+}
+
+// Open opens the menu with its top-left corner at the given screen position,
+// for the given source primitive. The menu is moved as needed to fit onto the
+// screen when it is drawn.
+func (m *ContextMenu) Open(x, y int, source Primitive) *ContextMenu {
+	m.open = true
+	m.anchorX, m.anchorY = x, y
+	m.source = source
+	m.list.SetCurrentItem(0)
+	return m
+}
+
+// Close closes the menu.
+func (m *ContextMenu) Close() *ContextMenu {
+	m.open = false
+	return m
+}
+
+// IsOpen returns whether the menu is currently open.
+func (m *ContextMenu) IsOpen() bool {
+	return m.open
+}
+
+// GetAnchor returns the screen position the menu was last opened at.
+func (m *ContextMenu) GetAnchor() (x, y int) {
+	return m.anchorX, m.anchorY
+}
+
+// GetSource returns the primitive the menu was last opened for.
+func (m *ContextMenu) GetSource() Primitive {
+	return m.source
+}
+
+// selectItem closes the menu, returns the focus to the source primitive, and
+// calls the action of the item with the given index.
+func (m *ContextMenu) selectItem(index int, setFocus func(p Primitive)) {
+	m.dismiss(setFocus)
+	if index < 0 || index >= len(m.items) {
+		return
+	}
+	if action := m.items[index].Action; action != nil {
+		action(m.source)
+	}
+}
+
+// dismiss closes the menu and returns the focus to the source primitive.
+func (m *ContextMenu) dismiss(setFocus func(p Primitive)) {
+	m.Close()
+	if m.source != nil {
+		setFocus(m.source)
+	}
+}
+
+// Draw draws this primitive onto the screen. Nothing is drawn if the menu is
+// not open.
+func (m *ContextMenu) Draw(screen tcell.Screen) {
+	if !m.open {
+		return
+	}
+
+	// Determine the size.
+	width := 0
+	for _, item := range m.items {
+		if w := TaggedStringWidth(item.Label); w > width {
+			width = w
+		}
+	}
+	width += 2
+	height := len(m.items) + 2
+
+	// Keep the menu on the screen, flipping it to the other side of the anchor
+	// if it doesn't fit.
+	screenWidth, screenHeight := screen.Size()
+	x, y := m.anchorX, m.anchorY
+	if x+width > screenWidth {
+		x = m.anchorX - width + 1
+		if x < 0 {
+			x = screenWidth - width
+		}
+	}
+	if y+height > screenHeight {
+		y = m.anchorY - height + 1
+		if y < 0 {
+			y = screenHeight - height
+		}
+	}
+	if x < 0 {
+		x = 0
+	}
+	if y < 0 {
+		y = 0
+	}
+	m.SetRect(x, y, width, height)
+
+	// Draw the frame and the items.
+	m.Box.DrawForSubclass(screen, m)
+	m.list.SetRect(m.GetInnerRect())
+	m.list.Draw(screen)
+}
+
+// Focus is called when this primitive receives focus.
+func (m *ContextMenu) Focus(delegate func(p Primitive)) {
+	m.Box.Focus(delegate)
+	m.list.Focus(delegate)
+}
+
+// Blur is called when this primitive loses focus.
+func (m *ContextMenu) Blur() {
+	m.list.Blur()
+	m.Box.Blur()
+}
+
+// InputHandler returns the handler for this primitive.
+func (m *ContextMenu) InputHandler() func(event *tcell.EventKey, setFocus func(p Primitive)) {
+	return m.WrapInputHandler(func(event *tcell.EventKey, setFocus func(p Primitive)) {
+		if !m.open {
+			return
+		}
+		switch event.Key() {
+		case tcell.KeyEscape:
+			m.dismiss(setFocus)
+		case tcell.KeyEnter:
+			m.selectItem(m.list.GetCurrentItem(), setFocus)
+		default:
+			if handler := m.list.InputHandler(); handler != nil {
+				handler(event, setFocus)
+			}
+		}
+	})
+}
+
+// MouseHandler returns the mouse handler for this primitive.
+func (m *ContextMenu) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+	return m.WrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+		if !m.open {
+			return false, nil
+		}
+		x, y := event.Position()
+
+		// Clicks outside of the menu close it.
+		if !m.InRect(x, y) {
+			switch action {
+			case MouseLeftClick, MouseRightClick, MouseMiddleClick:
+				m.dismiss(setFocus)
+				return true, nil
+			}
+			return false, m
+		}
+
+		switch action {
+		case MouseLeftClick:
+			if index := m.list.indexAtPoint(x, y); index >= 0 {
+				m.selectItem(index, setFocus)
+				return true, nil
+			}
+		case MouseMove:
+			if index := m.list.indexAtPoint(x, y); index >= 0 {
+				m.list.SetCurrentItem(index)
+			}
+		case MouseScrollUp, MouseScrollDown:
+			m.list.MouseHandler()(action, event, setFocus)
+		}
+
+		return true, m
+	})
+}
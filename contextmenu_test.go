@@ -0,0 +1,63 @@
+package tview
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestBoxContextMenu(t *testing.T) {
+	var (
+		selected string
+		source   Primitive
+	)
+	menu := NewContextMenu()
+	for _, label := range []string{"Copy", "Paste"} {
+		label := label
+		menu.AddItem(label, func(p Primitive) {
+			selected, source = label, p
+		})
+	}
+	box := NewBox()
+	box.SetContextMenu(menu)
+	app := NewApplication().SetRoot(box, true)
+	screen := startApp(t, app, 20, 8)
+
+	// Right-clicking the box opens the menu at the cursor.
+	screen.InjectMouse(5, 2, tcell.ButtonSecondary, 0)
+	screen.InjectMouse(5, 2, tcell.ButtonNone, 0)
+	waitForEvents(t, screen)
+	if !menu.IsOpen() {
+		t.Fatal("context menu was not opened")
+	}
+	if x, y := menu.GetAnchor(); x != 5 || y != 2 {
+		t.Errorf("menu opened at %d,%d, expected 5,2", x, y)
+	}
+	if x, y, _, _ := menu.GetRect(); x != 5 || y != 2 {
+		t.Errorf("menu drawn at %d,%d, expected 5,2", x, y)
+	}
+	if line := screenLine(screen, 3); !strings.HasPrefix(string([]rune(line)[6:]), "Copy") {
+		t.Errorf("row 3 is %q, expected the first item at column 6", line)
+	}
+	if app.GetFocus() != menu {
+		t.Error("context menu did not receive focus")
+	}
+
+	// The menu is operated with the keyboard.
+	screen.InjectKey(tcell.KeyDown, 0, 0)
+	screen.InjectKey(tcell.KeyEnter, 0, 0)
+	waitForEvents(t, screen)
+	if selected != "Paste" {
+		t.Errorf("selected %q, expected %q", selected, "Paste")
+	}
+	if source != box {
+		t.Errorf("action received %v, expected the clicked box", source)
+	}
+	if menu.IsOpen() {
+		t.Error("context menu is still open")
+	}
+	if app.GetFocus() != box {
+		t.Error("focus was not returned to the box")
+	}
+}
@@ -84,9 +84,9 @@ Color tags may contain not just the foreground (text) color but also the
 background color and additional flags. In fact, the full definition of a color
 tag is as follows:
 
-  [<foreground>:<background>:<flags>]
+  [<foreground>:<background>:<flags>:<url>]
 
-Each of the three fields can be left blank and trailing fields can be omitted.
+Each of the four fields can be left blank and trailing fields can be omitted.
 (Empty square brackets "[]", however, are not considered color tags.) Colors
 that are not specified will be left unchanged. A field with just a dash ("-")
 means "reset to default".
@@ -102,6 +102,13 @@ terminal):
   u: underline
   s: strike-through
 
+The URL field turns the following text into a hyperlink. Terminals which
+support OSC 8 hyperlinks make it clickable. The URL must start with a scheme
+(e.g. "https:") and may only contain the ASCII characters allowed in URLs
+(RFC 3986), i.e. other characters must be percent-encoded. A dash ends the
+link. Links are currently only supported by TextView (see
+TextView.SetLinkClickedFunc()).
+
 Examples:
 
   [yellow]Yellow text
@@ -111,6 +118,7 @@ Examples:
   [::bl]Bold, blinking text
   [::-]Colors unchanged, flags reset
   [-]Reset foreground color
+  [:::https://example.com]Link[:::-] with default style
   [-:-:-]Reset everything
   [:]No effect
   []Not a valid color tag, will print square brackets as they are
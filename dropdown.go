@@ -2,6 +2,7 @@ package tview
 
 import (
 	"strings"
+	"time"
 
 	"github.com/gdamore/tcell/v2"
 )
@@ -15,6 +16,11 @@ type dropDownOption struct {
 // DropDown implements a selection widget whose options become visible in a
 // drop-down list when activated.
 //
+// The open list floats on top of all other primitives, below the selection
+// area or above it if there is more space there. Use SetListBounds() to limit
+// its height. The list is drawn by the application so it is only visible when
+// the drop-down is part of a running application.
+//
 // See https://github.com/rivo/tview/wiki/DropDown for an example.
 type DropDown struct {
 	*Box
@@ -35,15 +41,42 @@ type DropDown struct {
 	// The text to be displayed when no option has yet been selected.
 	noSelection string
 
+	// If set to true, the list starts with an additional item which clears the
+	// selection when selected.
+	allowClear bool
+
+	// The text of the item which clears the selection.
+	clearText string
+
 	// Set to true if the options are visible and selectable.
 	open bool
 
 	// The runes typed so far to directly access one of the list items.
 	prefix string
 
+	// The time the last rune was added to the prefix.
+	prefixTime time.Time
+
+	// The time after which the prefix is discarded when the user types the next
+	// rune. A value of 0 means the prefix never expires.
+	prefixTimeout time.Duration
+
+	// An optional function which determines whether an option's text matches
+	// the prefix typed by the user. If nil, a case-insensitive prefix match is
+	// used.
+	prefixMatch func(optionText, prefix string) bool
+
 	// The list element for the options.
 	list *List
 
+	// The maximum number of options visible at once when the list is open. A
+	// value of 0 means no limit other than the screen size.
+	maxVisible int
+
+	// The position of the selection area and the width of the option list as
+	// of the last time the drop-down was drawn.
+	fieldX, fieldY, listWidth int
+
 	// The text to be displayed before the input area.
 	label string
 
@@ -65,6 +98,10 @@ type DropDown struct {
 	// the label text.
 	labelWidth int
 
+	// Set to true if the label width was set with SetLabelWidth(). It then
+	// takes precedence over the label width provided by a form.
+	fixedLabelWidth bool
+
 	// The screen width of the input area. A value of 0 means extend as much as
 	// possible.
 	fieldWidth int
@@ -82,6 +119,11 @@ type DropDown struct {
 	// selection.
 	selected func(text string, index int)
 
+	// An optional function which checks the text of the selected option when
+	// the drop-down is validated, e.g. by a form. It returns a non-nil error if
+	// the selection is not valid.
+	validate func(text string) error
+
 	dragging bool // Set to true when mouse dragging is in progress.
 }
 
@@ -94,6 +136,7 @@ func NewDropDown() *DropDown {
 		SetSelectedBackgroundColor(Styles.PrimaryTextColor).
 		SetHighlightFullLine(true).
 		SetBackgroundColor(Styles.MoreContrastBackgroundColor)
+	list.overrides = nil // These are the drop-down's theme colors.
 
 	d := &DropDown{
 		Box:                  NewBox(),
@@ -103,18 +146,78 @@ func NewDropDown() *DropDown {
 		fieldBackgroundColor: Styles.ContrastBackgroundColor,
 		fieldTextColor:       Styles.PrimaryTextColor,
 		prefixTextColor:      Styles.ContrastSecondaryTextColor,
+		clearText:            "(none)",
+		prefixTimeout:        time.Second,
 	}
+	list.dropDown = d
 
 	return d
 }
 
+// SetAllowClear sets whether the user may clear the selection. If set to true,
+// an additional item is shown at the top of the drop-down list (see
+// SetClearText()). Selecting it removes the current selection and triggers the
+// "selected" callback with an empty text and an index of -1. The drop-down then
+// shows the text for "no selection" (see SetTextOptions()).
+func (d *DropDown) SetAllowClear(allow bool) *DropDown {
+	if allow == d.allowClear {
+		return d
+	}
+	d.allowClear = allow
+	if allow {
+		d.list.InsertItem(0, d.optionPrefix+d.clearText+d.optionSuffix, "", 0, nil)
+	} else {
+		d.list.RemoveItem(0)
+	}
+	d.list.SetCurrentItem(d.listIndex(d.currentOption))
+	return d
+}
+
+// SetClearText sets the text of the item which clears the selection, see
+// SetAllowClear(). The default is "(none)".
+func (d *DropDown) SetClearText(text string) *DropDown {
+	d.clearText = text
+	if d.allowClear {
+		d.list.SetItemText(0, d.optionPrefix+text+d.optionSuffix, "")
+	}
+	return d
+}
+
+// Clear removes the current selection. This triggers the "selected" callback
+// with an empty text and an index of -1. The options are not affected.
+func (d *DropDown) Clear() *DropDown {
+	return d.SetCurrentOption(-1)
+}
+
+// listIndex returns the index of the drop-down list item for the option with
+// the given index. For negative option indices, it returns the item which
+// clears the selection, if there is one, or the first item.
+func (d *DropDown) listIndex(option int) int {
+	if option < 0 {
+		return 0
+	}
+	if d.allowClear {
+		return option + 1
+	}
+	return option
+}
+
+// optionIndex returns the index of the option shown by the drop-down list item
+// with the given index or -1 if the item clears the selection.
+func (d *DropDown) optionIndex(item int) int {
+	if d.allowClear {
+		return item - 1
+	}
+	return item
+}
+
 // SetCurrentOption sets the index of the currently selected option. This may
 // be a negative value to indicate that no option is currently selected. Calling
 // this function will also trigger the "selected" callback (if there is one).
 func (d *DropDown) SetCurrentOption(index int) *DropDown {
 	if index >= 0 && index < len(d.options) {
 		d.currentOption = index
-		d.list.SetCurrentItem(index)
+		d.list.SetCurrentItem(d.listIndex(index))
 		if d.selected != nil {
 			d.selected(d.options[index].Text, index)
 		}
@@ -152,8 +255,11 @@ func (d *DropDown) SetTextOptions(prefix, suffix, currentPrefix, currentSuffix,
 	d.noSelection = noSelection
 	d.optionPrefix = prefix
 	d.optionSuffix = suffix
-	for index := 0; index < d.list.GetItemCount(); index++ {
-		d.list.SetItemText(index, prefix+d.options[index].Text+suffix, "")
+	for index, option := range d.options {
+		d.list.SetItemText(d.listIndex(index), prefix+option.Text+suffix, "")
+	}
+	if d.allowClear {
+		d.list.SetItemText(0, prefix+d.clearText+suffix, "")
 	}
 	return d
 }
@@ -170,27 +276,43 @@ func (d *DropDown) GetLabel() string {
 }
 
 // SetLabelWidth sets the screen width of the label. A value of 0 will cause the
-// primitive to use the width of the label string.
+// primitive to use the width of the label string. A width greater than 0 also
+// overrides the label width a Form would otherwise assign to this primitive.
 func (d *DropDown) SetLabelWidth(width int) *DropDown {
 	d.labelWidth = width
+	d.fixedLabelWidth = width > 0
 	return d
 }
 
+// GetLabelWidth returns the screen width of the label. A value of 0 means the
+// width of the label string is used.
+func (d *DropDown) GetLabelWidth() int {
+	return d.labelWidth
+}
+
+// hasFixedLabelWidth implements the fixedLabelWidthItem interface.
+func (d *DropDown) hasFixedLabelWidth() bool {
+	return d.fixedLabelWidth
+}
+
 // SetLabelColor sets the color of the label.
 func (d *DropDown) SetLabelColor(color tcell.Color) *DropDown {
 	d.labelColor = color
+	d.overrides.set(&d.labelColor)
 	return d
 }
 
 // SetFieldBackgroundColor sets the background color of the options area.
 func (d *DropDown) SetFieldBackgroundColor(color tcell.Color) *DropDown {
 	d.fieldBackgroundColor = color
+	d.overrides.set(&d.fieldBackgroundColor)
 	return d
 }
 
 // SetFieldTextColor sets the text color of the options area.
 func (d *DropDown) SetFieldTextColor(color tcell.Color) *DropDown {
 	d.fieldTextColor = color
+	d.overrides.set(&d.fieldTextColor)
 	return d
 }
 
@@ -199,8 +321,29 @@ func (d *DropDown) SetFieldTextColor(color tcell.Color) *DropDown {
 // option that starts with the typed string.
 func (d *DropDown) SetPrefixTextColor(color tcell.Color) *DropDown {
 	d.prefixTextColor = color
+	d.overrides.set(&d.prefixTextColor)
+	return d
+}
+
+// SetPrefixMatchFunc sets a function which determines whether an option
+// matches the text typed by the user while the drop-down list is open
+// (type-ahead selection). The first matching option is selected. The default
+// matches options whose text starts with the typed text, ignoring case. Provide
+// nil to restore the default.
+func (d *DropDown) SetPrefixMatchFunc(match func(optionText, prefix string) bool) *DropDown {
+	d.prefixMatch = match
 	return d
 }
+
+// SetPrefixTimeout sets the time after which the text typed for type-ahead
+// selection is discarded when the user types another character. The default is
+// one second. A value of 0 means that the typed text is only discarded when an
+// option is selected or the drop-down list is closed.
+func (d *DropDown) SetPrefixTimeout(timeout time.Duration) *DropDown {
+	d.prefixTimeout = timeout
+	return d
+}
+
 func (d *DropDown) SetFieldStyle(style tcell.Style) *DropDown {
   d.fieldStyle = style
   return d
@@ -219,7 +362,9 @@ func (d *DropDown) SetListStyles(unselected, selected tcell.Style) *DropDown {
 
 // SetFormAttributes sets attributes shared by all form items.
 func (d *DropDown) SetFormAttributes(labelWidth int, labelColor, bgColor, fieldTextColor, fieldBgColor tcell.Color) FormItem {
-	d.labelWidth = labelWidth
+	if !d.fixedLabelWidth {
+		d.labelWidth = labelWidth
+	}
 	d.labelColor = labelColor
 	d.backgroundColor = bgColor
 	d.fieldTextColor = fieldTextColor
@@ -264,6 +409,9 @@ func (d *DropDown) AddOption(text string, selected func()) *DropDown {
 func (d *DropDown) SetOptions(texts []string, selected func(text string, index int)) *DropDown {
 	d.list.Clear()
 	d.options = nil
+	if d.allowClear {
+		d.list.AddItem(d.optionPrefix+d.clearText+d.optionSuffix, "", 0, nil)
+	}
 	for index, text := range texts {
 		func(t string, i int) {
 			d.AddOption(text, nil)
@@ -273,6 +421,17 @@ func (d *DropDown) SetOptions(texts []string, selected func(text string, index i
 	return d
 }
 
+// SetListBounds sets the maximum number of options which are visible at once
+// when the drop-down is open. The list scrolls if there are more options. A
+// value of 0 (the default) limits the list only by the screen size.
+func (d *DropDown) SetListBounds(maxVisible int) *DropDown {
+	if maxVisible < 0 {
+		maxVisible = 0
+	}
+	d.maxVisible = maxVisible
+	return d
+}
+
 // GetOptionCount returns the number of options in the drop-down.
 func (d *DropDown) GetList() *List {
 	return d.list
@@ -287,7 +446,7 @@ func (d *DropDown) GetOptionCount() int {
 // index is out of range.
 func (d *DropDown) RemoveOption(index int) *DropDown {
 	d.options = append(d.options[:index], d.options[index+1:]...)
-	d.list.RemoveItem(index)
+	d.list.RemoveItem(d.listIndex(index))
 	return d
 }
 
@@ -301,6 +460,27 @@ func (d *DropDown) SetSelectedFunc(handler func(text string, index int)) *DropDo
 	return d
 }
 
+// SetValidationFunc sets a handler which checks the text of the currently
+// selected option when the drop-down is validated. If no option is selected,
+// the handler receives an empty string. It returns a non-nil error if the
+// selection is not valid. Forms validate their items when the user leaves them
+// and display the error message below the field. See also [Form.Validate].
+func (d *DropDown) SetValidationFunc(handler func(text string) error) *DropDown {
+	d.validate = handler
+	return d
+}
+
+// Validate checks the currently selected option using the handler provided
+// with [DropDown.SetValidationFunc] and returns its result. If no such handler
+// was provided, nil is returned.
+func (d *DropDown) Validate() error {
+	if d.validate == nil {
+		return nil
+	}
+	_, text := d.GetCurrentOption()
+	return d.validate(text)
+}
+
 // SetDoneFunc sets a handler which is called when the user is done selecting
 // options. The callback function is provided with the key that was pressed,
 // which is one of the following:
@@ -319,6 +499,19 @@ func (d *DropDown) SetFinishedFunc(handler func(key tcell.Key)) FormItem {
 	return d
 }
 
+// applyTheme implements the themeable interface.
+func (d *DropDown) applyTheme(from, to *Theme) {
+	d.Box.applyTheme(from, to)
+	d.list.applyTheme(from, to)
+	d.list.overrides.style(&d.list.mainTextStyle, d.list.mainTextStyle.Foreground(to.PrimitiveBackgroundColor))
+	d.list.overrides.style(&d.list.selectedStyle, d.list.selectedStyle.Foreground(to.PrimitiveBackgroundColor).Background(to.PrimaryTextColor))
+	d.list.overrides.color(&d.list.backgroundColor, to.MoreContrastBackgroundColor)
+	d.overrides.color(&d.labelColor, to.SecondaryTextColor)
+	d.overrides.color(&d.fieldBackgroundColor, to.ContrastBackgroundColor)
+	d.overrides.color(&d.fieldTextColor, to.PrimaryTextColor)
+	d.overrides.color(&d.prefixTextColor, to.ContrastSecondaryTextColor)
+}
+
 // Draw draws this primitive onto the screen.
 func (d *DropDown) Draw(screen tcell.Screen) {
 	d.Box.DrawForSubclass(screen, d)
@@ -352,6 +545,11 @@ func (d *DropDown) Draw(screen tcell.Screen) {
 			maxWidth = strWidth
 		}
 	}
+	if d.allowClear {
+		if strWidth := TaggedStringWidth(d.clearText) + optionWrapWidth; strWidth > maxWidth {
+			maxWidth = strWidth
+		}
+	}
 
 	// Draw selection area.
 	fieldWidth := d.fieldWidth
@@ -387,15 +585,20 @@ func (d *DropDown) Draw(screen tcell.Screen) {
 	}
 
 	// Draw selected text.
-	if d.open && len(d.prefix) > 0 {
+	if option := d.optionIndex(d.list.GetCurrentItem()); d.open && len(d.prefix) > 0 && option >= 0 && option < len(d.options) {
 		// Show the prefix.
 		currentOptionPrefixWidth := TaggedStringWidth(d.currentOptionPrefix)
 		prefixWidth := stringWidth(d.prefix)
-		listItemText := d.options[d.list.GetCurrentItem()].Text
+		listItemText := d.options[option].Text
 		Print(screen, d.currentOptionPrefix, x, y, fieldWidth, AlignLeft, d.fieldTextColor)
-		Print(screen, d.prefix, x+currentOptionPrefixWidth, y, fieldWidth-currentOptionPrefixWidth, AlignLeft, d.prefixTextColor)
-		if len(d.prefix) < len(listItemText) {
-			Print(screen, listItemText[len(d.prefix):]+d.currentOptionSuffix, x+prefixWidth+currentOptionPrefixWidth, y, fieldWidth-prefixWidth-currentOptionPrefixWidth, AlignLeft, d.fieldTextColor)
+		if !strings.HasPrefix(strings.ToLower(listItemText), strings.ToLower(d.prefix)) {
+			// A custom match function may match elsewhere. Show the option as is.
+			Print(screen, listItemText+d.currentOptionSuffix, x+currentOptionPrefixWidth, y, fieldWidth-currentOptionPrefixWidth, AlignLeft, d.fieldTextColor)
+		} else {
+			Print(screen, d.prefix, x+currentOptionPrefixWidth, y, fieldWidth-currentOptionPrefixWidth, AlignLeft, d.prefixTextColor)
+			if len(d.prefix) < len(listItemText) {
+				Print(screen, listItemText[len(d.prefix):]+d.currentOptionSuffix, x+prefixWidth+currentOptionPrefixWidth, y, fieldWidth-prefixWidth-currentOptionPrefixWidth, AlignLeft, d.fieldTextColor)
+			}
 		}
 	} else {
 		color := d.fieldTextColor
@@ -410,26 +613,51 @@ func (d *DropDown) Draw(screen tcell.Screen) {
 		Print(screen, text, x, y, fieldWidth, AlignLeft, color)
 	}
 
-	// Draw options list.
-	if d.HasFocus() && d.open {
-		// We prefer to drop down but if there is no space, maybe drop up?
-		lx := x
-		ly := y + 1
-		lwidth := maxWidth
-		lheight := len(d.options)
-		_, sheight := screen.Size()
-		if ly+lheight >= sheight && ly-2 > lheight-ly {
-			ly = y - lheight
-			if ly < 0 {
-				ly = 0
-			}
-		}
-		if ly+lheight >= sheight {
-			lheight = sheight - ly
+	// Remember where to draw the options list. It is drawn on top of all other
+	// primitives by the application (see drawList()).
+	d.fieldX, d.fieldY, d.listWidth = x, y, maxWidth
+}
+
+// openDropDown returns the drop-down whose options list is the given primitive
+// if the list is open, or nil otherwise.
+func openDropDown(p Primitive) *DropDown {
+	if list, ok := p.(*List); ok && list.dropDown != nil && list.dropDown.open {
+		return list.dropDown
+	}
+	return nil
+}
+
+// drawList draws the open options list as a floating overlay. It drops down
+// below the selection area unless there is more space above it. The list's
+// height is limited by the screen and by SetListBounds().
+func (d *DropDown) drawList(screen tcell.Screen) {
+	swidth, sheight := screen.Size()
+	lx, lwidth := d.fieldX, d.listWidth
+	if lwidth > swidth {
+		lwidth = swidth
+	}
+	if lx+lwidth > swidth {
+		lx = swidth - lwidth
+	}
+	lheight := d.list.GetItemCount()
+	if d.maxVisible > 0 && lheight > d.maxVisible {
+		lheight = d.maxVisible
+	}
+	below, above := sheight-d.fieldY-1, d.fieldY
+	ly := d.fieldY + 1
+	if lheight > below && above > below {
+		if lheight > above {
+			lheight = above
 		}
-		d.list.SetRect(lx, ly, lwidth, lheight)
-		d.list.Draw(screen)
+		ly = d.fieldY - lheight
+	} else if lheight > below {
+		lheight = below
+	}
+	if lheight <= 0 || lwidth <= 0 {
+		return
 	}
+	d.list.SetRect(lx, ly, lwidth, lheight)
+	d.list.Draw(screen)
 }
 
 // InputHandler returns the handler for this primitive.
@@ -450,8 +678,7 @@ func (d *DropDown) InputHandler() func(event *tcell.EventKey, setFocus func(p Pr
 
 			// If the first key was a letter already, it becomes part of the prefix.
 			if r := event.Rune(); key == tcell.KeyRune && r != ' ' {
-				d.prefix += string(r)
-				d.evalPrefix()
+				d.addToPrefix(r)
 			}
 
 			d.openList(setFocus)
@@ -466,12 +693,31 @@ func (d *DropDown) InputHandler() func(event *tcell.EventKey, setFocus func(p Pr
 	})
 }
 
+// addToPrefix adds the given rune to the prefix typed by the user and selects
+// the first matching option. If the prefix timeout has elapsed since the last
+// rune was typed, the prefix is started anew.
+func (d *DropDown) addToPrefix(r rune) {
+	now := time.Now()
+	if d.prefixTimeout > 0 && now.Sub(d.prefixTime) > d.prefixTimeout {
+		d.prefix = ""
+	}
+	d.prefixTime = now
+	d.prefix += string(r)
+	d.evalPrefix()
+}
+
 // evalPrefix selects an item in the drop-down list based on the current prefix.
 func (d *DropDown) evalPrefix() {
 	if len(d.prefix) > 0 {
+		match := d.prefixMatch
+		if match == nil {
+			match = func(optionText, prefix string) bool {
+				return strings.HasPrefix(strings.ToLower(optionText), strings.ToLower(prefix))
+			}
+		}
 		for index, option := range d.options {
-			if strings.HasPrefix(strings.ToLower(option.Text), d.prefix) {
-				d.list.SetCurrentItem(index)
+			if match(option.Text, d.prefix) {
+				d.list.SetCurrentItem(d.listIndex(index))
 				return
 			}
 		}
@@ -493,9 +739,17 @@ func (d *DropDown) openList(setFocus func(Primitive)) {
 		}
 
 		// An option was selected. Close the list again.
-		d.currentOption = index
+		d.currentOption = d.optionIndex(index)
 		d.closeList(setFocus)
 
+		// The selection was cleared.
+		if d.currentOption < 0 {
+			if d.selected != nil {
+				d.selected("", -1)
+			}
+			return
+		}
+
 		// Trigger "selected" event.
 		if d.selected != nil {
 			d.selected(d.options[d.currentOption].Text, d.currentOption)
@@ -505,8 +759,7 @@ func (d *DropDown) openList(setFocus func(Primitive)) {
 		}
 	}).SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
 		if event.Key() == tcell.KeyRune {
-			d.prefix += string(event.Rune())
-			d.evalPrefix()
+			d.addToPrefix(event.Rune())
 		} else if event.Key() == tcell.KeyBackspace || event.Key() == tcell.KeyBackspace2 {
 			if len(d.prefix) > 0 {
 				r := []rune(d.prefix)
@@ -536,6 +789,7 @@ func (d *DropDown) openList(setFocus func(Primitive)) {
 // from it.
 func (d *DropDown) closeList(setFocus func(Primitive)) {
 	d.open = false
+	d.prefix = ""
 	if d.list.HasFocus() {
 		setFocus(d)
 	}
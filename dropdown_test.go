@@ -0,0 +1,72 @@
+package tview
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestDropDownClearOption(t *testing.T) {
+	type selection struct {
+		text  string
+		index int
+	}
+	var selections []selection
+	dropDown := NewDropDown().
+		SetOptions([]string{"red", "green"}, nil).
+		SetAllowClear(true).
+		SetSelectedFunc(func(text string, index int) {
+			selections = append(selections, selection{text, index})
+		})
+	dropDown.SetCurrentOption(1)
+	selections = nil
+
+	// Open the list and select the "none" item at the top.
+	var focused Primitive
+	setFocus := func(p Primitive) { focused = p }
+	dropDown.InputHandler()(tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone), setFocus)
+	if focused != dropDown.list {
+		t.Fatal("drop-down list did not open")
+	}
+	if text, _ := dropDown.list.GetItemText(0); text != "(none)" {
+		t.Errorf("first item is %q, expected %q", text, "(none)")
+	}
+	dropDown.list.InputHandler()(tcell.NewEventKey(tcell.KeyHome, 0, tcell.ModNone), setFocus)
+	dropDown.list.InputHandler()(tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone), setFocus)
+	if len(selections) != 1 || selections[0] != (selection{"", -1}) {
+		t.Errorf("selecting the none item reported %v, expected index -1 and no text", selections)
+	}
+	if index, text := dropDown.GetCurrentOption(); index != -1 || text != "" {
+		t.Errorf("current option is %d %q, expected -1 and no text", index, text)
+	}
+
+	// Options map to the items below the "none" item.
+	dropDown.SetCurrentOption(0)
+	if item := dropDown.list.GetCurrentItem(); item != 1 {
+		t.Errorf("list item of the first option is %d, expected 1", item)
+	}
+}
+
+func TestDropDownClear(t *testing.T) {
+	var selections []int
+	dropDown := NewDropDown().
+		SetOptions([]string{"red", "green"}, nil).
+		SetCurrentOption(1).
+		SetSelectedFunc(func(text string, index int) {
+			if text != "" && index < 0 {
+				t.Errorf("cleared selection reported text %q", text)
+			}
+			selections = append(selections, index)
+		})
+
+	dropDown.Clear()
+	if index, text := dropDown.GetCurrentOption(); index != -1 || text != "" {
+		t.Errorf("current option is %d %q after Clear(), expected -1 and no text", index, text)
+	}
+	if len(selections) != 1 || selections[0] != -1 {
+		t.Errorf("Clear() reported %v, expected a single -1", selections)
+	}
+	if count := dropDown.GetOptionCount(); count != 2 {
+		t.Errorf("Clear() left %d options, expected 2", count)
+	}
+}
@@ -19,6 +19,8 @@ type flexItem struct {
 	FixedSize  int       // The item's fixed size which may not be changed, 0 if it has no fixed size.
 	Proportion int       // The item's proportion.
 	Focus      bool      // Whether or not this item attracts the layout's focus.
+	MinSize    int       // The item's minimum size, 0 if it has no minimum size.
+	MaxSize    int       // The item's maximum size, 0 if it has no maximum size.
 }
 
 // Flex is a basic implementation of the Flexbox layout. The contained
@@ -39,6 +41,13 @@ type Flex struct {
 	// If set to true, Flex will use the entire screen as its available space
 	// instead its box dimensions.
 	fullScreen bool
+
+	// The number of blank cells between two adjacent visible items.
+	gap int
+
+	// Whether or not the arrow keys along the flex direction move the focus
+	// between items, and whether this wraps around at the ends.
+	arrowNavigation, arrowWrap bool
 }
 
 
@@ -77,6 +86,95 @@ func (f *Flex) SetFullScreen(fullScreen bool) *Flex {
 	return f
 }
 
+// SetGap sets the number of blank cells inserted between two adjacent items
+// along the flex direction. No gap is inserted before the first or after the
+// last item. Items which are not visible (see Box.SetVisible()) take no space
+// and don't receive gaps either, so hiding an item doesn't result in a double
+// gap. The space taken by the gaps is not available to items with a
+// proportional size.
+func (f *Flex) SetGap(gap int) *Flex {
+	if gap < 0 {
+		gap = 0
+	}
+	f.gap = gap
+	return f
+}
+
+// GetGap returns the number of blank cells between two adjacent items.
+func (f *Flex) GetGap() int {
+	return f.gap
+}
+
+// SetArrowNavigation sets whether the arrow keys along the flex direction
+// move the focus between the items: Up and Down for FlexRow, Left and Right for
+// FlexColumn. Items which are nil, not visible, or disabled are skipped. At the
+// ends, the focus stays where it is unless wrapping is enabled with
+// SetArrowNavigationWrap().
+//
+// While this is enabled, these arrow keys are not passed on to the focused
+// item, so it is best suited for items which don't use them, e.g. buttons or
+// checkboxes. Items which move a cursor or a selection with these keys lose
+// that ability, e.g. input fields in a FlexColumn (Left and Right) or lists in
+// a FlexRow (Up and Down). The default is false.
+func (f *Flex) SetArrowNavigation(enabled bool) *Flex {
+	f.arrowNavigation = enabled
+	return f
+}
+
+// SetArrowNavigationWrap sets whether the arrow keys move the focus from the
+// last item to the first item and vice versa (see SetArrowNavigation()).
+func (f *Flex) SetArrowNavigationWrap(wrap bool) *Flex {
+	f.arrowWrap = wrap
+	return f
+}
+
+// visible returns whether the given item takes part in the layout. Empty items
+// (nil primitives) do, hidden primitives don't.
+func (f *Flex) visible(item *flexItem) bool {
+	return item.Item == nil || item.Item.IsVisible()
+}
+
+// focusable returns whether the focus can be moved to the given item with the
+// arrow keys.
+func (f *Flex) focusable(item *flexItem) bool {
+	if item.Item == nil || !item.Item.IsVisible() {
+		return false
+	}
+	if d, ok := item.Item.(interface{ IsDisabled() bool }); ok && d.IsDisabled() {
+		return false
+	}
+	return true
+}
+
+// moveFocus moves the focus from the item which currently has it to the next
+// focusable item in the given direction (1 or -1), if there is one.
+func (f *Flex) moveFocus(step int, setFocus func(p Primitive)) {
+	current := -1
+	for index, item := range f.items {
+		if item.Item != nil && item.Item.HasFocus() {
+			current = index
+			break
+		}
+	}
+	if current < 0 {
+		return
+	}
+	index := current
+	for count := 1; count < len(f.items); count++ {
+		index += step
+		if index < 0 || index >= len(f.items) {
+			if !f.arrowWrap {
+				return
+			}
+			index = (index + len(f.items)) % len(f.items)
+		}
+		if f.focusable(f.items[index]) {
+			setFocus(f.items[index].Item)
+			return
+		}
+	}
+}
+
 // AddItem adds a new item to the container. The "fixedSize" argument is a width
 // or height that may not be changed by the layout algorithm. A value of 0 means
 // that its size is flexible and may be changed. The "proportion" argument
@@ -91,6 +189,8 @@ func (f *Flex) SetFullScreen(fullScreen bool) *Flex {
 //
 // You can provide a nil value for the primitive. This will still consume screen
 // space but nothing will be drawn.
+//
+// To limit the size of an item, use AddItemEx() instead.
 func (f *Flex) AddItem(
 	item Primitive,
 	fixedSize, proportion int,
@@ -108,6 +208,43 @@ func (f *Flex) AddItem(
 	return f
 }
 
+// AddItemEx adds a new item to the container, like AddItem(), but with
+// additional size constraints. "minSize" and "maxSize" limit the size the item
+// receives during layout. A value of 0 means that there is no such limit.
+//
+// Sizes are resolved in the following order:
+//
+//  1. Items with a fixed size receive that size, limited by "maxSize".
+//  2. If the available space is not enough for the fixed items and the minimum
+//     sizes of the proportional items, fixed items with a "minSize" shrink
+//     (evenly, one cell at a time) towards that minimum. Fixed items without a
+//     "minSize" never shrink.
+//  3. The remaining space is distributed among the proportional items. Items
+//     whose share would violate their "minSize" or "maxSize" receive that limit
+//     instead and the rest of the space is distributed among the other items
+//     again.
+//
+// If the constraints cannot be met in the available space, items may extend
+// beyond the container's area, just like fixed items do.
+func (f *Flex) AddItemEx(
+	item Primitive,
+	fixedSize, proportion, minSize, maxSize int,
+	focus bool,
+) *Flex {
+	f.items = append(
+		f.items,
+		&flexItem{
+			Item:       item,
+			FixedSize:  fixedSize,
+			Proportion: proportion,
+			Focus:      focus,
+			MinSize:    minSize,
+			MaxSize:    maxSize,
+		},
+	)
+	return f
+}
+
 // RemoveItem removes all items for the given primitive from the container,
 // keeping the order of the remaining items intact.
 func (f *Flex) RemoveItem(p Primitive) *Flex {
@@ -119,6 +256,34 @@ func (f *Flex) RemoveItem(p Primitive) *Flex {
 	return f
 }
 
+// MoveItem moves the item at index "from" to index "to", shifting the items in
+// between by one position. The item keeps its size settings and its "focus"
+// flag, and a focused primitive keeps its focus. The new layout is applied
+// the next time the container is drawn. Out of range indices are ignored.
+func (f *Flex) MoveItem(from, to int) *Flex {
+	if from < 0 || from >= len(f.items) || to < 0 || to >= len(f.items) || from == to {
+		return f
+	}
+	item := f.items[from]
+	if from < to {
+		copy(f.items[from:to], f.items[from+1:to+1])
+	} else {
+		copy(f.items[to+1:from+1], f.items[to:from])
+	}
+	f.items[to] = item
+	return f
+}
+
+// SwapItems exchanges the positions of the items at indices "i" and "j". See
+// MoveItem() for details.
+func (f *Flex) SwapItems(i, j int) *Flex {
+	if i < 0 || i >= len(f.items) || j < 0 || j >= len(f.items) {
+		return f
+	}
+	f.items[i], f.items[j] = f.items[j], f.items[i]
+	return f
+}
+
 // GetItemCount returns the number of items in this container.
 func (f *Flex) GetItemCount() int {
 	return len(f.items)
@@ -192,35 +357,35 @@ func (f *Flex) Draw(screen tcell.Screen) {
 
 	// How much space can we distribute?
 	x, y, width, height := f.GetInnerRect()
-	var proportionSum int
 	distSize := width
 	if f.direction == FlexRow {
 		distSize = height
 	}
+	var visibleItems int
 	for _, item := range f.items {
-		if item.FixedSize > 0 {
-			distSize -= item.FixedSize
-		} else {
-			proportionSum += item.Proportion
+		if f.visible(item) {
+			visibleItems++
 		}
 	}
+	if visibleItems > 1 {
+		distSize -= f.gap * (visibleItems - 1)
+	}
+	sizes := f.sizes(distSize)
 
 	// Calculate positions and draw items.
 	pos := x
 	if f.direction == FlexRow {
 		pos = y
 	}
-	for _, item := range f.items {
-		size := item.FixedSize
-		if size <= 0 {
-			if proportionSum > 0 {
-				size = distSize * item.Proportion / proportionSum
-				distSize -= size
-				proportionSum -= item.Proportion
-			} else {
-				size = 0
+	var gap bool
+	for index, item := range f.items {
+		if f.visible(item) {
+			if gap {
+				pos += f.gap
 			}
+			gap = true
 		}
+		size := sizes[index]
 		if item.Item != nil {
 			if f.direction == FlexColumn {
 				item.Item.SetRect(pos, y, size, height)
@@ -240,6 +405,93 @@ func (f *Flex) Draw(screen tcell.Screen) {
 	}
 }
 
+// sizes returns the sizes of all items along the flex direction when the given
+// space is distributed among them. See AddItemEx() for the order in which the
+// item sizes are resolved.
+func (f *Flex) sizes(distSize int) []int {
+	sizes := make([]int, len(f.items))
+
+	// Fixed sizes first.
+	var (
+		shrinkable []int
+		minSum     int
+	)
+	for index, item := range f.items {
+		if !f.visible(item) {
+			continue // Hidden items take no space.
+		}
+		if item.FixedSize <= 0 {
+			if item.MinSize > 0 {
+				minSum += item.MinSize
+			}
+			continue
+		}
+		size := item.FixedSize
+		if item.MaxSize > 0 && size > item.MaxSize {
+			size = item.MaxSize
+		}
+		sizes[index] = size
+		distSize -= size
+		if item.MinSize > 0 && item.MinSize < size {
+			shrinkable = append(shrinkable, index)
+		}
+	}
+
+	// Shrink fixed items if there is not enough space.
+	for distSize < minSum && len(shrinkable) > 0 {
+		for position := 0; position < len(shrinkable) && distSize < minSum; {
+			index := shrinkable[position]
+			sizes[index]--
+			distSize++
+			if sizes[index] <= f.items[index].MinSize {
+				shrinkable = append(shrinkable[:position], shrinkable[position+1:]...)
+				continue
+			}
+			position++
+		}
+	}
+
+	// Distribute the rest among proportional items, clamping them to their
+	// limits until no more limits are violated.
+	clamped := make(map[int]bool)
+	for {
+		remaining, proportionSum := distSize, 0
+		for index, item := range f.items {
+			if item.FixedSize > 0 || !f.visible(item) {
+				continue
+			}
+			if clamped[index] {
+				remaining -= sizes[index]
+			} else {
+				proportionSum += item.Proportion
+			}
+		}
+		var violated bool
+		for index, item := range f.items {
+			if item.FixedSize > 0 || clamped[index] || !f.visible(item) {
+				continue
+			}
+			size := 0
+			if proportionSum > 0 {
+				size = remaining * item.Proportion / proportionSum
+				remaining -= size
+				proportionSum -= item.Proportion
+			}
+			if item.MinSize > 0 && size < item.MinSize {
+				size = item.MinSize
+				clamped[index], violated = true, true
+			} else if item.MaxSize > 0 && size > item.MaxSize {
+				size = item.MaxSize
+				clamped[index], violated = true, true
+			}
+			sizes[index] = size
+		}
+		if !violated {
+			return sizes
+		}
+	}
+}
+
 // Focus is called when this primitive receives focus.
 func (f *Flex) Focus(delegate func(p Primitive)) {
 	for _, item := range f.items {
@@ -289,6 +541,22 @@ func (f *Flex) MouseHandler() func(action MouseAction, event *tcell.EventMouse,
 func (f *Flex) InputHandler() func(event *tcell.EventKey, setFocus func(p Primitive)) {
 	return f.WrapInputHandler(
 		func(event *tcell.EventKey, setFocus func(p Primitive)) {
+			// Move the focus between items.
+			if f.arrowNavigation && event.Modifiers() == tcell.ModNone {
+				previous, next := tcell.KeyLeft, tcell.KeyRight
+				if f.direction == FlexRow {
+					previous, next = tcell.KeyUp, tcell.KeyDown
+				}
+				switch event.Key() {
+				case previous:
+					f.moveFocus(-1, setFocus)
+					return
+				case next:
+					f.moveFocus(1, setFocus)
+					return
+				}
+			}
+
 			for _, item := range f.items {
 				if item.Item != nil && item.Item.HasFocus() {
 					if handler := item.Item.InputHandler(); handler != nil {
@@ -0,0 +1,109 @@
+package tview
+
+import "testing"
+
+// flexItemRects returns the rectangles of the given primitives as
+// {x, y, width, height}.
+func flexItemRects(items ...Primitive) [][4]int {
+	rects := make([][4]int, len(items))
+	for index, item := range items {
+		x, y, width, height := item.GetRect()
+		rects[index] = [4]int{x, y, width, height}
+	}
+	return rects
+}
+
+func TestFlexGap(t *testing.T) {
+	screen := newTestScreen(t, 20, 5)
+	a, b, c := NewBox(), NewBox(), NewBox()
+	flex := NewFlex().SetGap(2).
+		AddItem(a, 4, 0, false).
+		AddItem(b, 0, 1, false).
+		AddItem(c, 4, 0, false)
+	flex.SetRect(0, 0, 20, 5)
+
+	// Gaps between items only, the proportional item gets the rest.
+	flex.Draw(screen)
+	expected := [][4]int{{0, 0, 4, 5}, {6, 0, 8, 5}, {16, 0, 4, 5}}
+	if rects := flexItemRects(a, b, c); rects[0] != expected[0] || rects[1] != expected[1] || rects[2] != expected[2] {
+		t.Errorf("items are at %v, expected %v", rects, expected)
+	}
+
+	// A hidden item takes no space and leaves no double gap.
+	b.SetVisible(false)
+	flex.Draw(screen)
+	if x, _, _, _ := c.GetRect(); x != 6 {
+		t.Errorf("item after a hidden item starts at %d, expected 6", x)
+	}
+	a.SetVisible(false)
+	flex.Draw(screen)
+	if x, _, _, _ := c.GetRect(); x != 0 {
+		t.Errorf("only visible item starts at %d, expected 0", x)
+	}
+
+	// Rows use the gap, too.
+	a.SetVisible(true)
+	b.SetVisible(true)
+	flex.SetDirection(FlexRow).SetGap(1).Clear().
+		AddItem(a, 1, 0, false).
+		AddItem(b, 0, 1, false).
+		AddItem(c, 1, 0, false)
+	flex.Draw(screen)
+	expected = [][4]int{{0, 0, 20, 1}, {0, 2, 20, 1}, {0, 4, 20, 1}}
+	if rects := flexItemRects(a, b, c); rects[0] != expected[0] || rects[1] != expected[1] || rects[2] != expected[2] {
+		t.Errorf("rows are at %v, expected %v", rects, expected)
+	}
+}
+
+func TestFlexAddItemExOverconstrained(t *testing.T) {
+	screen := newTestScreen(t, 10, 1)
+	a, b := NewBox(), NewBox()
+	flex := NewFlex().
+		AddItemEx(a, 6, 0, 3, 0, false).
+		AddItemEx(b, 0, 1, 6, 0, false)
+	flex.SetRect(0, 0, 10, 1)
+
+	// The fixed item shrinks towards its minimum to make room.
+	flex.Draw(screen)
+	expected := [][4]int{{0, 0, 4, 1}, {4, 0, 6, 1}}
+	if rects := flexItemRects(a, b); rects[0] != expected[0] || rects[1] != expected[1] {
+		t.Errorf("items are at %v, expected %v", rects, expected)
+	}
+
+	// If the minimums can't be met, items extend beyond the container.
+	flex.Clear().
+		AddItemEx(a, 6, 0, 5, 0, false).
+		AddItemEx(b, 0, 1, 8, 0, false)
+	flex.Draw(screen)
+	expected = [][4]int{{0, 0, 5, 1}, {5, 0, 8, 1}}
+	if rects := flexItemRects(a, b); rects[0] != expected[0] || rects[1] != expected[1] {
+		t.Errorf("items are at %v, expected %v", rects, expected)
+	}
+}
+
+func TestFlexAddItemExUnderconstrained(t *testing.T) {
+	screen := newTestScreen(t, 20, 1)
+	a, b, c := NewBox(), NewBox(), NewBox()
+	flex := NewFlex().
+		AddItemEx(a, 0, 1, 0, 5, false).
+		AddItemEx(b, 0, 1, 0, 0, false).
+		AddItemEx(c, 8, 0, 0, 4, false)
+	flex.SetRect(0, 0, 20, 1)
+
+	// Capped items give their excess to the others.
+	flex.Draw(screen)
+	expected := [][4]int{{0, 0, 5, 1}, {5, 0, 11, 1}, {16, 0, 4, 1}}
+	if rects := flexItemRects(a, b, c); rects[0] != expected[0] || rects[1] != expected[1] || rects[2] != expected[2] {
+		t.Errorf("items are at %v, expected %v", rects, expected)
+	}
+
+	// If all items are capped, the rest of the space stays empty.
+	flex.Clear().
+		AddItemEx(a, 0, 1, 0, 5, false).
+		AddItemEx(b, 0, 3, 0, 6, false)
+	flex.Draw(screen)
+	expected = [][4]int{{0, 0, 5, 1}, {5, 0, 6, 1}}
+	if rects := flexItemRects(a, b); rects[0] != expected[0] || rects[1] != expected[1] {
+		t.Errorf("items are at %v, expected %v", rects, expected)
+	}
+}
@@ -1,6 +1,8 @@
 package tview
 
 import (
+	"time"
+
 	"github.com/gdamore/tcell/v2"
 )
 
@@ -33,11 +35,34 @@ type FormItem interface {
 	SetFinishedFunc(handler func(key tcell.Key)) FormItem
 }
 
+// formItemValidator is implemented by form items which can be validated, e.g.
+// InputField and DropDown. Validate returns a non-nil error if the item's
+// current value is not valid. Custom form items may implement this interface
+// to take part in form validation.
+type formItemValidator interface {
+	Validate() error
+}
+
+// fixedLabelWidthItem is implemented by form items whose label width can be
+// set explicitly, e.g. with InputField.SetLabelWidth(). The form does not
+// change such label widths and leaves them out when aligning the other labels.
+type fixedLabelWidthItem interface {
+	GetLabelWidth() int
+	hasFixedLabelWidth() bool
+}
+
 // Form allows you to combine multiple one-line form elements into a vertical
 // or horizontal layout. Form elements include types such as InputField or
 // Checkbox. These elements can be optionally followed by one or more buttons
 // for which you can define form-wide actions (e.g. Save, Clear, Cancel).
 //
+// Items which provide a validation function (e.g. with
+// InputField.SetValidationFunc()) are validated when the user leaves them. If
+// an item is not valid, the error message is shown in the row below it until
+// the problem is fixed. All items can be validated at once with Validate().
+// Buttons may be set up to be selectable only when all items are valid, see
+// SetButtonRequiresValid().
+//
 // See https://github.com/rivo/tview/wiki/Form for an example.
 type Form struct {
 	*Box
@@ -58,6 +83,10 @@ type Form struct {
 	// The number of empty rows between items.
 	itemPadding int
 
+	// If set to true, labels in vertical layouts are padded to the width of the
+	// longest label so that all fields align.
+	labelAlign bool
+
 	// The index of the item or button which has focus. (Items are counted first,
 	// buttons are counted last.) This is only used when the form itself receives
 	// focus so that the last element that had focus keeps it.
@@ -78,8 +107,21 @@ type Form struct {
 	// The color of the button text.
 	buttonTextColor tcell.Color
 
+	// The color of error messages shown for invalid items.
+	errorTextColor tcell.Color
+
+	// The validation errors of items which were found to be invalid when the
+	// user last left them (or when the form was last validated).
+	itemErrors map[FormItem]error
+
+	// The buttons which can only be selected when all items are valid.
+	requireValid map[*Button]bool
+
 	// An optional function which is called when the user hits Escape.
 	cancel func()
+
+	// The vertical scroll offset as of the last call to Draw().
+	scrollOffset int
 }
 
 // NewForm returns a new form.
@@ -89,11 +131,15 @@ func NewForm() *Form {
 	f := &Form{
 		Box:                   box,
 		itemPadding:           1,
+		labelAlign:            true,
 		labelColor:            Styles.SecondaryTextColor,
 		fieldBackgroundColor:  Styles.ContrastBackgroundColor,
 		fieldTextColor:        Styles.PrimaryTextColor,
 		buttonBackgroundColor: Styles.ContrastBackgroundColor,
 		buttonTextColor:       Styles.PrimaryTextColor,
+		errorTextColor:        tcell.ColorRed,
+		itemErrors:            make(map[FormItem]error),
+		requireValid:          make(map[*Button]bool),
 	}
 
 	return f
@@ -110,27 +156,50 @@ func (f *Form) SetItemPadding(padding int) *Form {
 // SetHorizontal sets the direction the form elements are laid out. If set to
 // true, instead of positioning them from top to bottom (the default), they are
 // positioned from left to right, moving into the next row if there is not
-// enough space.
+// enough space. The buttons follow the last item in the same way. Rows are two
+// cells apart to leave room for error messages (see Validate()).
+//
+// In horizontal layouts, each item's width is its label width plus one space
+// plus its field width (DefaultFormFieldWidth for flexible fields), and items
+// are separated by the item padding (see SetItemPadding()). An item which does
+// not fit into an empty row is truncated. The focus order (e.g. when pressing
+// Tab) is always the order in which items were added, which is also the order
+// in which they appear on screen.
 func (f *Form) SetHorizontal(horizontal bool) *Form {
 	f.horizontal = horizontal
 	return f
 }
 
+// SetLabelAlign sets whether labels in vertical layouts are padded to the
+// screen width of the longest label so that the fields of all items start in
+// the same column (the default). If set to false, each label uses its own
+// width. Items whose label width was set explicitly (e.g. with
+// InputField.SetLabelWidth()) keep that width in either case and are not
+// considered when determining the longest label. Labels in horizontal layouts
+// are never padded.
+func (f *Form) SetLabelAlign(align bool) *Form {
+	f.labelAlign = align
+	return f
+}
+
 // SetLabelColor sets the color of the labels.
 func (f *Form) SetLabelColor(color tcell.Color) *Form {
 	f.labelColor = color
+	f.overrides.set(&f.labelColor)
 	return f
 }
 
 // SetFieldBackgroundColor sets the background color of the input areas.
 func (f *Form) SetFieldBackgroundColor(color tcell.Color) *Form {
 	f.fieldBackgroundColor = color
+	f.overrides.set(&f.fieldBackgroundColor)
 	return f
 }
 
 // SetFieldTextColor sets the text color of the input areas.
 func (f *Form) SetFieldTextColor(color tcell.Color) *Form {
 	f.fieldTextColor = color
+	f.overrides.set(&f.fieldTextColor)
 	return f
 }
 
@@ -144,12 +213,21 @@ func (f *Form) SetButtonsAlign(align int) *Form {
 // SetButtonBackgroundColor sets the background color of the buttons.
 func (f *Form) SetButtonBackgroundColor(color tcell.Color) *Form {
 	f.buttonBackgroundColor = color
+	f.overrides.set(&f.buttonBackgroundColor)
 	return f
 }
 
 // SetButtonTextColor sets the color of the button texts.
 func (f *Form) SetButtonTextColor(color tcell.Color) *Form {
 	f.buttonTextColor = color
+	f.overrides.set(&f.buttonTextColor)
+	return f
+}
+
+// SetErrorTextColor sets the color of the error messages shown below invalid
+// items.
+func (f *Form) SetErrorTextColor(color tcell.Color) *Form {
+	f.errorTextColor = color
 	return f
 }
 
@@ -224,6 +302,17 @@ func (f *Form) AddCheckbox(label string, checked bool, changed func(checked bool
 	return f
 }
 
+// AddCalendar adds a date picker to the form. It has a label, an initial date,
+// and an (optional) callback function which is invoked when the user selects a
+// date.
+func (f *Form) AddCalendar(label string, date time.Time, selected func(date time.Time)) *Form {
+	f.items = append(f.items, NewCalendar().
+		SetLabel(label).
+		SetDate(date).
+		SetSelectedFunc(selected))
+	return f
+}
+
 // AddButton adds a new button to the form. The "selected" function is called
 // when the user selects this button. It may be nil.
 func (f *Form) AddButton(label string, selected func()) *Form {
@@ -241,6 +330,9 @@ func (f *Form) GetButton(index int) *Button {
 // RemoveButton removes the button at the specified position, starting with 0
 // for the button that was added first.
 func (f *Form) RemoveButton(index int) *Form {
+	button := f.buttons[index]
+	delete(f.requireValid, button)
+	button.invalid = false
 	f.buttons = append(f.buttons[:index], f.buttons[index+1:]...)
 	return f
 }
@@ -266,6 +358,7 @@ func (f *Form) GetButtonIndex(label string) int {
 // specified.
 func (f *Form) Clear(includeButtons bool) *Form {
 	f.items = nil
+	f.itemErrors = make(map[FormItem]error)
 	if includeButtons {
 		f.ClearButtons()
 	}
@@ -275,7 +368,11 @@ func (f *Form) Clear(includeButtons bool) *Form {
 
 // ClearButtons removes all buttons from the form.
 func (f *Form) ClearButtons() *Form {
+	for _, button := range f.buttons {
+		button.invalid = false
+	}
 	f.buttons = nil
+	f.requireValid = make(map[*Button]bool)
 	return f
 }
 
@@ -311,6 +408,7 @@ func (f *Form) GetFormItem(index int) FormItem {
 // index 0. Elements are referenced in the order they were added. Buttons are
 // not included.
 func (f *Form) RemoveFormItem(index int) *Form {
+	delete(f.itemErrors, f.items[index])
 	f.items = append(f.items[:index], f.items[index+1:]...)
 	return f
 }
@@ -352,6 +450,86 @@ func (f *Form) GetFocusedItemIndex() (formItem, button int) {
 	return -1, index - len(f.items)
 }
 
+// SetButtonRequiresValid sets whether the button at the specified 0-based index
+// can only be selected when all form items are valid. Such buttons are dimmed
+// and ignore the user's selection as long as at least one item fails
+// validation. This is typically used for a "Submit" or "Save" button.
+func (f *Form) SetButtonRequiresValid(index int, requiresValid bool) *Form {
+	button := f.buttons[index]
+	if requiresValid {
+		f.requireValid[button] = true
+	} else {
+		delete(f.requireValid, button)
+	}
+	f.updateButtons()
+	return f
+}
+
+// Validate validates all form items and returns the errors of the items which
+// are not valid, in the order of the items. If all items are valid, nil is
+// returned. The error messages are shown below the invalid items.
+//
+// Items are validated if they provide a Validate() method returning an error,
+// which InputField and DropDown do (see InputField.SetValidationFunc() and
+// DropDown.SetValidationFunc()). Other items are always considered valid.
+func (f *Form) Validate() []error {
+	var errs []error
+	for _, item := range f.items {
+		if err := f.validateItem(item); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	f.updateButtons()
+	return errs
+}
+
+// GetFormItemError returns the error of the form item at the given position
+// as determined when it was last validated, or nil if the item was valid or
+// has not been validated yet. The error is updated when the user leaves the
+// item and when Validate() is called.
+func (f *Form) GetFormItemError(index int) error {
+	return f.itemErrors[f.items[index]]
+}
+
+// validateItem validates the given form item, remembers its error (if any) for
+// display, and returns it.
+func (f *Form) validateItem(item FormItem) error {
+	validator, ok := item.(formItemValidator)
+	if !ok {
+		return nil
+	}
+	err := validator.Validate()
+	if err != nil {
+		f.itemErrors[item] = err
+	} else {
+		delete(f.itemErrors, item)
+	}
+	return err
+}
+
+// isValid returns whether all form items are currently valid. Unlike
+// Validate(), this does not change the error messages shown.
+func (f *Form) isValid() bool {
+	for _, item := range f.items {
+		if validator, ok := item.(formItemValidator); ok && validator.Validate() != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// updateButtons disables the buttons which require all items to be valid if
+// at least one item is not valid, and enables them otherwise.
+func (f *Form) updateButtons() {
+	if len(f.requireValid) == 0 {
+		return
+	}
+	valid := f.isValid()
+	for button := range f.requireValid {
+		button.invalid = !valid
+	}
+}
+
 // SetCancelFunc sets a handler which is called when the user hits the Escape
 // key.
 func (f *Form) SetCancelFunc(callback func()) *Form {
@@ -359,6 +537,17 @@ func (f *Form) SetCancelFunc(callback func()) *Form {
 	return f
 }
 
+// applyTheme implements the themeable interface. The form items and buttons
+// are themed separately.
+func (f *Form) applyTheme(from, to *Theme) {
+	f.Box.applyTheme(from, to)
+	f.overrides.color(&f.labelColor, to.SecondaryTextColor)
+	f.overrides.color(&f.fieldBackgroundColor, to.ContrastBackgroundColor)
+	f.overrides.color(&f.fieldTextColor, to.PrimaryTextColor)
+	f.overrides.color(&f.buttonBackgroundColor, to.ContrastBackgroundColor)
+	f.overrides.color(&f.buttonTextColor, to.PrimaryTextColor)
+}
+
 // Draw draws this primitive onto the screen.
 func (f *Form) Draw(screen tcell.Screen) {
 	f.Box.DrawForSubclass(screen, f)
@@ -375,9 +564,18 @@ func (f *Form) Draw(screen tcell.Screen) {
 	rightLimit := x + width
 	startX := x
 
-	// Find the longest label.
+	// Error messages disappear as soon as the problem is fixed.
+	for item := range f.itemErrors {
+		f.validateItem(item)
+	}
+	f.updateButtons()
+
+	// Find the longest label, not counting fixed label widths.
 	var maxLabelWidth int
 	for _, item := range f.items {
+		if item, ok := item.(fixedLabelWidthItem); ok && item.hasFixedLabelWidth() {
+			continue
+		}
 		labelWidth := TaggedStringWidth(item.GetLabel())
 		if labelWidth > maxLabelWidth {
 			maxLabelWidth = labelWidth
@@ -387,7 +585,9 @@ func (f *Form) Draw(screen tcell.Screen) {
 
 	// Calculate positions of form items.
 	positions := make([]struct{ x, y, width, height int }, len(f.items)+len(f.buttons))
+	labelWidths := make([]int, len(f.items))
 	var focusedPosition struct{ x, y, width, height int }
+	var focused Primitive
 	for index, item := range f.items {
 		// Calculate the space needed.
 		labelWidth := TaggedStringWidth(item.GetLabel())
@@ -400,21 +600,16 @@ func (f *Form) Draw(screen tcell.Screen) {
 			labelWidth++
 			itemWidth = labelWidth + fieldWidth
 		} else {
-			// We want all fields to align vertically.
-			labelWidth = maxLabelWidth
+			if f.labelAlign {
+				// We want all fields to align vertically.
+				labelWidth = maxLabelWidth
+			} else {
+				labelWidth++
+			}
 			itemWidth = width
 		}
 
-		// Advance to next line if there is no space.
-		if f.horizontal && x+labelWidth+1 >= rightLimit {
-			x = startX
-			y += 2
-		}
-
 		// Adjust the item's attributes.
-		if x+itemWidth >= rightLimit {
-			itemWidth = rightLimit - x
-		}
 		item.SetFormAttributes(
 			labelWidth,
 			f.labelColor,
@@ -423,13 +618,35 @@ func (f *Form) Draw(screen tcell.Screen) {
 			f.fieldBackgroundColor,
 		)
 
+		// Items may insist on their own label width.
+		if item, ok := item.(fixedLabelWidthItem); ok && item.hasFixedLabelWidth() {
+			if w := item.GetLabelWidth(); w != labelWidth {
+				if f.horizontal {
+					itemWidth += w - labelWidth
+				}
+				labelWidth = w
+			}
+		}
+
+		// Advance to next line if the item doesn't fit. If the row is empty,
+		// it would not help.
+		if f.horizontal && x > startX && x+itemWidth > rightLimit {
+			x = startX
+			y += 2
+		}
+		if x+itemWidth >= rightLimit {
+			itemWidth = rightLimit - x
+		}
+
 		// Save position.
 		positions[index].x = x
 		positions[index].y = y
 		positions[index].width = itemWidth
 		positions[index].height = 1
+		labelWidths[index] = labelWidth
 		if item.HasFocus() {
 			focusedPosition = positions[index]
+			focused = item
 		}
 
 		// Advance to next item.
@@ -437,6 +654,9 @@ func (f *Form) Draw(screen tcell.Screen) {
 			x += itemWidth + f.itemPadding
 		} else {
 			y += 1 + f.itemPadding
+			if f.itemErrors[item] != nil && f.itemPadding == 0 {
+				y++ // Make room for the error message.
+			}
 		}
 	}
 
@@ -469,7 +689,7 @@ func (f *Form) Draw(screen tcell.Screen) {
 		space := rightLimit - x
 		buttonWidth := buttonWidths[index]
 		if f.horizontal {
-			if space < buttonWidth-4 {
+			if x > startX && space < buttonWidth-4 {
 				x = startX
 				y += 2
 				space = width
@@ -495,19 +715,24 @@ func (f *Form) Draw(screen tcell.Screen) {
 
 		if button.HasFocus() {
 			focusedPosition = positions[buttonIndex]
+			focused = button
 		}
 
 		x += buttonWidth + 1
 	}
 
-	// Determine vertical offset based on the position of the focused item.
+	// Determine vertical offset based on the position of the focused item,
+	// unless it is not to be scrolled to.
 	var offset int
-	if focusedPosition.y+focusedPosition.height > bottomLimit {
+	if focused != nil && !scrollsToFocus(focused) {
+		offset = f.scrollOffset
+	} else if focusedPosition.y+focusedPosition.height > bottomLimit {
 		offset = focusedPosition.y + focusedPosition.height - bottomLimit
 		if focusedPosition.y-offset < topLimit {
 			offset = focusedPosition.y - topLimit
 		}
 	}
+	f.scrollOffset = offset
 
 	// Draw items.
 	for index, item := range f.items {
@@ -527,6 +752,16 @@ func (f *Form) Draw(screen tcell.Screen) {
 		} else {
 			item.Draw(screen)
 		}
+
+		// Draw the error message below the field.
+		if err := f.itemErrors[item]; err != nil && y+1 < bottomLimit {
+			errorX := positions[index].x + labelWidths[index]
+			errorWidth := positions[index].width - labelWidths[index]
+			if !f.horizontal && errorX+errorWidth < rightLimit {
+				errorWidth = rightLimit - errorX
+			}
+			Print(screen, Escape(err.Error()), errorX, y+1, errorWidth, AlignLeft, f.errorTextColor)
+		}
 	}
 
 	// Draw buttons.
@@ -559,16 +794,27 @@ func (f *Form) Focus(delegate func(p Primitive)) {
 	if f.focusedElement < 0 || f.focusedElement >= len(f.items)+len(f.buttons) {
 		f.focusedElement = 0
 	}
+	f.skipDisabledButtons(1)
 	handler := func(key tcell.Key) {
+		// Validate the item the user is leaving.
+		if f.focusedElement >= 0 && f.focusedElement < len(f.items) {
+			f.validateItem(f.items[f.focusedElement])
+		}
+
 		switch key {
 		case tcell.KeyTab, tcell.KeyEnter:
 			f.focusedElement++
+			if f.focusedElement >= len(f.items)+len(f.buttons) {
+				f.focusedElement = 0
+			}
+			f.skipDisabledButtons(1)
 			f.Focus(delegate)
 		case tcell.KeyBacktab:
 			f.focusedElement--
 			if f.focusedElement < 0 {
 				f.focusedElement = len(f.items) + len(f.buttons) - 1
 			}
+			f.skipDisabledButtons(-1)
 			f.Focus(delegate)
 		case tcell.KeyEscape:
 			if f.cancel != nil {
@@ -593,6 +839,21 @@ func (f *Form) Focus(delegate func(p Primitive)) {
 	}
 }
 
+// skipDisabledButtons moves the focused element in the given direction (1 or
+// -1), wrapping around, until it is not a disabled button. The focused element
+// remains unchanged if all elements are disabled buttons.
+func (f *Form) skipDisabledButtons(step int) {
+	count := len(f.items) + len(f.buttons)
+	index := f.focusedElement
+	for i := 0; i < count; i++ {
+		if index < len(f.items) || !f.buttons[index-len(f.items)].IsDisabled() {
+			f.focusedElement = index
+			return
+		}
+		index = (index + step + count) % count
+	}
+}
+
 // HasFocus returns whether or not this primitive has focus.
 func (f *Form) HasFocus() bool {
 	if f.focusIndex() >= 0 {
@@ -626,6 +887,10 @@ func (f *Form) MouseHandler() func(action MouseAction, event *tcell.EventMouse,
 			if consumed {
 				index := f.focusIndex()
 				if index >= 0 {
+					// Validate the item the user has left.
+					if index != f.focusedElement && f.focusedElement >= 0 && f.focusedElement < len(f.items) {
+						f.validateItem(f.items[f.focusedElement])
+					}
 					f.focusedElement = index
 				}
 			}
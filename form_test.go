@@ -0,0 +1,100 @@
+package tview
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// fieldColumn returns the first column of the given screen row whose
+// background color is the form field background color, -1 if there is none.
+func fieldColumn(screen tcell.Screen, y int) int {
+	width, _ := screen.Size()
+	for x := 0; x < width; x++ {
+		_, _, style, _ := screen.GetContent(x, y)
+		if _, bg, _ := style.Decompose(); bg == Styles.ContrastBackgroundColor {
+			return x
+		}
+	}
+	return -1
+}
+
+func TestFormLabelAlign(t *testing.T) {
+	form := NewForm().
+		AddInputField("Name", "", 5, nil, nil).
+		AddInputField("Straße", "", 5, nil, nil).
+		AddInputField("日本語", "", 5, nil, nil)
+	form.SetRect(0, 0, 30, 8)
+	screen := newTestScreen(t, 30, 8)
+
+	// Fields align after the widest label ("日本語", six cells) plus a space.
+	form.Draw(screen)
+	for index, y := range []int{1, 3, 5} {
+		if x := fieldColumn(screen, y); x != 1+6+1 {
+			t.Errorf("field %d starts at column %d, expected %d", index, x, 1+6+1)
+		}
+	}
+
+	// Without alignment, each label uses its own width.
+	form.SetLabelAlign(false)
+	screen.Clear()
+	form.Draw(screen)
+	for index, expected := range []int{1 + 4 + 1, 1 + 6 + 1, 1 + 6 + 1} {
+		if x := fieldColumn(screen, 1+2*index); x != expected {
+			t.Errorf("unaligned field %d starts at column %d, expected %d", index, x, expected)
+		}
+	}
+}
+
+func TestFormFixedLabelWidth(t *testing.T) {
+	fixed := NewInputField().SetLabel("Fixed label which is long").SetLabelWidth(3).SetFieldWidth(5).
+		SetValidationFunc(func(text string) error {
+			return errors.New("invalid")
+		})
+	form := NewForm().
+		AddInputField("A", "", 5, nil, nil).
+		AddFormItem(fixed).
+		AddInputField("Bee", "", 5, nil, nil)
+	form.SetRect(0, 0, 30, 8)
+	form.Validate()
+	screen := newTestScreen(t, 30, 8)
+	form.Draw(screen)
+
+	// The fixed label width is kept and does not widen the other labels.
+	for index, expected := range []int{1 + 3 + 1, 1 + 3, 1 + 3 + 1} {
+		if x := fieldColumn(screen, 1+2*index); x != expected {
+			t.Errorf("field %d starts at column %d, expected %d", index, x, expected)
+		}
+	}
+
+	// The error message starts below the fixed item's field.
+	if line := screenLine(screen, 4); line[1+3:1+3+7] != "invalid" {
+		t.Errorf("error row is %q, expected the message at column %d", line, 1+3)
+	}
+}
+
+func TestFormHorizontalWrap(t *testing.T) {
+	form := NewForm().SetHorizontal(true).
+		AddInputField("A", "", 5, nil, nil).
+		AddInputField("B", "", 5, nil, nil)
+	form.SetRect(0, 0, 14, 6)
+	screen := newTestScreen(t, 14, 6)
+
+	// The second field doesn't fit next to the first one and moves to the next
+	// row instead of being cut off.
+	form.Draw(screen)
+	firstX, firstY, _, _ := form.GetFormItem(0).GetRect()
+	x, y, width, _ := form.GetFormItem(1).GetRect()
+	if x != firstX || y != firstY+2 || width != 7 {
+		t.Errorf("second field is at %d,%d with width %d, expected %d,%d with width 7", x, y, width, firstX, firstY+2)
+	}
+
+	// In a wide enough form, both fields share a row.
+	form.SetRect(0, 0, 30, 6)
+	screen.SetSize(30, 6)
+	form.Draw(screen)
+	if _, y, _, _ := form.GetFormItem(1).GetRect(); y != firstY {
+		t.Errorf("second field is on row %d, expected %d", y, firstY)
+	}
+}
@@ -16,11 +16,30 @@ type gridItem struct {
 	MinGridWidth, MinGridHeight int       // The minimum grid width/height for which this item is visible.
 	Focus                       bool      // Whether or not this item attracts the layout's focus.
 
+	flexible bool // Whether or not this item expands into empty adjacent cells.
+
 	visible    bool // Whether or not this item was visible the last time the grid was drawn.
 	x, y, w, h int  // The last position of the item relative to the top-left corner of the grid. Undefined if visible is false.
 }
 type GridItem = gridItem
 
+// SetFlexibleSpan sets whether or not the item expands into empty cells to its
+// right and below it when the grid is laid out. The item first grows to the
+// right, then downwards, as long as all cells it would cover are not occupied
+// by other items. The row and column spans provided to Grid.AddItem() then
+// become the item's minimum spans. If multiple flexible items compete for the
+// same free cells, they are distributed in proportion to these minimum spans.
+func (item *GridItem) SetFlexibleSpan(flexible bool) *GridItem {
+	item.flexible = flexible
+	return item
+}
+
+// HasFlexibleSpan returns whether or not the item expands into empty adjacent
+// cells. See SetFlexibleSpan() for details.
+func (item *GridItem) HasFlexibleSpan() bool {
+	return item.flexible
+}
+
 // Grid is an implementation of a grid-based layout. It works by defining the
 // size of the rows and columns, then placing primitives into the grid.
 //
@@ -169,6 +188,7 @@ func (g *Grid) SetBorders(borders bool) *Grid {
 // SetBordersColor sets the color of the item borders.
 func (g *Grid) SetBordersColor(color tcell.Color) *Grid {
 	g.bordersColor = color
+	g.overrides.set(&g.bordersColor)
 	return g
 }
 
@@ -338,6 +358,118 @@ func (g *Grid) InputHandler() func(event *tcell.EventKey, setFocus func(p Primit
 	})
 }
 
+// gridSpan is the number of rows and columns an item occupies during layout.
+type gridSpan struct {
+	height, width int
+}
+
+// spans returns the number of rows and columns occupied by the given items in
+// a grid of the given size. These are the items' spans unless they are
+// flexible (see GridItem.SetFlexibleSpan()), in which case they grow into
+// empty adjacent cells.
+func (g *Grid) spans(items map[Primitive]*gridItem, rows, columns int) map[*gridItem]gridSpan {
+	spans := make(map[*gridItem]gridSpan, len(items))
+	var flexible []*gridItem
+	for _, item := range g.items {
+		if items[item.Item] != item {
+			continue // Not shown.
+		}
+		spans[item] = gridSpan{height: item.Height, width: item.Width}
+		if item.flexible {
+			flexible = append(flexible, item)
+		}
+	}
+	if len(flexible) == 0 {
+		return spans
+	}
+
+	// Mark the occupied cells.
+	occupied := make([][]bool, rows)
+	for row := range occupied {
+		occupied[row] = make([]bool, columns)
+	}
+	for item, span := range spans {
+		for row := item.Row; row < item.Row+span.height; row++ {
+			for column := item.Column; column < item.Column+span.width; column++ {
+				occupied[row][column] = true
+			}
+		}
+	}
+
+	// Let the items grow one row or column at a time, giving the next row or
+	// column to the item which grew least relative to its original span.
+	grow := func(horizontal bool) {
+		growth := make(map[*gridItem]int)
+		blocked := make(map[*gridItem]bool)
+		for {
+			var (
+				next  *gridItem
+				ratio float64
+			)
+			for _, item := range flexible {
+				if blocked[item] {
+					continue
+				}
+				size := item.Width
+				if !horizontal {
+					size = item.Height
+				}
+				if r := float64(growth[item]) / float64(size); next == nil || r < ratio {
+					next, ratio = item, r
+				}
+			}
+			if next == nil {
+				return
+			}
+
+			// Can the item grow by one?
+			span := spans[next]
+			fromRow, toRow, fromColumn, toColumn := next.Row+span.height, next.Row+span.height+1, next.Column, next.Column+span.width
+			if horizontal {
+				fromRow, toRow, fromColumn, toColumn = next.Row, next.Row+span.height, next.Column+span.width, next.Column+span.width+1
+			}
+			free := toRow <= rows && toColumn <= columns
+			for row := fromRow; free && row < toRow; row++ {
+				for column := fromColumn; column < toColumn; column++ {
+					if occupied[row][column] {
+						free = false
+						break
+					}
+				}
+			}
+			if !free {
+				blocked[next] = true
+				continue
+			}
+
+			// Grow.
+			for row := fromRow; row < toRow; row++ {
+				for column := fromColumn; column < toColumn; column++ {
+					occupied[row][column] = true
+				}
+			}
+			if horizontal {
+				span.width++
+			} else {
+				span.height++
+			}
+			spans[next] = span
+			growth[next]++
+		}
+	}
+	grow(true)
+	grow(false)
+
+	return spans
+}
+
+// applyTheme implements the themeable interface. The grid items are themed
+// separately.
+func (g *Grid) applyTheme(from, to *Theme) {
+	g.Box.applyTheme(from, to)
+	g.overrides.color(&g.bordersColor, to.GraphicsColor)
+}
+
 // Draw draws this primitive onto the screen.
 func (g *Grid) Draw(screen tcell.Screen) {
 	g.Box.DrawForSubclass(screen, g)
@@ -374,6 +506,7 @@ func (g *Grid) Draw(screen tcell.Screen) {
 	if rows == 0 || columns == 0 {
 		return // No content.
 	}
+	spans := g.spans(items, rows, columns)
 
 	// Where are they located?
 	rowPos := make([]int, rows)
@@ -496,19 +629,20 @@ func (g *Grid) Draw(screen tcell.Screen) {
 	for primitive, item := range items {
 		px := columnPos[item.Column]
 		py := rowPos[item.Row]
+		span := spans[item]
 		var pw, ph int
-		for index := 0; index < item.Height; index++ {
+		for index := 0; index < span.height; index++ {
 			ph += rowHeight[item.Row+index]
 		}
-		for index := 0; index < item.Width; index++ {
+		for index := 0; index < span.width; index++ {
 			pw += columnWidth[item.Column+index]
 		}
 		if g.borders {
-			pw += item.Width - 1
-			ph += item.Height - 1
+			pw += span.width - 1
+			ph += span.height - 1
 		} else {
-			pw += (item.Width - 1) * g.gapColumns
-			ph += (item.Height - 1) * g.gapRows
+			pw += (span.width - 1) * g.gapColumns
+			ph += (span.height - 1) * g.gapRows
 		}
 		item.x, item.y, item.w, item.h = px, py, pw, ph
 		item.visible = true
@@ -555,7 +689,7 @@ func (g *Grid) Draw(screen tcell.Screen) {
 	}
 
 	// The focused item must be within the visible area.
-	if focus != nil {
+	if focus != nil && scrollsToFocus(focus.Item) {
 		if focus.y+focus.h-offsetY >= height {
 			offsetY = focus.y - height + focus.h
 		}
@@ -97,6 +97,10 @@ type Image struct {
 	// the label text.
 	labelWidth int
 
+	// Set to true if the label width was set with SetLabelWidth(). It then
+	// takes precedence over the label width provided by a form.
+	fixedLabelWidth bool
+
 	// The actual image size (in cells) when it was drawn the last time.
 	lastWidth, lastHeight int
 
@@ -220,12 +224,25 @@ func (i *Image) GetLabel() string {
 }
 
 // SetLabelWidth sets the screen width of the label. A value of 0 will cause the
-// primitive to use the width of the label string.
+// primitive to use the width of the label string. A width greater than 0 also
+// overrides the label width a Form would otherwise assign to this primitive.
 func (i *Image) SetLabelWidth(width int) *Image {
 	i.labelWidth = width
+	i.fixedLabelWidth = width > 0
 	return i
 }
 
+// GetLabelWidth returns the screen width of the label. A value of 0 means the
+// width of the label string is used.
+func (i *Image) GetLabelWidth() int {
+	return i.labelWidth
+}
+
+// hasFixedLabelWidth implements the fixedLabelWidthItem interface.
+func (i *Image) hasFixedLabelWidth() bool {
+	return i.fixedLabelWidth
+}
+
 // GetFieldWidth returns this primitive's field width. This is the image's width
 // or, if the width is 0 or less, the proportional width of the image based on
 // its height as returned by [Image.GetFieldHeight]. If there is no image, 0 is
@@ -253,7 +270,9 @@ func (i *Image) GetFieldHeight() int {
 
 // SetFormAttributes sets attributes shared by all form items.
 func (i *Image) SetFormAttributes(labelWidth int, labelColor, bgColor, fieldTextColor, fieldBgColor tcell.Color) FormItem {
-	i.labelWidth = labelWidth
+	if !i.fixedLabelWidth {
+		i.labelWidth = labelWidth
+	}
 	i.backgroundColor = bgColor
 	i.SetLabelStyle(tcell.StyleDefault.Foreground(labelColor).Background(bgColor))
 	i.lastWidth, i.lastHeight = 0, 0
@@ -1,10 +1,14 @@
 package tview
 
 import (
+	"errors"
 	"math"
+	"net/url"
 	"regexp"
+	"strconv"
 	"strings"
 	"sync"
+	"unicode"
 	"unicode/utf8"
 
 	"github.com/gdamore/tcell/v2"
@@ -18,6 +22,95 @@ const (
 	AutocompletedClick           // The user selected an autocomplete entry by clicking the mouse button on it.
 )
 
+// InputType is a preset for the kind of text an input field accepts. See
+// [InputField.SetInputType].
+type InputType int
+
+// Input types for [InputField.SetInputType].
+const (
+	InputTypeText    InputType = iota // Any text (no restrictions).
+	InputTypeInteger                  // Integers, e.g. "-42".
+	InputTypeFloat                    // Floating-point numbers, e.g. "3.14".
+	InputTypeEmail                    // Email addresses, e.g. "me@example.com".
+	InputTypeURL                      // Absolute URLs, e.g. "https://example.com".
+	InputTypeHex                      // Hexadecimal numbers, e.g. "ff" or "0xff".
+)
+
+// inputFieldUndoItem is the state of an input field before an edit which can
+// be reverted with [InputField.Undo].
+type inputFieldUndoItem struct {
+	text      string
+	cursorPos int
+}
+
+// inputTypeEmail matches the (loosely defined) email addresses accepted by
+// InputTypeEmail.
+var inputTypeEmail = regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[^@\s.]+$`)
+
+// inputTypeFuncs returns the acceptance and validation functions of the given
+// input type. Both are nil for InputTypeText. The validation functions accept
+// empty text.
+func inputTypeFuncs(inputType InputType) (accept func(text string, ch rune) bool, validate func(text string) error) {
+	noSpace := func(text string, ch rune) bool {
+		return !strings.ContainsAny(text, " \t")
+	}
+	switch inputType {
+	case InputTypeInteger:
+		return InputFieldInteger, func(text string) error {
+			if _, err := strconv.Atoi(text); text != "" && err != nil {
+				return errors.New("must be an integer")
+			}
+			return nil
+		}
+	case InputTypeFloat:
+		return InputFieldFloat, func(text string) error {
+			if _, err := strconv.ParseFloat(text, 64); text != "" && err != nil {
+				return errors.New("must be a number")
+			}
+			return nil
+		}
+	case InputTypeEmail:
+		return noSpace, func(text string) error {
+			if text != "" && !inputTypeEmail.MatchString(text) {
+				return errors.New("invalid email address")
+			}
+			return nil
+		}
+	case InputTypeURL:
+		return noSpace, func(text string) error {
+			if text == "" {
+				return nil
+			}
+			if u, err := url.ParseRequestURI(text); err != nil || u.Scheme == "" || u.Host == "" {
+				return errors.New("invalid URL")
+			}
+			return nil
+		}
+	case InputTypeHex:
+		return func(text string, ch rune) bool {
+				if text == "0" || strings.EqualFold(text, "0x") {
+					return true
+				}
+				_, err := strconv.ParseUint(trimHexPrefix(text), 16, 64)
+				return err == nil
+			}, func(text string) error {
+				if _, err := strconv.ParseUint(trimHexPrefix(text), 16, 64); text != "" && err != nil {
+					return errors.New("must be a hexadecimal number")
+				}
+				return nil
+			}
+	}
+	return nil, nil
+}
+
+// trimHexPrefix removes a leading "0x" or "0X" from the given text.
+func trimHexPrefix(text string) string {
+	if len(text) >= 2 && text[0] == '0' && (text[1] == 'x' || text[1] == 'X') {
+		return text[2:]
+	}
+	return text
+}
+
 // InputField is a one-line box (three lines if there is a title) where the
 // user can enter text. Use [InputField.SetAcceptanceFunc] to accept or reject
 // input, [InputField.SetChangedFunc] to listen for changes, and
@@ -37,13 +130,32 @@ const (
 //   - Tab, Enter: Select the current autocomplete entry.
 //   - Home, Ctrl-A, Alt-a: Move to the beginning of the line.
 //   - End, Ctrl-E, Alt-e: Move to the end of the line.
-//   - Alt-left, Alt-b: Move left by one word.
-//   - Alt-right, Alt-f: Move right by one word.
+//   - Ctrl-left, Alt-left, Alt-b: Move left by one word.
+//   - Ctrl-right, Alt-right, Alt-f: Move right by one word.
 //   - Backspace: Delete the character before the cursor.
 //   - Delete: Delete the character after the cursor.
 //   - Ctrl-K: Delete from the cursor to the end of the line.
 //   - Ctrl-W: Delete the last word before the cursor.
+//   - Alt-d: Delete the next word after the cursor.
 //   - Ctrl-U: Delete the entire line.
+//   - Ctrl-Q: Copy the entire text into the clipboard.
+//   - Ctrl-X: Copy the entire text into the clipboard and delete it.
+//   - Ctrl-V: Insert the clipboard text at the cursor position.
+//   - Ctrl-Z: Undo the last change.
+//   - Ctrl-Y: Redo the last undone change.
+//
+// Consecutively typed characters are undone together while other edits, e.g.
+// deletions and pastes, are undone one by one. Use [Box.SetInputCapture] to
+// assign undo and redo to other keys, calling [InputField.Undo] and
+// [InputField.Redo].
+//
+// Words are separated by whitespace by default. Use
+// [InputField.SetWordBoundaryFunc] to change this.
+//
+// As with [TextArea], Ctrl-Q is used for copying because Ctrl-C stops the
+// application by default. The text of masked input fields is never copied into
+// the clipboard. The clipboard is shared by all primitives, see
+// [Application.SetClipboard].
 //
 // See https://github.com/rivo/tview/wiki/InputField for an example.
 type InputField struct {
@@ -71,6 +183,10 @@ type InputField struct {
 	// the label text.
 	labelWidth int
 
+	// Set to true if the label width was set with SetLabelWidth(). It then
+	// takes precedence over the label width provided by a form.
+	fixedLabelWidth bool
+
 	// The screen width of the input area. A value of 0 means extend as much as
 	// possible.
 	fieldWidth int
@@ -82,11 +198,40 @@ type InputField struct {
 	// The cursor position as a byte index into the text string.
 	cursorPos int
 
+	// The states before the edits which can be undone, oldest first, and
+	// the states before the undone edits which can be redone, most recently
+	// undone last.
+	undoStack, redoStack []inputFieldUndoItem
+
+	// The maximum number of undo steps kept. 0 means there is no limit.
+	maxUndoSteps int
+
+	// Set to true if the last edit was typing a character so that the next
+	// typed character is undone together with it.
+	undoTyping bool
+
+	// An optional function which returns whether a character separates words.
+	// If nil, whitespace separates words.
+	wordBoundary func(r rune) bool
+
 	// An optional autocomplete function which receives the current text of the
 	// input field and returns a slice of strings to be displayed in a drop-down
 	// selection.
 	autocomplete func(text string) []string
 
+	// An optional autocomplete function which receives the current text of the
+	// input field and returns a channel delivering the strings to be displayed
+	// in the drop-down selection. Used instead of "autocomplete" if set.
+	autocompleteAsync func(text string) <-chan []string
+
+	// The application used to redraw the input field when asynchronous
+	// autocomplete entries arrive, see SetAutocompleteApplication(). May be nil.
+	autocompleteApp *Application
+
+	// Incremented with every asynchronous autocomplete request so that results
+	// of superseded requests can be discarded.
+	autocompleteRequest int
+
 	// The List object which shows the selectable autocomplete entries. If not
 	// nil, the list's main texts represent the current autocomplete entries.
 	autocompleteList      *List
@@ -110,9 +255,38 @@ type InputField struct {
 	// An optional function which may reject the last character that was entered.
 	accept func(text string, ch rune) bool
 
+	// Whether or not to beep when input is rejected.
+	rejectionBeep bool
+
+	// Whether or not to draw the input area in the rejection style after input
+	// was rejected, and the rejection style.
+	rejectionFlash bool
+	rejectionStyle tcell.Style
+
+	// Set when input was rejected and cleared with the next input.
+	rejected bool
+
+	// Set when input was rejected and cleared when the beep was emitted.
+	beepPending bool
+
 	// An optional function which is called when the input has changed.
 	changed func(text string)
 
+	// An optional function which checks the text when the input field is
+	// validated, e.g. by a form. It returns a non-nil error if the text is not
+	// valid.
+	validate func(text string) error
+
+	// The input type preset and its acceptance and validation functions which
+	// are used if no custom functions were provided.
+	inputType         InputType
+	inputTypeAccept   func(text string, ch rune) bool
+	inputTypeValidate func(text string) error
+
+	// The error returned by the last validation when the input field lost
+	// focus.
+	blurError error
+
 	// An optional function which is called when the user indicated that they
 	// are done entering text. The key which was pressed is provided (tab,
 	// shift-tab, enter, or escape).
@@ -133,6 +307,7 @@ func NewInputField() *InputField {
 		labelStyle:       tcell.StyleDefault.Foreground(Styles.SecondaryTextColor),
 		fieldStyle:       tcell.StyleDefault.Background(Styles.ContrastBackgroundColor).Foreground(Styles.PrimaryTextColor),
 		placeholderStyle: tcell.StyleDefault.Background(Styles.ContrastBackgroundColor).Foreground(Styles.ContrastSecondaryTextColor),
+		maxUndoSteps:     100,
 	}
 	i.autocompleteStyles.main = tcell.StyleDefault.Foreground(Styles.PrimitiveBackgroundColor)
 	i.autocompleteStyles.selected = tcell.StyleDefault.Background(Styles.PrimaryTextColor).Foreground(Styles.PrimitiveBackgroundColor)
@@ -140,8 +315,19 @@ func NewInputField() *InputField {
 	return i
 }
 
-// SetText sets the current text of the input field.
+// SetText sets the current text of the input field. This is a separate undo
+// step, i.e. the previous text can be restored with [InputField.Undo].
 func (i *InputField) SetText(text string) *InputField {
+	if text != i.text {
+		i.pushUndo(inputFieldUndoItem{text: i.text, cursorPos: i.cursorPos}, false)
+	}
+	i.undoTyping = false
+	return i.setText(text)
+}
+
+// setText sets the current text of the input field without recording an undo
+// step.
+func (i *InputField) setText(text string) *InputField {
 	i.text = text
 	i.cursorPos = len(text)
 	if i.changed != nil {
@@ -155,6 +341,98 @@ func (i *InputField) GetText() string {
 	return i.text
 }
 
+// Undo reverts the last change to the text, if any. Consecutively typed
+// characters are reverted together. This is the same as pressing Ctrl-Z. The
+// changed handler is called if the text changed.
+func (i *InputField) Undo() *InputField {
+	if i.undo() && i.changed != nil {
+		i.changed(i.text)
+	}
+	return i
+}
+
+// Redo reapplies the last change reverted with [InputField.Undo], if any. This
+// is the same as pressing Ctrl-Y. The changed handler is called if the text
+// changed.
+func (i *InputField) Redo() *InputField {
+	if i.redo() && i.changed != nil {
+		i.changed(i.text)
+	}
+	return i
+}
+
+// CanUndo returns whether there is a change which can be reverted with
+// [InputField.Undo].
+func (i *InputField) CanUndo() bool {
+	return len(i.undoStack) > 0
+}
+
+// CanRedo returns whether there is a reverted change which can be reapplied
+// with [InputField.Redo].
+func (i *InputField) CanRedo() bool {
+	return len(i.redoStack) > 0
+}
+
+// SetMaxUndoSteps sets the maximum number of undo steps which are kept. When
+// the limit is exceeded, the oldest steps are dropped. A value of 0 means that
+// there is no limit. The default is 100.
+func (i *InputField) SetMaxUndoSteps(steps int) *InputField {
+	if steps < 0 {
+		steps = 0
+	}
+	i.maxUndoSteps = steps
+	i.trimUndoStack()
+	return i
+}
+
+// trimUndoStack drops the oldest undo steps until there are no more than the
+// maximum number of undo steps.
+func (i *InputField) trimUndoStack() {
+	if i.maxUndoSteps > 0 && len(i.undoStack) > i.maxUndoSteps {
+		i.undoStack = append(i.undoStack[:0], i.undoStack[len(i.undoStack)-i.maxUndoSteps:]...)
+	}
+}
+
+// pushUndo records the given state before an edit so that the edit can be
+// undone. If the edit and the previous edit were both typed characters, they
+// are undone together. Any undone edits can no longer be redone.
+func (i *InputField) pushUndo(before inputFieldUndoItem, typing bool) {
+	i.redoStack = nil
+	if !typing || !i.undoTyping {
+		i.undoStack = append(i.undoStack, before)
+		i.trimUndoStack()
+	}
+	i.undoTyping = typing
+}
+
+// undo restores the state before the last edit and returns whether there was
+// an edit to undo.
+func (i *InputField) undo() bool {
+	if len(i.undoStack) == 0 {
+		return false
+	}
+	item := i.undoStack[len(i.undoStack)-1]
+	i.undoStack = i.undoStack[:len(i.undoStack)-1]
+	i.redoStack = append(i.redoStack, inputFieldUndoItem{text: i.text, cursorPos: i.cursorPos})
+	i.text, i.cursorPos, i.offset = item.text, item.cursorPos, 0
+	i.undoTyping = false
+	return true
+}
+
+// redo restores the state before the last undo and returns whether there was
+// an undo to revert.
+func (i *InputField) redo() bool {
+	if len(i.redoStack) == 0 {
+		return false
+	}
+	item := i.redoStack[len(i.redoStack)-1]
+	i.redoStack = i.redoStack[:len(i.redoStack)-1]
+	i.undoStack = append(i.undoStack, inputFieldUndoItem{text: i.text, cursorPos: i.cursorPos})
+	i.text, i.cursorPos, i.offset = item.text, item.cursorPos, 0
+	i.undoTyping = false
+	return true
+}
+
 // SetLabel sets the text to be displayed before the input area.
 func (i *InputField) SetLabel(label string) *InputField {
 	i.label = label
@@ -167,12 +445,25 @@ func (i *InputField) GetLabel() string {
 }
 
 // SetLabelWidth sets the screen width of the label. A value of 0 will cause the
-// primitive to use the width of the label string.
+// primitive to use the width of the label string. A width greater than 0 also
+// overrides the label width a Form would otherwise assign to this primitive.
 func (i *InputField) SetLabelWidth(width int) *InputField {
 	i.labelWidth = width
+	i.fixedLabelWidth = width > 0
 	return i
 }
 
+// GetLabelWidth returns the screen width of the label. A value of 0 means the
+// width of the label string is used.
+func (i *InputField) GetLabelWidth() int {
+	return i.labelWidth
+}
+
+// hasFixedLabelWidth implements the fixedLabelWidthItem interface.
+func (i *InputField) hasFixedLabelWidth() bool {
+	return i.fixedLabelWidth
+}
+
 // SetPlaceholder sets the text to be displayed when the input text is empty.
 func (i *InputField) SetPlaceholder(text string) *InputField {
 	i.placeholder = text
@@ -182,12 +473,14 @@ func (i *InputField) SetPlaceholder(text string) *InputField {
 // SetLabelColor sets the text color of the label.
 func (i *InputField) SetLabelColor(color tcell.Color) *InputField {
 	i.labelStyle = i.labelStyle.Foreground(color)
+	i.overrides.set(&i.labelStyle)
 	return i
 }
 
 // SetLabelStyle sets the style of the label.
 func (i *InputField) SetLabelStyle(style tcell.Style) *InputField {
 	i.labelStyle = style
+	i.overrides.set(&i.labelStyle)
 	return i
 }
 
@@ -199,12 +492,14 @@ func (i *InputField) GetLabelStyle() tcell.Style {
 // SetFieldBackgroundColor sets the background color of the input area.
 func (i *InputField) SetFieldBackgroundColor(color tcell.Color) *InputField {
 	i.fieldStyle = i.fieldStyle.Background(color)
+	i.overrides.set(&i.fieldStyle)
 	return i
 }
 
 // SetFieldTextColor sets the text color of the input area.
 func (i *InputField) SetFieldTextColor(color tcell.Color) *InputField {
 	i.fieldStyle = i.fieldStyle.Foreground(color)
+	i.overrides.set(&i.fieldStyle)
 	return i
 }
 
@@ -212,6 +507,7 @@ func (i *InputField) SetFieldTextColor(color tcell.Color) *InputField {
 // shown).
 func (i *InputField) SetFieldStyle(style tcell.Style) *InputField {
 	i.fieldStyle = style
+	i.overrides.set(&i.fieldStyle)
 	return i
 }
 
@@ -224,6 +520,7 @@ func (i *InputField) GetFieldStyle() tcell.Style {
 // SetPlaceholderTextColor sets the text color of placeholder text.
 func (i *InputField) SetPlaceholderTextColor(color tcell.Color) *InputField {
 	i.placeholderStyle = i.placeholderStyle.Foreground(color)
+	i.overrides.set(&i.placeholderStyle)
 	return i
 }
 
@@ -231,6 +528,7 @@ func (i *InputField) SetPlaceholderTextColor(color tcell.Color) *InputField {
 // shown).
 func (i *InputField) SetPlaceholderStyle(style tcell.Style) *InputField {
 	i.placeholderStyle = style
+	i.overrides.set(&i.placeholderStyle)
 	return i
 }
 
@@ -247,16 +545,18 @@ func (i *InputField) SetAutocompleteStyles(background tcell.Color, main, selecte
 	i.autocompleteStyles.background = background
 	i.autocompleteStyles.main = main
 	i.autocompleteStyles.selected = selected
+	i.overrides.set(&i.autocompleteStyles.background, &i.autocompleteStyles.main, &i.autocompleteStyles.selected)
 	return i
 }
 
 // SetFormAttributes sets attributes shared by all form items.
 func (i *InputField) SetFormAttributes(labelWidth int, labelColor, bgColor, fieldTextColor, fieldBgColor tcell.Color) FormItem {
-	i.labelWidth = labelWidth
+	if !i.fixedLabelWidth {
+		i.labelWidth = labelWidth
+	}
 	i.backgroundColor = bgColor
-	i.SetLabelColor(labelColor).
-		SetFieldTextColor(fieldTextColor).
-		SetFieldBackgroundColor(fieldBgColor)
+	i.labelStyle = i.labelStyle.Foreground(labelColor)
+	i.fieldStyle = i.fieldStyle.Foreground(fieldTextColor).Background(fieldBgColor)
 	return i
 }
 
@@ -277,13 +577,64 @@ func (i *InputField) GetFieldHeight() int {
 	return 1
 }
 
-// SetMaskCharacter sets a character that masks user input on a screen. A value
-// of 0 disables masking.
+// SetMaskCharacter sets a character that masks user input on a screen, e.g. for
+// password entry. Each character of the text is shown as the mask character
+// while GetText() still returns the actual text. The mask character may be a
+// wide character. Masked text is never copied to the clipboard. A
+// value of 0 disables masking.
 func (i *InputField) SetMaskCharacter(mask rune) *InputField {
 	i.maskCharacter = mask
 	return i
 }
 
+// displayText returns the text as it is shown on screen. If a mask character
+// is set, each character (grapheme cluster) of the text is replaced with it.
+func (i *InputField) displayText() string {
+	if i.maskCharacter == 0 {
+		return i.text
+	}
+	var count int
+	biterateString(i.text, func(main rune, comb []rune, textPos, textWidth, screenPos, screenWidth, boundaries int) bool {
+		count++
+		return false
+	})
+	return strings.Repeat(string(i.maskCharacter), count)
+}
+
+// toDisplayPos converts a byte position in the text to the corresponding byte
+// position in the text returned by displayText(). The position must be at the
+// start of a grapheme cluster or at the end of the text.
+func (i *InputField) toDisplayPos(pos int) int {
+	if i.maskCharacter == 0 {
+		return pos
+	}
+	var count int
+	biterateString(i.text[:pos], func(main rune, comb []rune, textPos, textWidth, screenPos, screenWidth, boundaries int) bool {
+		count++
+		return false
+	})
+	return count * utf8.RuneLen(i.maskCharacter)
+}
+
+// fromDisplayPos converts a byte position in the text returned by
+// displayText() to the corresponding byte position in the text.
+func (i *InputField) fromDisplayPos(pos int) int {
+	if i.maskCharacter == 0 {
+		return pos
+	}
+	count := pos / utf8.RuneLen(i.maskCharacter)
+	result := len(i.text)
+	biterateString(i.text, func(main rune, comb []rune, textPos, textWidth, screenPos, screenWidth, boundaries int) bool {
+		if count == 0 {
+			result = textPos
+			return true
+		}
+		count--
+		return false
+	})
+	return result
+}
+
 // SetAutocompleteFunc sets an autocomplete callback function which may return
 // strings to be selected from a drop-down based on the current text of the
 // input field. The drop-down appears only if len(entries) > 0. The callback is
@@ -291,14 +642,58 @@ func (i *InputField) SetMaskCharacter(mask rune) *InputField {
 // Autocomplete() is called. Entries are cleared when the user selects an entry
 // or presses Escape.
 func (i *InputField) SetAutocompleteFunc(callback func(currentText string) (entries []string)) *InputField {
+	i.autocompleteListMutex.Lock()
 	i.autocomplete = callback
+	i.autocompleteAsync = nil
+	i.autocompleteListMutex.Unlock()
 	i.Autocomplete()
 	return i
 }
+
+// SetAutocompleteFuncAsync sets an autocomplete callback function for entries
+// which take a while to be determined, e.g. when they are retrieved over the
+// network. The callback is invoked like the one provided to
+// [InputField.SetAutocompleteFunc] but instead of the entries, it returns a
+// channel over which it sends them later. It may send more than one slice of
+// entries (each replacing the previous one) and should close the channel when
+// it is done. Entries received after the text of the input field has changed
+// are discarded. The drop-down appears only if len(entries) > 0. The callback
+// may call the input field's functions but it should return the channel
+// without waiting for the entries as it blocks the event loop until then.
+//
+// When entries arrive, the drop-down is updated in the event loop of the
+// application set with [InputField.SetAutocompleteApplication] and the screen
+// is redrawn. Without an application, the drop-down is updated immediately but
+// the input field is not redrawn until the next time the screen is drawn.
+//
+// This replaces any callback set with [InputField.SetAutocompleteFunc].
+func (i *InputField) SetAutocompleteFuncAsync(callback func(currentText string) <-chan []string) *InputField {
+	i.autocompleteListMutex.Lock()
+	i.autocomplete = nil
+	i.autocompleteAsync = callback
+	i.autocompleteListMutex.Unlock()
+	i.Autocomplete()
+	return i
+}
+
+// SetAutocompleteApplication sets the application whose QueueUpdateDraw() is
+// used to show asynchronous autocomplete entries, see
+// [InputField.SetAutocompleteFuncAsync]. Provide nil to update the drop-down
+// without redrawing the screen.
+func (i *InputField) SetAutocompleteApplication(app *Application) *InputField {
+	i.autocompleteListMutex.Lock()
+	defer i.autocompleteListMutex.Unlock()
+	i.autocompleteApp = app
+	return i
+}
+
 // AutocompleteList returns list view
 func (i *InputField) AutocompleteList() *List {
-  return i.autocompleteList
+	i.autocompleteListMutex.Lock()
+	defer i.autocompleteListMutex.Unlock()
+	return i.autocompleteList
 }
+
 // SetAutocompletedFunc sets a callback function which is invoked when the user
 // selects an entry from the autocomplete drop-down list. The function is passed
 // the text of the selected entry (stripped of any color tags), the index of the
@@ -322,22 +717,69 @@ func (i *InputField) SetAutocompletedFunc(autocompleted func(text string, index
 // input field will present the user with a corresponding drop-down list the
 // next time the input field is drawn.
 //
+// If an asynchronous callback was set with
+// [InputField.SetAutocompleteFuncAsync], it is invoked and the drop-down is
+// updated when its entries arrive.
+//
 // It is safe to call this function from any goroutine. Note that the input
 // field is not redrawn automatically unless called from the main goroutine
 // (e.g. in response to events).
 func (i *InputField) Autocomplete() *InputField {
 	i.autocompleteListMutex.Lock()
+	if i.autocompleteAsync != nil {
+		// The callback is invoked without holding the lock so that it may
+		// call back into the input field.
+		i.autocompleteRequest++
+		request, app, callback, text := i.autocompleteRequest, i.autocompleteApp, i.autocompleteAsync, i.text
+		i.autocompleteListMutex.Unlock()
+		go i.receiveAutocomplete(request, app, callback(text))
+		return i
+	}
 	defer i.autocompleteListMutex.Unlock()
 	if i.autocomplete == nil {
 		return i
 	}
+	i.setAutocompleteEntries(i.autocomplete(i.text))
+	return i
+}
 
+// receiveAutocomplete reads asynchronous autocomplete entries from the given
+// channel until it is closed and shows them in the drop-down as long as the
+// request with the given number is the most recent one. The application is
+// used to update the drop-down in the event loop. If it is nil, the drop-down
+// is updated directly.
+func (i *InputField) receiveAutocomplete(request int, app *Application, entries <-chan []string) {
+	if entries == nil {
+		return
+	}
+	for list := range entries {
+		list := list
+		update := func() {
+			i.autocompleteListMutex.Lock()
+			defer i.autocompleteListMutex.Unlock()
+			if request != i.autocompleteRequest {
+				return // These entries are stale.
+			}
+			i.setAutocompleteEntries(list)
+		}
+		if app != nil {
+			app.QueueUpdateDraw(update)
+		} else {
+			update()
+		}
+	}
+}
+
+// setAutocompleteEntries fills the autocomplete drop-down with the given
+// entries, creating it if necessary, and selects the best match for the
+// current text. If there are no entries, the drop-down is removed. The
+// autocomplete list mutex must be locked when calling this function.
+func (i *InputField) setAutocompleteEntries(entries []string) {
 	// Do we have any autocomplete entries?
-	entries := i.autocomplete(i.text)
 	if len(entries) == 0 {
 		// No entries, no list.
 		i.autocompleteList = nil
-		return i
+		return
 	}
 
 	// Make a list if we have none.
@@ -366,12 +808,11 @@ func (i *InputField) Autocomplete() *InputField {
 	if currentEntry >= 0 {
 		i.autocompleteList.SetCurrentItem(currentEntry)
 	}
-
-	return i
 }
 
 // SetAcceptanceFunc sets a handler which may reject the last character that was
-// entered (by returning false).
+// entered (by returning false). It takes precedence over the acceptance rules
+// of the input type set with [InputField.SetInputType].
 //
 // This package defines a number of variables prefixed with InputField which may
 // be used for common input (e.g. numbers, maximum text length).
@@ -380,6 +821,41 @@ func (i *InputField) SetAcceptanceFunc(handler func(textToCheck string, lastChar
 	return i
 }
 
+// SetAcceptancePattern sets an acceptance function (see
+// [InputField.SetAcceptanceFunc]) which accepts text only if the given regular
+// expression matches the entire text. As the text is checked after every
+// keystroke, the pattern must also match all intermediate states, e.g.
+// `\d{0,3}(-\d{0,4})?` instead of `\d{3}-\d{4}`. Use
+// [InputField.SetValidationFunc] to check the final text. A nil pattern
+// removes the acceptance function.
+func (i *InputField) SetAcceptancePattern(pattern *regexp.Regexp) *InputField {
+	if pattern == nil {
+		i.accept = nil
+		return i
+	}
+	i.accept = func(text string, ch rune) bool {
+		match := pattern.FindStringIndex(text)
+		return match != nil && match[0] == 0 && match[1] == len(text)
+	}
+	return i
+}
+
+// SetRejectionBeep sets whether the terminal beeps when typed or pasted text
+// is rejected by the acceptance function.
+func (i *InputField) SetRejectionBeep(beep bool) *InputField {
+	i.rejectionBeep = beep
+	return i
+}
+
+// SetRejectionStyle sets a style in which the input area is drawn after typed
+// or pasted text was rejected by the acceptance function, until the next key
+// is handled. This makes the input area flash briefly while the user types.
+func (i *InputField) SetRejectionStyle(style tcell.Style) *InputField {
+	i.rejectionStyle = style
+	i.rejectionFlash = true
+	return i
+}
+
 // SetChangedFunc sets a handler which is called whenever the text of the input
 // field has changed. It receives the current text (after the change).
 func (i *InputField) SetChangedFunc(handler func(text string)) *InputField {
@@ -387,6 +863,118 @@ func (i *InputField) SetChangedFunc(handler func(text string)) *InputField {
 	return i
 }
 
+// SetValidationFunc sets a handler which checks the text of the input field
+// when it is validated. The handler returns a non-nil error if the text is not
+// valid. Forms validate their items when the user leaves them and display the
+// error message below the field. See also [Form.Validate].
+//
+// The handler takes precedence over the validation of the input type set with
+// [InputField.SetInputType].
+func (i *InputField) SetValidationFunc(handler func(text string) error) *InputField {
+	i.validate = handler
+	return i
+}
+
+// Validate checks the current text using the handler provided with
+// [InputField.SetValidationFunc] or, if there is none, the validation of the
+// input type set with [InputField.SetInputType], and returns its result. If
+// neither exists, nil is returned.
+func (i *InputField) Validate() error {
+	validate := i.validate
+	if validate == nil {
+		validate = i.inputTypeValidate
+	}
+	if validate == nil {
+		return nil
+	}
+	return validate(i.GetText())
+}
+
+// SetInputType sets a preset for the kind of text the input field accepts.
+// Characters which cannot lead to valid text of this type are rejected while
+// typing, and the complete text is validated when the input field loses
+// focus (see [InputField.GetValidationError]) or when it is validated by a
+// form. Empty text is always considered valid. The default is InputTypeText
+// which accepts any text.
+//
+// Functions provided with [InputField.SetAcceptanceFunc] and
+// [InputField.SetValidationFunc] override the preset's rules.
+func (i *InputField) SetInputType(inputType InputType) *InputField {
+	i.inputType = inputType
+	i.inputTypeAccept, i.inputTypeValidate = inputTypeFuncs(inputType)
+	return i
+}
+
+// GetInputType returns the input type set with [InputField.SetInputType].
+func (i *InputField) GetInputType() InputType {
+	return i.inputType
+}
+
+// GetValidationError returns the error of the validation performed when the
+// input field last lost focus, or nil if the text was valid at that time.
+func (i *InputField) GetValidationError() error {
+	return i.blurError
+}
+
+// SetWordBoundaryFunc sets a function which returns whether the given
+// character separates words, e.g. to treat punctuation as word separators or
+// to support scripts which don't separate words with spaces. It is used when
+// moving the cursor by words and when deleting words. A nil function restores
+// the default which separates words by whitespace.
+func (i *InputField) SetWordBoundaryFunc(isBoundary func(r rune) bool) *InputField {
+	i.wordBoundary = isBoundary
+	return i
+}
+
+// isWordBoundary returns whether the given character separates words.
+func (i *InputField) isWordBoundary(r rune) bool {
+	if i.wordBoundary != nil {
+		return i.wordBoundary(r)
+	}
+	return unicode.IsSpace(r)
+}
+
+// wordStart returns the byte index of the beginning of the word before the
+// given byte index, skipping word separators directly before it.
+func (i *InputField) wordStart(pos int) int {
+	inWord := false
+	for pos > 0 {
+		r, size := utf8.DecodeLastRuneInString(i.text[:pos])
+		if i.isWordBoundary(r) {
+			if inWord {
+				break
+			}
+		} else {
+			inWord = true
+		}
+		pos -= size
+	}
+	return pos
+}
+
+// wordEnd returns the byte index of the end of the word after the given byte
+// index, skipping word separators directly after it. If trailing is true, the
+// word separators following the word are skipped, too.
+func (i *InputField) wordEnd(pos int, trailing bool) int {
+	inWord := false
+	for pos < len(i.text) {
+		r, size := utf8.DecodeRuneInString(i.text[pos:])
+		if i.isWordBoundary(r) {
+			if inWord && !trailing {
+				break
+			}
+		} else if inWord && pos > 0 {
+			if previous, _ := utf8.DecodeLastRuneInString(i.text[:pos]); i.isWordBoundary(previous) {
+				break // The next word begins.
+			}
+		} else {
+			inWord = true
+		}
+		pos += size
+	}
+	return pos
+}
+
 // SetDoneFunc sets a handler which is called when the user is done entering
 // text. The callback function is provided with the key that was pressed, which
 // is one of the following:
@@ -409,9 +997,21 @@ func (i *InputField) SetFinishedFunc(handler func(key tcell.Key)) FormItem {
 // Blur is called when this primitive loses focus.
 func (i *InputField) Blur() {
 	i.Box.Blur()
+	i.blurError = i.Validate()
 	i.autocompleteList = nil // Hide the autocomplete drop-down.
 }
 
+// applyTheme implements the themeable interface.
+func (i *InputField) applyTheme(from, to *Theme) {
+	i.Box.applyTheme(from, to)
+	i.overrides.style(&i.labelStyle, tcell.StyleDefault.Foreground(to.SecondaryTextColor))
+	i.overrides.style(&i.fieldStyle, tcell.StyleDefault.Background(to.ContrastBackgroundColor).Foreground(to.PrimaryTextColor))
+	i.overrides.style(&i.placeholderStyle, tcell.StyleDefault.Background(to.ContrastBackgroundColor).Foreground(to.ContrastSecondaryTextColor))
+	i.overrides.style(&i.autocompleteStyles.main, tcell.StyleDefault.Foreground(to.PrimitiveBackgroundColor))
+	i.overrides.style(&i.autocompleteStyles.selected, tcell.StyleDefault.Background(to.PrimaryTextColor).Foreground(to.PrimitiveBackgroundColor))
+	i.overrides.color(&i.autocompleteStyles.background, to.MoreContrastBackgroundColor)
+}
+
 // Draw draws this primitive onto the screen.
 func (i *InputField) Draw(screen tcell.Screen) {
 	i.Box.DrawForSubclass(screen, i)
@@ -441,7 +1041,11 @@ func (i *InputField) Draw(screen tcell.Screen) {
 	i.fieldX = x
 	fieldWidth := i.fieldWidth
 	text := i.text
-	inputStyle := i.fieldStyle
+	fieldStyle := i.fieldStyle
+	if i.rejected && i.rejectionFlash {
+		fieldStyle = i.rejectionStyle
+	}
+	inputStyle := fieldStyle
 	placeholder := text == "" && i.placeholder != ""
 	if placeholder {
 		inputStyle = i.placeholderStyle
@@ -466,43 +1070,44 @@ func (i *InputField) Draw(screen tcell.Screen) {
 		printWithStyle(screen, Escape(i.placeholder), x, y, 0, fieldWidth, AlignLeft, i.placeholderStyle, true)
 		i.offset = 0
 	} else {
-		// Draw entered text.
-		if i.maskCharacter > 0 {
-			text = strings.Repeat(string(i.maskCharacter), utf8.RuneCountInString(i.text))
+		// Draw entered text. Positions in the masked text differ from those in
+		// the actual text so we convert them here.
+		if i.cursorPos < 0 {
+			i.cursorPos = 0
+		} else if i.cursorPos > len(i.text) {
+			i.cursorPos = len(i.text)
+		}
+		if i.offset > i.cursorPos {
+			i.offset = i.cursorPos
 		}
+		text = i.displayText()
+		cursorPos, offset := i.toDisplayPos(i.cursorPos), i.toDisplayPos(i.offset)
 		if fieldWidth >= stringWidth(text) {
 			// We have enough space for the full text.
-			printWithStyle(screen, Escape(text), x, y, 0, fieldWidth, AlignLeft, i.fieldStyle, true)
-			i.offset = 0
+			printWithStyle(screen, Escape(text), x, y, 0, fieldWidth, AlignLeft, fieldStyle, true)
+			offset = 0
 			biterateString(text, func(main rune, comb []rune, textPos, textWidth, screenPos, screenWidth, boundaries int) bool {
-				if textPos >= i.cursorPos {
+				if textPos >= cursorPos {
 					return true
 				}
 				cursorScreenPos += screenWidth
 				return false
 			})
 		} else {
-			// The text doesn't fit. Where is the cursor?
-			if i.cursorPos < 0 {
-				i.cursorPos = 0
-			} else if i.cursorPos > len(text) {
-				i.cursorPos = len(text)
-			}
-			// Shift the text so the cursor is inside the field.
+			// The text doesn't fit. Shift the text so the cursor is inside the
+			// field.
 			var shiftLeft int
-			if i.offset > i.cursorPos {
-				i.offset = i.cursorPos
-			} else if subWidth := uniseg.StringWidth(text[i.offset:i.cursorPos]); subWidth > fieldWidth-1 {
+			if subWidth := uniseg.StringWidth(text[offset:cursorPos]); subWidth > fieldWidth-1 {
 				shiftLeft = subWidth - fieldWidth + 1
 			}
-			currentOffset := i.offset
+			currentOffset := offset
 			biterateString(text, func(main rune, comb []rune, textPos, textWidth, screenPos, screenWidth, boundaries int) bool {
 				if textPos >= currentOffset {
 					if shiftLeft > 0 {
-						i.offset = textPos + textWidth
+						offset = textPos + textWidth
 						shiftLeft -= screenWidth
 					} else {
-						if textPos+textWidth > i.cursorPos {
+						if textPos+textWidth > cursorPos {
 							return true
 						}
 						cursorScreenPos += screenWidth
@@ -510,8 +1115,9 @@ func (i *InputField) Draw(screen tcell.Screen) {
 				}
 				return false
 			})
-			printWithStyle(screen, Escape(text[i.offset:]), x, y, 0, fieldWidth, AlignLeft, i.fieldStyle, true)
+			printWithStyle(screen, Escape(text[offset:]), x, y, 0, fieldWidth, AlignLeft, fieldStyle, true)
 		}
+		i.offset = i.fromDisplayPos(offset)
 	}
 
 	// Draw autocomplete list.
@@ -533,7 +1139,7 @@ func (i *InputField) Draw(screen tcell.Screen) {
 		lx := x
 		ly := y + 1
 		_, sheight := screen.Size()
-		if ly+lheight >= sheight && ly-2 > lheight-ly {
+		if ly+lheight >= sheight && ly-2 > sheight-ly {
 			ly = y - lheight
 			if ly < 0 {
 				ly = 0
@@ -550,14 +1156,79 @@ func (i *InputField) Draw(screen tcell.Screen) {
 	if i.HasFocus() {
 		screen.ShowCursor(x+cursorScreenPos, y)
 	}
+
+	// Signal rejected input.
+	if i.beepPending {
+		i.beepPending = false
+		screen.Beep()
+	}
+}
+
+// insert inserts the given text at the cursor position and moves the cursor
+// behind it. Line breaks are replaced with spaces. It returns whether the text
+// was accepted by the acceptance function. This is used for typing as well as
+// for pasting, both from the clipboard and via bracketed paste. Pasted text is
+// checked as a whole, i.e. it is either inserted completely or not at all.
+func (i *InputField) insert(text string) bool {
+	i.rejected = false
+	text = strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ").Replace(text)
+	if text == "" {
+		return true
+	}
+	newText := i.text[:i.cursorPos] + text + i.text[i.cursorPos:]
+	accept := i.accept
+	if accept == nil {
+		accept = i.inputTypeAccept
+	}
+	if accept != nil {
+		r, _ := utf8.DecodeLastRuneInString(text)
+		if !accept(newText, r) {
+			i.rejected = true
+			i.beepPending = i.rejectionBeep
+			return false
+		}
+	}
+	i.text = newText
+	i.cursorPos += len(text)
+	return true
+}
+
+// OnPaste is called when a bracketed paste is finished. The pasted text is
+// inserted at the cursor position. If a handler was installed with
+// [Box.SetOnPaste], that handler is called instead.
+func (i *InputField) OnPaste(runes []rune) {
+	if i.onPaste != nil {
+		i.onPaste(runes)
+		return
+	}
+	before := inputFieldUndoItem{text: i.text, cursorPos: i.cursorPos}
+	if i.insert(string(runes)) && len(runes) > 0 {
+		if i.text != before.text {
+			i.pushUndo(before, false)
+		}
+		i.Autocomplete()
+		if i.changed != nil {
+			i.changed(i.text)
+		}
+	}
 }
 
 // InputHandler returns the handler for this primitive.
 func (i *InputField) InputHandler() func(event *tcell.EventKey, setFocus func(p Primitive)) {
 	return i.WrapInputHandler(func(event *tcell.EventKey, setFocus func(p Primitive)) {
-		// Trigger changed events.
+		// Trigger changed events and record undo steps.
 		currentText := i.text
+		before := inputFieldUndoItem{text: i.text, cursorPos: i.cursorPos}
+		var typing, undoing bool
+		i.rejected = false
 		defer func() {
+			if !undoing {
+				if i.text != currentText {
+					i.pushUndo(before, typing)
+				} else {
+					i.undoTyping = false
+				}
+			}
 			if i.text != currentText {
 				i.Autocomplete()
 				if i.changed != nil {
@@ -582,22 +1253,17 @@ func (i *InputField) InputHandler() func(event *tcell.EventKey, setFocus func(p
 			})
 		}
 		moveWordLeft := func() {
-			i.cursorPos = len(regexp.MustCompile(`\S+\s*$`).ReplaceAllString(i.text[:i.cursorPos], ""))
+			i.cursorPos = i.wordStart(i.cursorPos)
 		}
 		moveWordRight := func() {
-			i.cursorPos = len(i.text) - len(regexp.MustCompile(`^\s*\S+\s*`).ReplaceAllString(i.text[i.cursorPos:], ""))
+			i.cursorPos = i.wordEnd(i.cursorPos, true)
 		}
 
 		// Add character function. Returns whether or not the rune character is
 		// accepted.
 		add := func(r rune) bool {
-			newText := i.text[:i.cursorPos] + string(r) + i.text[i.cursorPos:]
-			if i.accept != nil && !i.accept(newText, r) {
-				return false
-			}
-			i.text = newText
-			i.cursorPos += len(string(r))
-			return true
+			typing = true
+			return i.insert(string(r))
 		}
 
 		// Finish up.
@@ -645,7 +1311,7 @@ func (i *InputField) InputHandler() func(event *tcell.EventKey, setFocus func(p
 							currentText = i.GetText()
 						}
 					} else {
-						i.SetText(text)
+						i.setText(text)
 						currentText = stripTags(text) // We want to keep the autocomplete list open and unchanged.
 					}
 				})
@@ -668,6 +1334,8 @@ func (i *InputField) InputHandler() func(event *tcell.EventKey, setFocus func(p
 					moveWordLeft()
 				case 'f': // Move word right.
 					moveWordRight()
+				case 'd': // Delete next word.
+					i.text = i.text[:i.cursorPos] + i.text[i.wordEnd(i.cursorPos, false):]
 				default:
 					if !add(event.Rune()) {
 						return
@@ -682,13 +1350,29 @@ func (i *InputField) InputHandler() func(event *tcell.EventKey, setFocus func(p
 		case tcell.KeyCtrlU: // Delete all.
 			i.text = ""
 			i.cursorPos = 0
+		case tcell.KeyCtrlQ: // Copy to clipboard.
+			if i.maskCharacter == 0 {
+				getClipboard().Set(i.text)
+			}
+		case tcell.KeyCtrlX: // Cut to clipboard.
+			if i.maskCharacter == 0 {
+				getClipboard().Set(i.text)
+			}
+			i.text = ""
+			i.cursorPos = 0
+			i.offset = 0
+		case tcell.KeyCtrlV: // Paste from clipboard.
+			i.insert(getClipboard().Get())
+		case tcell.KeyCtrlZ: // Undo.
+			undoing = i.undo()
+		case tcell.KeyCtrlY: // Redo.
+			undoing = i.redo()
 		case tcell.KeyCtrlK: // Delete until the end of the line.
 			i.text = i.text[:i.cursorPos]
 		case tcell.KeyCtrlW: // Delete last word.
-			lastWord := regexp.MustCompile(`\S+\s*$`)
-			newText := lastWord.ReplaceAllString(i.text[:i.cursorPos], "") + i.text[i.cursorPos:]
-			i.cursorPos -= len(i.text) - len(newText)
-			i.text = newText
+			start := i.wordStart(i.cursorPos)
+			i.text = i.text[:start] + i.text[i.cursorPos:]
+			i.cursorPos = start
 		case tcell.KeyBackspace, tcell.KeyBackspace2: // Delete character before the cursor.
 			iterateStringReverse(i.text[:i.cursorPos], func(main rune, comb []rune, textPos, textWidth, screenPos, screenWidth int) bool {
 				i.text = i.text[:textPos] + i.text[textPos+textWidth:]
@@ -704,7 +1388,7 @@ func (i *InputField) InputHandler() func(event *tcell.EventKey, setFocus func(p
 				return true
 			})
 		case tcell.KeyLeft:
-			if event.Modifiers()&tcell.ModAlt > 0 {
+			if event.Modifiers()&(tcell.ModAlt|tcell.ModCtrl) > 0 {
 				moveWordLeft()
 			} else {
 				moveLeft()
@@ -712,7 +1396,7 @@ func (i *InputField) InputHandler() func(event *tcell.EventKey, setFocus func(p
 		case tcell.KeyCtrlB:
 			moveLeft()
 		case tcell.KeyRight:
-			if event.Modifiers()&tcell.ModAlt > 0 {
+			if event.Modifiers()&(tcell.ModAlt|tcell.ModCtrl) > 0 {
 				moveWordRight()
 			} else {
 				moveRight()
@@ -737,8 +1421,10 @@ func (i *InputField) InputHandler() func(event *tcell.EventKey, setFocus func(p
 func (i *InputField) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
 	return i.WrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
 		currentText := i.GetText()
+		before := inputFieldUndoItem{text: i.text, cursorPos: i.cursorPos}
 		defer func() {
 			if i.GetText() != currentText {
+				i.pushUndo(before, false)
 				i.Autocomplete()
 				if i.changed != nil {
 					i.changed(i.text)
@@ -759,7 +1445,7 @@ func (i *InputField) MouseHandler() func(action MouseAction, event *tcell.EventM
 					}
 					return
 				}
-				i.SetText(text)
+				i.setText(text)
 				i.autocompleteList = nil
 			})
 			if consumed, _ = i.autocompleteList.MouseHandler()(action, event, setFocus); consumed {
@@ -782,10 +1468,12 @@ func (i *InputField) MouseHandler() func(action MouseAction, event *tcell.EventM
 				consumed = true
 			} else if action == MouseLeftClick {
 				// Determine where to place the cursor.
+				i.undoTyping = false
 				if x >= i.fieldX {
-					if !biterateString(i.text[i.offset:], func(main rune, comb []rune, textPos int, textWidth int, screenPos int, screenWidth, boundaries int) bool {
+					offset := i.toDisplayPos(i.offset)
+					if !biterateString(i.displayText()[offset:], func(main rune, comb []rune, textPos int, textWidth int, screenPos int, screenWidth, boundaries int) bool {
 						if x-i.fieldX < screenPos+screenWidth {
-							i.cursorPos = textPos + i.offset
+							i.cursorPos = i.fromDisplayPos(textPos + offset)
 							return true
 						}
 						return false
@@ -0,0 +1,135 @@
+package tview
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// typeText sends the given text to the input field, one key event per rune.
+func typeText(field *InputField, text string) {
+	handler := field.InputHandler()
+	for _, ch := range text {
+		handler(tcell.NewEventKey(tcell.KeyRune, ch, tcell.ModNone), func(p Primitive) {})
+	}
+}
+
+func TestInputFieldInputTypeInteger(t *testing.T) {
+	field := NewInputField().SetInputType(InputTypeInteger)
+	typeText(field, "-4a2x")
+	if text := field.GetText(); text != "-42" {
+		t.Errorf("text is %q, expected letters to be rejected", text)
+	}
+	field.Blur()
+	if err := field.GetValidationError(); err != nil {
+		t.Errorf("integer was flagged as invalid: %v", err)
+	}
+}
+
+func TestInputFieldInputTypeEmail(t *testing.T) {
+	field := NewInputField().SetInputType(InputTypeEmail)
+
+	// Invalid addresses are flagged when the field loses focus.
+	for _, text := range []string{"me", "me@example", "@example.com", "me@@example.com"} {
+		field.SetText(text)
+		field.Blur()
+		if field.GetValidationError() == nil {
+			t.Errorf("%q was not flagged as invalid", text)
+		}
+	}
+
+	// Valid and empty addresses are not.
+	for _, text := range []string{"me@example.com", ""} {
+		field.SetText(text)
+		field.Blur()
+		if err := field.GetValidationError(); err != nil {
+			t.Errorf("%q was flagged as invalid: %v", text, err)
+		}
+	}
+
+	// Spaces are rejected while typing.
+	field.SetText("")
+	typeText(field, "me @x.org")
+	if text := field.GetText(); text != "me@x.org" {
+		t.Errorf("text is %q, expected the space to be rejected", text)
+	}
+}
+
+func TestInputFieldSetTextUndo(t *testing.T) {
+	var changes []string
+	field := NewInputField().SetChangedFunc(func(text string) {
+		changes = append(changes, text)
+	})
+	typeText(field, "ab")
+	field.SetText("reset")
+	typeText(field, "cd")
+
+	// The typed characters are not grouped with those typed before SetText().
+	for _, expected := range []string{"reset", "ab", ""} {
+		field.Undo()
+		if text := field.GetText(); text != expected {
+			t.Errorf("text is %q after undoing, expected %q", text, expected)
+		}
+	}
+	for _, expected := range []string{"ab", "reset", "resetcd"} {
+		field.Redo()
+		if text := field.GetText(); text != expected {
+			t.Errorf("text is %q after redoing, expected %q", text, expected)
+		}
+	}
+
+	// Setting the same text again does not add an undo step.
+	field.SetText("resetcd")
+	field.Undo()
+	if text := field.GetText(); text != "reset" {
+		t.Errorf("text is %q after undoing, expected %q", text, "reset")
+	}
+	if len(changes) != 13 {
+		t.Errorf("changed handler was called %d times, expected 13", len(changes))
+	}
+}
+
+func TestInputFieldAutocompleteAsyncCallback(t *testing.T) {
+	field := NewInputField()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		var calls int
+		field.SetAutocompleteFuncAsync(func(currentText string) <-chan []string {
+			// Calling back into the input field does not deadlock.
+			calls++
+			if calls == 1 {
+				field.GetText()
+				field.AutocompleteList()
+				field.Autocomplete()
+			}
+			entries := make(chan []string, 1)
+			entries <- []string{currentText + "1", currentText + "2"}
+			close(entries)
+			return entries
+		})
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("autocomplete callback deadlocked")
+	}
+
+	// Without an application, the entries are shown directly.
+	entries := func() int {
+		field.autocompleteListMutex.Lock()
+		defer field.autocompleteListMutex.Unlock()
+		if field.autocompleteList == nil {
+			return 0
+		}
+		return field.autocompleteList.GetItemCount()
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for entries() != 2 {
+		if time.Now().After(deadline) {
+			t.Fatal("autocomplete entries were not shown")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
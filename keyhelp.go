@@ -0,0 +1,194 @@
+package tview
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// KeyHelper is implemented by primitives which document their own keyboard
+// shortcuts. Application.ShowKeyHelp() lists these shortcuts when the
+// primitive has focus. The actions of the returned bindings are ignored.
+type KeyHelper interface {
+	KeyHelp() []KeyBinding
+}
+
+// keyHelpGroup is a group of bindings with a common heading in the key help
+// overlay.
+type keyHelpGroup struct {
+	heading  string
+	bindings []KeyBinding
+}
+
+// keyHelp is the overlay shown by Application.ShowKeyHelp().
+type keyHelp struct {
+	*Box
+
+	// The groups of bindings, in the order they are shown.
+	groups []keyHelpGroup
+}
+
+// newKeyHelp returns a new key help overlay listing the bindings of the given
+// key map which are active while the given primitive has focus, as well as the
+// focused primitive's own shortcuts (see KeyHelper). Both arguments may be nil.
+func newKeyHelp(keyMap *KeyMap, focus Primitive) *keyHelp {
+	k := &keyHelp{
+		Box: NewBox(),
+	}
+	k.SetBorder(true).
+		SetTitle(" Keyboard shortcuts ").
+		SetBackgroundColor(Styles.ContrastBackgroundColor).
+		SetBorderPadding(0, 0, 1, 1)
+
+	// Collect the active bindings by context.
+	if keyMap != nil {
+		var (
+			global   []KeyBinding
+			contexts []Primitive
+			grouped  = make(map[Primitive][]KeyBinding)
+		)
+		for _, binding := range keyMap.bindings {
+			if binding.Primitive == nil {
+				global = append(global, binding)
+				continue
+			}
+			if !binding.Primitive.HasFocus() {
+				continue
+			}
+			if _, ok := grouped[binding.Primitive]; !ok {
+				contexts = append(contexts, binding.Primitive)
+			}
+			grouped[binding.Primitive] = append(grouped[binding.Primitive], binding)
+		}
+		for _, context := range contexts {
+			k.groups = append(k.groups, keyHelpGroup{
+				heading:  primitiveName(context),
+				bindings: grouped[context],
+			})
+		}
+
+		// Global bindings may be overridden.
+		var active []KeyBinding
+		for _, binding := range global {
+			if !overridden(binding, contexts, grouped) {
+				active = append(active, binding)
+			}
+		}
+		if len(active) > 0 {
+			k.groups = append(k.groups, keyHelpGroup{
+				heading:  "Global",
+				bindings: active,
+			})
+		}
+	}
+
+	// Add the focused primitive's own shortcuts.
+	if helper, ok := focus.(KeyHelper); ok {
+		if bindings := helper.KeyHelp(); len(bindings) > 0 {
+			k.groups = append(k.groups, keyHelpGroup{
+				heading:  primitiveName(focus),
+				bindings: bindings,
+			})
+		}
+	}
+
+	return k
+}
+
+// overridden returns whether the given global binding is overridden by one of
+// the given bindings of primitives.
+func overridden(binding KeyBinding, contexts []Primitive, grouped map[Primitive][]KeyBinding) bool {
+	for _, context := range contexts {
+		binding.Primitive = context
+		for _, override := range grouped[context] {
+			if binding.sameKeys(override) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// primitiveName returns a heading for bindings of the given primitive. This is
+// its title if it has one, otherwise its type.
+func primitiveName(p Primitive) string {
+	if titled, ok := p.(interface{ GetTitle() string }); ok {
+		if title := strings.TrimSpace(titled.GetTitle()); title != "" {
+			return title
+		}
+	}
+	return strings.TrimPrefix(fmt.Sprintf("%T", p), "*tview.")
+}
+
+// lines returns the lines of text of the overlay, without color tags, and the
+// width of the key name column.
+func (k *keyHelp) lines() (lines []string, nameWidth int) {
+	for _, group := range k.groups {
+		for _, binding := range group.bindings {
+			if w := TaggedStringWidth(binding.Name()); w > nameWidth {
+				nameWidth = w
+			}
+		}
+	}
+	for index, group := range k.groups {
+		if index > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, group.heading)
+		for _, binding := range group.bindings {
+			name := binding.Name()
+			lines = append(lines, "  "+name+strings.Repeat(" ", nameWidth-TaggedStringWidth(name))+"  "+binding.Label)
+		}
+	}
+	if len(lines) == 0 {
+		lines = append(lines, "No keyboard shortcuts")
+	}
+	return
+}
+
+// Draw draws this primitive centered onto the screen.
+func (k *keyHelp) Draw(screen tcell.Screen) {
+	lines, _ := k.lines()
+
+	// Determine the size.
+	screenWidth, screenHeight := screen.Size()
+	width := TaggedStringWidth(k.GetTitle()) + 2
+	for _, line := range lines {
+		if w := TaggedStringWidth(line); w > width {
+			width = w
+		}
+	}
+	width += 4
+	height := len(lines) + 2
+	if width > screenWidth {
+		width = screenWidth
+	}
+	if height > screenHeight {
+		height = screenHeight
+	}
+	k.SetRect((screenWidth-width)/2, (screenHeight-height)/2, width, height)
+
+	// Draw the lines. Headings are highlighted.
+	k.Box.DrawForSubclass(screen, k)
+	x, y, width, height := k.GetInnerRect()
+	line := 0
+	for _, group := range k.groups {
+		if line > 0 {
+			line++
+		}
+		if line < height {
+			printWithStyle(screen, Escape(group.heading), x, y+line, 0, width, AlignLeft, tcell.StyleDefault.Foreground(Styles.SecondaryTextColor).Bold(true), true)
+		}
+		line++
+		for range group.bindings {
+			if line < height {
+				Print(screen, Escape(lines[line]), x, y+line, width, AlignLeft, Styles.PrimaryTextColor)
+			}
+			line++
+		}
+	}
+	if len(k.groups) == 0 {
+		Print(screen, lines[0], x, y, width, AlignLeft, Styles.PrimaryTextColor)
+	}
+}
@@ -0,0 +1,209 @@
+package tview
+
+import (
+	"github.com/gdamore/tcell/v2"
+)
+
+// KeyBinding binds a key combination to an action. See KeyMap.
+type KeyBinding struct {
+	// The key. For printable characters, this is tcell.KeyRune.
+	Key tcell.Key
+
+	// The character if Key is tcell.KeyRune, ignored otherwise.
+	Rune rune
+
+	// The modifier keys which must be held. For control characters such as
+	// tcell.KeyCtrlS, tcell.ModCtrl is implied and need not be set.
+	Modifiers tcell.ModMask
+
+	// A human-readable description of the action, e.g. "Save file".
+	Label string
+
+	// If not nil, the binding is only active while this primitive (or one of
+	// its descendants) has focus. Such bindings take precedence over bindings
+	// without a primitive.
+	Primitive Primitive
+
+	// The function which is called when the key combination is pressed.
+	Action func()
+}
+
+// Name returns a human-readable name of the binding's key combination, e.g.
+// "Ctrl+S", "F1", or "Alt+x".
+func (b KeyBinding) Name() string {
+	if b.Key != tcell.KeyRune {
+		return tcell.NewEventKey(b.Key, b.Rune, b.Modifiers).Name()
+	}
+	var name string
+	for _, modifier := range []struct {
+		mask tcell.ModMask
+		name string
+	}{
+		{tcell.ModCtrl, "Ctrl+"},
+		{tcell.ModAlt, "Alt+"},
+		{tcell.ModMeta, "Meta+"},
+		{tcell.ModShift, "Shift+"},
+	} {
+		if b.Modifiers&modifier.mask != 0 {
+			name += modifier.name
+		}
+	}
+	if b.Rune == ' ' {
+		return name + "Space"
+	}
+	return name + string(b.Rune)
+}
+
+// matches returns whether the given key event triggers this binding.
+func (b KeyBinding) matches(event *tcell.EventKey) bool {
+	if event.Key() != b.Key || b.Key == tcell.KeyRune && event.Rune() != b.Rune {
+		return false
+	}
+	return normalizeModifiers(b.Key, event.Modifiers()) == normalizeModifiers(b.Key, b.Modifiers)
+}
+
+// sameKeys returns whether the two bindings are triggered by the same key
+// combination in the same context.
+func (b KeyBinding) sameKeys(other KeyBinding) bool {
+	return b.Key == other.Key &&
+		(b.Key != tcell.KeyRune || b.Rune == other.Rune) &&
+		normalizeModifiers(b.Key, b.Modifiers) == normalizeModifiers(other.Key, other.Modifiers) &&
+		b.Primitive == other.Primitive
+}
+
+// normalizeModifiers removes the Ctrl modifier from control character keys
+// as terminals don't report it consistently for them.
+func normalizeModifiers(key tcell.Key, modifiers tcell.ModMask) tcell.ModMask {
+	if key <= tcell.KeyCtrlUnderscore {
+		return modifiers &^ tcell.ModCtrl
+	}
+	return modifiers
+}
+
+// KeyMap is a registry of key bindings. It is installed with
+// Application.SetKeyMap() which then invokes the bindings' actions before key
+// events are forwarded to the focused primitive. Key events which trigger a
+// binding are not forwarded.
+//
+// Bindings may be limited to a primitive (see KeyBinding.Primitive). Such
+// bindings override global bindings of the same key combination while the
+// primitive has focus. The bindings are introspectable (see Bindings()), e.g.
+// to build a help screen, and bindings of the same key combination in the same
+// context can be detected with Conflicts().
+//
+// A key map must not be modified from another goroutine while it is installed
+// in a running application. Use Application.QueueUpdate() for that.
+type KeyMap struct {
+	// The bindings in the order they were added.
+	bindings []KeyBinding
+}
+
+// NewKeyMap returns a new, empty key map.
+func NewKeyMap() *KeyMap {
+	return &KeyMap{}
+}
+
+// Bind adds a global binding for the given key and modifier keys. For
+// printable characters, use BindRune().
+func (k *KeyMap) Bind(key tcell.Key, mod tcell.ModMask, action func()) *KeyMap {
+	return k.Add(KeyBinding{
+		Key:       key,
+		Modifiers: mod,
+		Action:    action,
+	})
+}
+
+// BindRune adds a global binding for the given character and modifier keys.
+func (k *KeyMap) BindRune(ch rune, mod tcell.ModMask, action func()) *KeyMap {
+	return k.Add(KeyBinding{
+		Key:       tcell.KeyRune,
+		Rune:      ch,
+		Modifiers: mod,
+		Action:    action,
+	})
+}
+
+// Add adds the given binding. This allows to set all of the binding's
+// properties, e.g. its label or its primitive.
+func (k *KeyMap) Add(binding KeyBinding) *KeyMap {
+	k.bindings = append(k.bindings, binding)
+	return k
+}
+
+// Unbind removes all bindings of the given key, character (only used for
+// tcell.KeyRune), and modifier keys which are limited to the given primitive,
+// or all global bindings if the primitive is nil.
+func (k *KeyMap) Unbind(key tcell.Key, ch rune, mod tcell.ModMask, p Primitive) *KeyMap {
+	target := KeyBinding{Key: key, Rune: ch, Modifiers: mod, Primitive: p}
+	bindings := k.bindings[:0]
+	for _, binding := range k.bindings {
+		if !binding.sameKeys(target) {
+			bindings = append(bindings, binding)
+		}
+	}
+	k.bindings = bindings
+	return k
+}
+
+// Clear removes all bindings.
+func (k *KeyMap) Clear() *KeyMap {
+	k.bindings = nil
+	return k
+}
+
+// Bindings returns a copy of all bindings in the order they were added.
+func (k *KeyMap) Bindings() []KeyBinding {
+	return append([]KeyBinding(nil), k.bindings...)
+}
+
+// Conflicts returns groups of bindings which share the same key combination
+// and the same primitive. Only the first binding of each group is ever
+// triggered. Overrides of global bindings by primitive bindings are not
+// considered conflicts. nil is returned if there are no conflicts.
+func (k *KeyMap) Conflicts() (conflicts [][]KeyBinding) {
+	grouped := make([]bool, len(k.bindings))
+	for index, binding := range k.bindings {
+		if grouped[index] {
+			continue
+		}
+		group := []KeyBinding{binding}
+		for other := index + 1; other < len(k.bindings); other++ {
+			if !grouped[other] && binding.sameKeys(k.bindings[other]) {
+				group = append(group, k.bindings[other])
+				grouped[other] = true
+			}
+		}
+		if len(group) > 1 {
+			conflicts = append(conflicts, group)
+		}
+	}
+	return
+}
+
+// dispatch invokes the action of the binding triggered by the given key event,
+// if any, and returns whether there was one. Bindings of the focused primitive
+// itself win over bindings of primitives containing it which in turn win over
+// global bindings.
+func (k *KeyMap) dispatch(event *tcell.EventKey, focus Primitive) bool {
+	for _, active := range []func(binding KeyBinding) bool{
+		func(binding KeyBinding) bool {
+			return binding.Primitive != nil && binding.Primitive == focus
+		},
+		func(binding KeyBinding) bool {
+			return binding.Primitive != nil && binding.Primitive.HasFocus()
+		},
+		func(binding KeyBinding) bool {
+			return binding.Primitive == nil
+		},
+	} {
+		for _, binding := range k.bindings {
+			if active(binding) && binding.matches(event) {
+				if binding.Action != nil {
+					binding.Action()
+				}
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -13,10 +13,21 @@ type listItem struct {
 	SecondaryText string // A secondary text to be shown underneath the main text.
 	Shortcut      rune   // The key to select the list item directly, 0 if there is no shortcut.
 	Selected      func() // The optional function which is called when the item is selected.
+	Reference     any    // An optional reference object.
+
+	separatorAfter bool // Whether a separator line is drawn after this item.
+	marked         bool // Whether the item is part of the multi-selection.
+	header         bool // Whether the item is a section header.
+	collapsed      bool // Whether the items of a header's section are hidden.
 }
 
 // List displays rows of items, each of which can be selected.
 //
+// Items may be grouped into sections by adding headers with AddHeader(). A
+// section's header remains visible at the top of the list while the section
+// is scrolled through. Sections can be made collapsible with
+// SetSectionsCollapsible().
+//
 // See https://github.com/rivo/tview/wiki/List for an example.
 type List struct {
 	*Box
@@ -42,6 +53,12 @@ type List struct {
 	// The style for selected items.
 	selectedStyle tcell.Style
 
+	// The style of section headers.
+	headerStyle tcell.Style
+
+	// Whether sections can be collapsed and expanded by the user.
+	collapsible bool
+
 	// If true, the selection is only shown when the list has focus.
 	selectedFocusOnly bool
 
@@ -74,6 +91,31 @@ type List struct {
 
 	// An optional function which is called when the user presses the Escape key.
 	done func()
+
+	// An optional function which computes the main text of visible items. If
+	// set, it overrides the items' stored main texts during drawing.
+	mainTextFunc func(index int, reference any) string
+
+	// An optional function which decides which items are shown. Items for
+	// which it returns false are neither drawn nor navigable.
+	filter func(index int, mainText, secondaryText string) bool
+
+	// The indices of the items which are shown, see visibleIndices(). This is
+	// nil if the items or the filter have changed since it was determined.
+	visible []int
+
+	// Whether or not multiple items can be selected, see SetMultiSelect().
+	multiSelect bool
+
+	// The indicators drawn in front of selected and unselected items in
+	// multi-select mode.
+	markedIndicator, unmarkedIndicator string
+
+	// The index of the item where a range selection starts.
+	anchorItem int
+
+	// The drop-down whose options this list shows, if any.
+	dropDown *DropDown
 }
 
 // NewList returns a new list.
@@ -86,13 +128,18 @@ func NewList() *List {
 		secondaryTextStyle: tcell.StyleDefault.Foreground(Styles.TertiaryTextColor),
 		shortcutStyle:      tcell.StyleDefault.Foreground(Styles.SecondaryTextColor),
 		selectedStyle:      tcell.StyleDefault.Foreground(Styles.PrimitiveBackgroundColor).Background(Styles.PrimaryTextColor),
+		headerStyle:        tcell.StyleDefault.Foreground(Styles.TitleColor).Bold(true),
+		markedIndicator:    "[x] ",
+		unmarkedIndicator:  "[ ] ",
 	}
 }
 
 // SetCurrentItem sets the currently selected item by its index, starting at 0
 // for the first item. If a negative index is provided, items are referred to
 // from the back (-1 = last item, -2 = second-to-last item, and so on). Out of
-// range indices are clamped to the beginning/end.
+// range indices are clamped to the beginning/end. Headers are skipped unless
+// sections are collapsible and a collapsed section containing the item is
+// expanded (see AddHeader()).
 //
 // Calling this function triggers a "changed" event if the selection changes.
 func (l *List) SetCurrentItem(index int) *List {
@@ -105,6 +152,13 @@ func (l *List) SetCurrentItem(index int) *List {
 	if index < 0 {
 		index = 0
 	}
+	if selectable := l.nextSelectable(index); selectable >= 0 {
+		index = selectable
+	}
+	if header := l.sectionHeader(index); header >= 0 && l.items[header].collapsed && !l.items[index].header {
+		l.items[header].collapsed = false
+		l.visible = nil
+	}
 
 	if index != l.currentItem && l.changed != nil {
 		item := l.items[index]
@@ -119,11 +173,28 @@ func (l *List) SetCurrentItem(index int) *List {
 }
 
 // GetCurrentItem returns the index of the currently selected list item,
-// starting at 0 for the first item.
+// starting at 0 for the first item. This is the index among all items,
+// regardless of any filter (see SetFilter()), as expected by SetCurrentItem().
+// Use GetCurrentItemPosition() to get the position of the item on screen.
 func (l *List) GetCurrentItem() int {
 	return l.currentItem
 }
 
+// GetCurrentItemOriginalIndex returns the index of the currently selected list
+// item among all items, regardless of any filter. It is the same as
+// GetCurrentItem().
+func (l *List) GetCurrentItemOriginalIndex() int {
+	return l.currentItem
+}
+
+// GetCurrentItemPosition returns the position of the currently selected item
+// among the items which are shown, i.e. the items which pass the filter (see
+// SetFilter()) and which are not part of a collapsed section. It returns -1 if
+// the current item is not shown.
+func (l *List) GetCurrentItemPosition() int {
+	return l.visiblePosition(l.visibleIndices(), l.currentItem)
+}
+
 // SetOffset sets the number of items to be skipped (vertically) as well as the
 // number of cells skipped horizontally when the list is drawn. Note that one
 // item corresponds to two rows when there are secondary texts. Shortcuts are
@@ -171,6 +242,7 @@ func (l *List) RemoveItem(index int) *List {
 
 	// Remove item.
 	l.items = append(l.items[:index], l.items[index+1:]...)
+	l.visible = nil
 
 	// If there is nothing left, we're done.
 	if len(l.items) == 0 {
@@ -182,6 +254,9 @@ func (l *List) RemoveItem(index int) *List {
 	if l.currentItem > index || l.currentItem == len(l.items) {
 		l.currentItem--
 	}
+	if selectable := l.nextSelectable(l.currentItem); selectable >= 0 {
+		l.currentItem = selectable // Don't select a header.
+	}
 
 	// Fire "changed" event for removed items.
 	if previousCurrentItem == index && l.changed != nil {
@@ -195,6 +270,7 @@ func (l *List) RemoveItem(index int) *List {
 // SetMainTextColor sets the color of the items' main text.
 func (l *List) SetMainTextColor(color tcell.Color) *List {
 	l.mainTextStyle = l.mainTextStyle.Foreground(color)
+	l.overrides.set(&l.mainTextStyle)
 	return l
 }
 
@@ -203,12 +279,14 @@ func (l *List) SetMainTextColor(color tcell.Color) *List {
 // the list itself.
 func (l *List) SetMainTextStyle(style tcell.Style) *List {
 	l.mainTextStyle = style
+	l.overrides.set(&l.mainTextStyle)
 	return l
 }
 
 // SetSecondaryTextColor sets the color of the items' secondary text.
 func (l *List) SetSecondaryTextColor(color tcell.Color) *List {
 	l.secondaryTextStyle = l.secondaryTextStyle.Foreground(color)
+	l.overrides.set(&l.secondaryTextStyle)
 	return l
 }
 
@@ -217,12 +295,14 @@ func (l *List) SetSecondaryTextColor(color tcell.Color) *List {
 // of the list itself.
 func (l *List) SetSecondaryTextStyle(style tcell.Style) *List {
 	l.secondaryTextStyle = style
+	l.overrides.set(&l.secondaryTextStyle)
 	return l
 }
 
 // SetShortcutColor sets the color of the items' shortcut.
 func (l *List) SetShortcutColor(color tcell.Color) *List {
 	l.shortcutStyle = l.shortcutStyle.Foreground(color)
+	l.overrides.set(&l.shortcutStyle)
 	return l
 }
 
@@ -231,20 +311,77 @@ func (l *List) SetShortcutColor(color tcell.Color) *List {
 // the list itself.
 func (l *List) SetShortcutStyle(style tcell.Style) *List {
 	l.shortcutStyle = style
+	l.overrides.set(&l.shortcutStyle)
+	return l
+}
+
+// SetHeaderStyle sets the style of section headers (see AddHeader()).
+func (l *List) SetHeaderStyle(style tcell.Style) *List {
+	l.headerStyle = style
+	l.overrides.set(&l.headerStyle)
+	return l
+}
+
+// SetSectionsCollapsible sets whether the user can collapse and expand the
+// sections of the list (see AddHeader()). If set to true, headers can be
+// navigated to like other items and are drawn with an indicator showing
+// whether their section is collapsed. Pressing Enter on a header or clicking
+// on it toggles its section. The "selected" callbacks are not invoked for
+// headers but the "changed" callback is.
+func (l *List) SetSectionsCollapsible(collapsible bool) *List {
+	l.collapsible = collapsible
+	if selectable := l.nextSelectable(l.currentItem); selectable >= 0 {
+		l.currentItem = selectable
+	}
+	l.filterChanged()
 	return l
 }
 
+// SetSectionCollapsed hides (true) or shows (false) the items of the section
+// of the header with the given index. The items remain in the list and keep
+// their indices but are neither drawn nor navigable while they are hidden. If
+// the current item is hidden, the header becomes the current item if sections
+// are collapsible (see SetSectionsCollapsible()) or the next visible item
+// otherwise. Nothing happens if the item is not a header. Panics if the index
+// is out of range.
+//
+// Sections can be collapsed even if the user cannot collapse them.
+func (l *List) SetSectionCollapsed(header int, collapsed bool) *List {
+	item := l.items[header]
+	if !item.header || item.collapsed == collapsed {
+		return l
+	}
+	item.collapsed = collapsed
+	l.visible = nil
+	if collapsed && l.collapsible && l.sectionHeader(l.currentItem) == header {
+		l.currentItem = header
+		if l.changed != nil {
+			l.changed(header, item.MainText, item.SecondaryText, item.Shortcut)
+		}
+	}
+	l.filterChanged()
+	return l
+}
+
+// IsSectionCollapsed returns whether the items of the section of the header
+// with the given index are hidden. Panics if the index is out of range.
+func (l *List) IsSectionCollapsed(header int) bool {
+	return l.items[header].collapsed
+}
+
 // SetSelectedTextColor sets the text color of selected items. Note that the
 // color of main text characters that are different from the main text color
 // (e.g. color tags) is maintained.
 func (l *List) SetSelectedTextColor(color tcell.Color) *List {
 	l.selectedStyle = l.selectedStyle.Foreground(color)
+	l.overrides.set(&l.selectedStyle)
 	return l
 }
 
 // SetSelectedBackgroundColor sets the background color of selected items.
 func (l *List) SetSelectedBackgroundColor(color tcell.Color) *List {
 	l.selectedStyle = l.selectedStyle.Background(color)
+	l.overrides.set(&l.selectedStyle)
 	return l
 }
 
@@ -253,6 +390,7 @@ func (l *List) SetSelectedBackgroundColor(color tcell.Color) *List {
 // tags) is maintained.
 func (l *List) SetSelectedStyle(style tcell.Style) *List {
 	l.selectedStyle = style
+	l.overrides.set(&l.selectedStyle)
 	return l
 }
 
@@ -289,6 +427,98 @@ func (l *List) SetWrapAround(wrapAround bool) *List {
 	return l
 }
 
+// SetMultiSelect sets whether the user can select multiple items. In this
+// mode, an indicator is drawn in front of each item showing whether or not it
+// is selected (see SetSelectionIndicators()). The space bar toggles the
+// selection of the current item, and holding Shift while navigating with the
+// arrow keys, Home, End, Page Up, or Page Down selects all items between the
+// item where the navigation started and the new current item. Clicking on an
+// item with Shift held does the same with the mouse, clicking with Ctrl held
+// toggles the clicked item.
+//
+// The current item (see GetCurrentItem()) is independent of the selection.
+// Enter and regular mouse clicks still invoke the "selected" callbacks for the
+// current item. Use GetSelectedIndices() to read the selection back.
+func (l *List) SetMultiSelect(multiSelect bool) *List {
+	l.multiSelect = multiSelect
+	l.anchorItem = l.currentItem
+	return l
+}
+
+// SetSelectionIndicators sets the texts drawn in front of selected and
+// unselected items in multi-select mode (see SetMultiSelect()). They may
+// contain color tags and should have the same width. The defaults are "[x] "
+// and "[ ] ".
+func (l *List) SetSelectionIndicators(selected, unselected string) *List {
+	l.markedIndicator, l.unmarkedIndicator = selected, unselected
+	return l
+}
+
+// GetSelectedIndices returns the indices of all selected items in ascending
+// order, regardless of any filter (see SetFilter()). The selection is kept
+// when multi-select mode is turned off.
+func (l *List) GetSelectedIndices() (indices []int) {
+	for index, item := range l.items {
+		if item.marked {
+			indices = append(indices, index)
+		}
+	}
+	return
+}
+
+// SetItemSelected sets whether the item with the given index is part of the
+// selection in multi-select mode. Panics if the index is out of range.
+func (l *List) SetItemSelected(index int, selected bool) *List {
+	l.items[index].marked = selected
+	return l
+}
+
+// IsItemSelected returns whether the item with the given index is part of the
+// selection in multi-select mode. Panics if the index is out of range.
+func (l *List) IsItemSelected(index int) bool {
+	return l.items[index].marked
+}
+
+// SelectAll adds all items which pass the filter (see SetFilter()) to the
+// selection.
+func (l *List) SelectAll() *List {
+	for _, index := range l.visibleIndices() {
+		if !l.items[index].header {
+			l.items[index].marked = true
+		}
+	}
+	return l
+}
+
+// ClearSelection removes all items from the selection.
+func (l *List) ClearSelection() *List {
+	for _, item := range l.items {
+		item.marked = false
+	}
+	return l
+}
+
+// selectRange adds all visible items between the range selection anchor and
+// the item with the given index to the selection.
+func (l *List) selectRange(index int) {
+	visible := l.visibleIndices()
+	from, to := l.visiblePosition(visible, l.anchorItem), l.visiblePosition(visible, index)
+	if to < 0 {
+		return
+	}
+	if from < 0 {
+		from = to
+	}
+	if from > to {
+		from, to = to, from
+	}
+	for position := from; position <= to; position++ {
+		if item := l.items[visible[position]]; !item.header {
+			item.marked = true
+		}
+	}
+}
+
 // SetChangedFunc sets the function which is called when the user navigates to
 // a list item. The function receives the item's index in the list of items
 // (starting with 0), its main text, secondary text, and its shortcut rune.
@@ -316,6 +546,234 @@ func (l *List) SetDoneFunc(handler func()) *List {
 	return l
 }
 
+// SetMainTextFunc sets a function which computes the main text of each visible
+// item whenever the list is drawn, overriding the text stored with the item.
+// The function receives the item's index and its reference (see
+// SetItemReference()). This is useful for lists backed by domain objects whose
+// labels would otherwise have to be updated with SetItemText() on every change.
+//
+// Provide nil to use the stored main texts again.
+func (l *List) SetMainTextFunc(handler func(index int, reference any) string) *List {
+	l.mainTextFunc = handler
+	return l
+}
+
+// SetFilter sets a function which decides which items are shown. It receives
+// each item's index, main text, and secondary text and returns false for items
+// which are to be hidden. Hidden items are not removed from the list, they are
+// merely skipped when drawing and navigating. The filter may depend on external
+// state, for example text the user typed into an input field. Call SetFilter()
+// again when that state changes so that the selection is updated.
+//
+// If the currently selected item is hidden by the filter, the selection moves
+// to the next visible item (or the previous one if there is none). This
+// triggers a "changed" event.
+//
+// Provide nil (or call ClearFilter()) to show all items again.
+func (l *List) SetFilter(handler func(index int, mainText, secondaryText string) bool) *List {
+	l.filter = handler
+	l.visible = nil
+	l.filterChanged()
+	return l
+}
+
+// ClearFilter removes the filter set with SetFilter() so that all items are
+// shown again.
+func (l *List) ClearFilter() *List {
+	return l.SetFilter(nil)
+}
+
+// filterChanged keeps the current item and the offset valid after the filter
+// or the items it depends on have changed.
+func (l *List) filterChanged() {
+	visible := l.visibleIndices()
+	if len(visible) == 0 {
+		l.itemOffset = 0
+		return
+	}
+	selectable := l.selectableIndices()
+
+	// Move the selection to a visible item.
+	if len(selectable) > 0 && l.visiblePosition(selectable, l.currentItem) < 0 {
+		index := selectable[len(selectable)-1]
+		for _, v := range selectable {
+			if v > l.currentItem {
+				index = v
+				break
+			}
+		}
+		l.currentItem = index
+		if l.changed != nil {
+			item := l.items[index]
+			l.changed(index, item.MainText, item.SecondaryText, item.Shortcut)
+		}
+	}
+
+	// Keep the offset in range.
+	if l.itemOffset >= len(visible) {
+		l.itemOffset = len(visible) - 1
+	}
+	if l.itemOffset < 0 {
+		l.itemOffset = 0
+	}
+	l.adjustOffset()
+}
+
+// visibleIndices returns the indices of the items which pass the filter and
+// which are not part of a collapsed section, in ascending order.
+// The result is cached until the items or the filter change and must not be
+// modified.
+func (l *List) visibleIndices() []int {
+	if l.visible != nil {
+		return l.visible
+	}
+	visible := make([]int, 0, len(l.items))
+	var collapsed bool
+	for index, item := range l.items {
+		if item.header {
+			collapsed = item.collapsed
+		} else if collapsed {
+			continue
+		}
+		if l.filter == nil || l.filter(index, item.MainText, item.SecondaryText) {
+			visible = append(visible, index)
+		}
+	}
+	l.visible = visible
+	return visible
+}
+
+// selectable returns whether the item with the given index can become the
+// current item. Headers can only be selected if sections are collapsible.
+func (l *List) selectable(index int) bool {
+	return !l.items[index].header || l.collapsible
+}
+
+// selectableIndices returns the indices of the visible items which can become
+// the current item, in ascending order.
+func (l *List) selectableIndices() []int {
+	visible := l.visibleIndices()
+	selectable := make([]int, 0, len(visible))
+	for _, index := range visible {
+		if l.selectable(index) {
+			selectable = append(selectable, index)
+		}
+	}
+	return selectable
+}
+
+// nextSelectable returns the index of the first item at or after the given
+// index which can become the current item or, if there is none, the last such
+// item before the given index. It returns -1 if there is no such item.
+func (l *List) nextSelectable(index int) int {
+	for next := index; next < len(l.items); next++ {
+		if next >= 0 && l.selectable(next) {
+			return next
+		}
+	}
+	for previous := index - 1; previous >= 0; previous-- {
+		if previous < len(l.items) && l.selectable(previous) {
+			return previous
+		}
+	}
+	return -1
+}
+
+// sectionHeader returns the index of the header of the section the item with
+// the given index belongs to or -1 if it doesn't belong to a section. Headers
+// don't belong to their own sections.
+func (l *List) sectionHeader(index int) int {
+	for previous := index - 1; previous >= 0; previous-- {
+		if previous < len(l.items) && l.items[previous].header {
+			return previous
+		}
+	}
+	return -1
+}
+
+// toggleSection collapses the section of the header with the given index if it
+// is expanded and expands it otherwise.
+func (l *List) toggleSection(header int) {
+	l.SetSectionCollapsed(header, !l.items[header].collapsed)
+}
+
+// headerText returns the text drawn for the given header, including the
+// collapse indicator if sections are collapsible.
+func (l *List) headerText(item *listItem) string {
+	if !l.collapsible {
+		return item.MainText
+	}
+	if item.collapsed {
+		return "▸ " + item.MainText
+	}
+	return "▾ " + item.MainText
+}
+
+// stickyHeader returns the index of the header which is pinned to the top of
+// the list when the given visible items are drawn starting at the given
+// position, i.e. the header of the first drawn item's section if that header
+// was scrolled out of view. It returns -1 if no header is pinned.
+func (l *List) stickyHeader(visible []int, offset int) int {
+	if offset <= 0 || offset >= len(visible) || l.items[visible[offset]].header {
+		return -1
+	}
+	for position := offset - 1; position >= 0; position-- {
+		if index := visible[position]; l.items[index].header {
+			return index
+		}
+	}
+	return -1
+}
+
+// visiblePosition returns the position of the item with the given index among
+// the given visible items or -1 if the item is not visible.
+func (l *List) visiblePosition(visible []int, index int) int {
+	for position, v := range visible {
+		if v == index {
+			return position
+		}
+	}
+	return -1
+}
+
+// SetItemReference stores a reference of any type with the item at the given
+// index. Panics if the index is out of range.
+func (l *List) SetItemReference(index int, reference any) *List {
+	l.items[index].Reference = reference
+	return l
+}
+
+// GetItemReference returns the reference stored with the item at the given
+// index. Panics if the index is out of range.
+func (l *List) GetItemReference(index int) any {
+	return l.items[index].Reference
+}
+
+// AddHeader adds a section header with the given text to the end of the list.
+// The following items, up to the next header, form the header's section. A
+// header cannot be selected and is skipped when navigating the list unless
+// sections are collapsible (see SetSectionsCollapsible()). Clicks on headers
+// are ignored in that case. While the items of a section are scrolled through,
+// its header is pinned to the top of the list. Headers are drawn across the
+// full width with the style set with SetHeaderStyle(), without shortcuts and
+// secondary texts.
+//
+// Headers are items, too. They count towards GetItemCount() and are subject
+// to the filter (see SetFilter()).
+func (l *List) AddHeader(text string) *List {
+	l.insertItem(-1, &listItem{
+		MainText: text,
+		header:   true,
+	})
+	return l
+}
+
+// IsHeader returns whether the item with the given index is a section header
+// (see AddHeader()). Panics if the index is out of range.
+func (l *List) IsHeader(index int) bool {
+	return l.items[index].header
+}
+
 // AddItem calls InsertItem() with an index of -1.
 func (l *List) AddItem(mainText, secondaryText string, shortcut rune, selected func()) *List {
 	l.InsertItem(-1, mainText, secondaryText, shortcut, selected)
@@ -345,13 +803,18 @@ func (l *List) AddItem(mainText, secondaryText string, shortcut rune, selected f
 // was previously empty, a "changed" event is fired because the new item becomes
 // selected.
 func (l *List) InsertItem(index int, mainText, secondaryText string, shortcut rune, selected func()) *List {
-	item := &listItem{
+	l.insertItem(index, &listItem{
 		MainText:      mainText,
 		SecondaryText: secondaryText,
 		Shortcut:      shortcut,
 		Selected:      selected,
-	}
+	})
+	return l
+}
 
+// insertItem inserts the given item at the given index as described for
+// InsertItem().
+func (l *List) insertItem(index int, item *listItem) {
 	// Shift index to range.
 	if index < 0 {
 		index = len(l.items) + index + 1
@@ -373,14 +836,21 @@ func (l *List) InsertItem(index int, mainText, secondaryText string, shortcut ru
 		copy(l.items[index+1:], l.items[index:])
 	}
 	l.items[index] = item
+	l.visible = nil
 
-	// Fire a "change" event for the first item in the list.
-	if len(l.items) == 1 && l.changed != nil {
+	// Fire a "change" event for the first selectable item in the list.
+	if l.currentItem < len(l.items) && !l.selectable(l.currentItem) {
+		if selectable := l.nextSelectable(l.currentItem); selectable >= 0 {
+			l.currentItem = selectable
+			if l.changed != nil {
+				item := l.items[selectable]
+				l.changed(selectable, item.MainText, item.SecondaryText, item.Shortcut)
+			}
+		}
+	} else if len(l.items) == 1 && l.changed != nil {
 		item := l.items[0]
 		l.changed(0, item.MainText, item.SecondaryText, item.Shortcut)
 	}
-
-	return l
 }
 
 // GetItemCount returns the number of items in the list.
@@ -400,9 +870,38 @@ func (l *List) SetItemText(index int, main, secondary string) *List {
 	item := l.items[index]
 	item.MainText = main
 	item.SecondaryText = secondary
+	l.visible = nil
+	return l
+}
+
+// SetSeparatorAfter sets whether a horizontal separator line is drawn after the
+// item with the given index, e.g. to group the items of a menu. Separators are
+// not items themselves and are therefore skipped when navigating the list.
+// Panics if the index is out of range.
+func (l *List) SetSeparatorAfter(index int, separator bool) *List {
+	l.items[index].separatorAfter = separator
 	return l
 }
 
+// HasSeparatorAfter returns whether a separator line is drawn after the item
+// with the given index. Panics if the index is out of range.
+func (l *List) HasSeparatorAfter(index int) bool {
+	return l.items[index].separatorAfter
+}
+
+// itemHeight returns the number of rows occupied by the item with the given
+// index, including its secondary text and a separator line after it.
+func (l *List) itemHeight(index int) int {
+	height := 1
+	if l.showSecondaryText && !l.items[index].header {
+		height++
+	}
+	if l.items[index].separatorAfter {
+		height++
+	}
+	return height
+}
+
 // FindItems searches the main and secondary texts for the given strings and
 // returns a list of item indices in which those strings are found. One of the
 // two search strings may be empty, it will then be ignored. Indices are always
@@ -446,16 +945,41 @@ func (l *List) FindItems(mainSearch, secondarySearch string, mustContainBoth, ig
 // Clear removes all items from the list.
 func (l *List) Clear() *List {
 	l.items = nil
+	l.visible = nil
 	l.currentItem = 0
+	l.anchorItem = 0
 	return l
 }
 
+// printItemText prints the main or secondary text of an item, skipping the
+// cells of the current horizontal offset. Text which is cut off at the right
+// ends with an ellipsis unless the list is scrolled horizontally. It returns the
+// printed width and whether the text was cut off.
+func (l *List) printItemText(screen tcell.Screen, text string, x, y, width int, style tcell.Style) (printedWidth int, truncated bool) {
+	if l.horizontalOffset == 0 {
+		return printWithEllipsis(screen, text, x, y, width, AlignLeft, style, true), TaggedStringWidth(text) > width
+	}
+	_, printedWidth, _, end := printWithStyle(screen, text, x, y, l.horizontalOffset, width, AlignLeft, style, true)
+	return printedWidth, end < len(text)
+}
+
+// applyTheme implements the themeable interface.
+func (l *List) applyTheme(from, to *Theme) {
+	l.Box.applyTheme(from, to)
+	l.overrides.style(&l.mainTextStyle, tcell.StyleDefault.Foreground(to.PrimaryTextColor))
+	l.overrides.style(&l.secondaryTextStyle, tcell.StyleDefault.Foreground(to.TertiaryTextColor))
+	l.overrides.style(&l.shortcutStyle, tcell.StyleDefault.Foreground(to.SecondaryTextColor))
+	l.overrides.style(&l.selectedStyle, tcell.StyleDefault.Foreground(to.PrimitiveBackgroundColor).Background(to.PrimaryTextColor))
+	l.overrides.style(&l.headerStyle, tcell.StyleDefault.Foreground(to.TitleColor).Bold(true))
+}
+
 // Draw draws this primitive onto the screen.
 func (l *List) Draw(screen tcell.Screen) {
 	l.Box.DrawForSubclass(screen, l)
 
 	// Determine the dimensions.
 	x, y, width, height := l.GetInnerRect()
+	separatorX, separatorWidth := x, width
 	bottomLimit := y + height
 	_, totalHeight := screen.Size()
 	if bottomLimit > totalHeight {
@@ -473,6 +997,20 @@ func (l *List) Draw(screen tcell.Screen) {
 		}
 	}
 
+	// Make room for the selection indicators.
+	var indicatorWidth int
+	if l.multiSelect {
+		indicatorWidth = TaggedStringWidth(l.markedIndicator)
+		if w := TaggedStringWidth(l.unmarkedIndicator); w > indicatorWidth {
+			indicatorWidth = w
+		}
+		if indicatorWidth > width {
+			indicatorWidth = width
+		}
+		x += indicatorWidth
+		width -= indicatorWidth
+	}
+
 	if l.horizontalOffset < 0 {
 		l.horizontalOffset = 0
 	}
@@ -482,26 +1020,69 @@ func (l *List) Draw(screen tcell.Screen) {
 		maxWidth    int  // The maximum printed item width.
 		overflowing bool // Whether a text's end exceeds the right border.
 	)
-	for index, item := range l.items {
-		if index < l.itemOffset {
+	drawSeparator := func() {
+		style := tcell.StyleDefault.Background(l.backgroundColor).Foreground(Styles.GraphicsColor)
+		for bx := 0; bx < separatorWidth; bx++ {
+			screen.SetContent(separatorX+bx, y, Borders.Horizontal, nil, style)
+		}
+	}
+	visible := l.visibleIndices()
+	if header := l.stickyHeader(visible, l.itemOffset); header >= 0 && y < bottomLimit {
+		printWithEllipsis(screen, l.headerText(l.items[header]), separatorX, y, separatorWidth, AlignLeft, l.headerStyle, true)
+		y++
+	}
+	for position, index := range visible {
+		if position < l.itemOffset {
 			continue
 		}
+		item := l.items[index]
 
 		if y >= bottomLimit {
 			break
 		}
 
+		// Section headers span the entire width.
+		if item.header {
+			style := l.headerStyle
+			if index == l.currentItem && (!l.selectedFocusOnly || l.HasFocus()) {
+				style = l.selectedStyle
+				for bx := 0; bx < separatorWidth; bx++ {
+					screen.SetContent(separatorX+bx, y, ' ', nil, style)
+				}
+			}
+			printWithEllipsis(screen, l.headerText(item), separatorX, y, separatorWidth, AlignLeft, style, true)
+			y++
+			if item.separatorAfter && y < bottomLimit {
+				drawSeparator()
+				y++
+			}
+			continue
+		}
+
 		// Shortcuts.
 		if showShortcuts && item.Shortcut != 0 {
-			printWithStyle(screen, fmt.Sprintf("(%s)", string(item.Shortcut)), x-5, y, 0, 4, AlignRight, l.shortcutStyle, true)
+			printWithStyle(screen, fmt.Sprintf("(%s)", string(item.Shortcut)), x-indicatorWidth-5, y, 0, 4, AlignRight, l.shortcutStyle, true)
+		}
+
+		// Selection indicator.
+		if l.multiSelect {
+			indicator := l.unmarkedIndicator
+			if item.marked {
+				indicator = l.markedIndicator
+			}
+			printWithStyle(screen, indicator, x-indicatorWidth, y, 0, indicatorWidth, AlignLeft, l.mainTextStyle, true)
 		}
 
 		// Main text.
-		_, printedWidth, _, end := printWithStyle(screen, item.MainText, x, y, l.horizontalOffset, width, AlignLeft, l.mainTextStyle, true)
+		mainText := item.MainText
+		if l.mainTextFunc != nil {
+			mainText = l.mainTextFunc(index, item.Reference)
+		}
+		printedWidth, truncated := l.printItemText(screen, mainText, x, y, width, l.mainTextStyle)
 		if printedWidth > maxWidth {
 			maxWidth = printedWidth
 		}
-		if end < len(item.MainText) {
+		if truncated {
 			overflowing = true
 		}
 
@@ -509,7 +1090,7 @@ func (l *List) Draw(screen tcell.Screen) {
 		if index == l.currentItem && (!l.selectedFocusOnly || l.HasFocus()) {
 			textWidth := width
 			if !l.highlightFullLine {
-				if w := TaggedStringWidth(item.MainText); w < textWidth {
+				if w := TaggedStringWidth(mainText) - l.horizontalOffset; w < textWidth {
 					textWidth = w
 				}
 			}
@@ -534,15 +1115,21 @@ func (l *List) Draw(screen tcell.Screen) {
 
 		// Secondary text.
 		if l.showSecondaryText {
-			_, printedWidth, _, end := printWithStyle(screen, item.SecondaryText, x, y, l.horizontalOffset, width, AlignLeft, l.secondaryTextStyle, true)
+			printedWidth, truncated := l.printItemText(screen, item.SecondaryText, x, y, width, l.secondaryTextStyle)
 			if printedWidth > maxWidth {
 				maxWidth = printedWidth
 			}
-			if end < len(item.SecondaryText) {
+			if truncated {
 				overflowing = true
 			}
 			y++
 		}
+
+		// Separator.
+		if item.separatorAfter && y < bottomLimit {
+			drawSeparator()
+			y++
+		}
 	}
 
 	// We don't want the item text to get out of view. If the horizontal offset
@@ -562,16 +1149,32 @@ func (l *List) adjustOffset() {
 	if height == 0 {
 		return
 	}
-	if l.currentItem < l.itemOffset {
-		l.itemOffset = l.currentItem
-	} else if l.showSecondaryText {
-		if 2*(l.currentItem-l.itemOffset) >= height-1 {
-			l.itemOffset = (2*l.currentItem + 3 - height) / 2
+	visible := l.visibleIndices()
+	current := l.visiblePosition(visible, l.currentItem)
+	if current < 0 {
+		return
+	}
+	if current < l.itemOffset {
+		l.itemOffset = current
+		return
+	}
+
+	// Scroll down until the current item's text fits.
+	for l.itemOffset < current {
+		rows := 1
+		if l.showSecondaryText {
+			rows++
+		}
+		if l.stickyHeader(visible, l.itemOffset) >= 0 {
+			rows++
 		}
-	} else {
-		if l.currentItem-l.itemOffset >= height {
-			l.itemOffset = l.currentItem + 1 - height
+		for position := l.itemOffset; position < current; position++ {
+			rows += l.itemHeight(visible[position])
+		}
+		if rows <= height {
+			break
 		}
+		l.itemOffset++
 	}
 }
 
@@ -583,65 +1186,95 @@ func (l *List) InputHandler() func(event *tcell.EventKey, setFocus func(p Primit
 				l.done()
 			}
 			return
-		} else if len(l.items) == 0 {
-			return
 		}
 
+		// Navigation happens among the visible items only, skipping headers
+		// unless sections are collapsible.
+		visible := l.selectableIndices()
+		if len(visible) == 0 {
+			return
+		}
 		previousItem := l.currentItem
+		current := l.visiblePosition(visible, l.currentItem)
+		if current < 0 {
+			current = 0
+		}
+		selectCurrent := func() {
+			index := visible[current]
+			item := l.items[index]
+			if item.header {
+				l.toggleSection(index)
+				return
+			}
+			if item.Selected != nil {
+				item.Selected()
+			}
+			if l.selected != nil {
+				l.selected(index, item.MainText, item.SecondaryText, item.Shortcut)
+			}
+		}
+
+		// In multi-select mode, Shift extends the selection and the space bar
+		// toggles it.
+		var extend bool
+		if l.multiSelect {
+			switch event.Key() {
+			case tcell.KeyUp, tcell.KeyDown, tcell.KeyHome, tcell.KeyEnd, tcell.KeyPgUp, tcell.KeyPgDn:
+				extend = event.Modifiers()&tcell.ModShift != 0
+			case tcell.KeyRune:
+				if item := l.items[visible[current]]; event.Rune() == ' ' && !item.header {
+					item.marked = !item.marked
+					l.anchorItem = visible[current]
+					return
+				}
+			}
+		}
 
 		switch key := event.Key(); key {
 		case tcell.KeyTab, tcell.KeyDown:
-			l.currentItem++
+			current++
 		case tcell.KeyBacktab, tcell.KeyUp:
-			l.currentItem--
+			current--
 		case tcell.KeyRight:
 			if l.overflowing {
 				l.horizontalOffset += 2 // We shift by 2 to account for two-cell characters.
 			} else {
-				l.currentItem++
+				current++
 			}
 		case tcell.KeyLeft:
 			if l.horizontalOffset > 0 {
 				l.horizontalOffset -= 2
 			} else {
-				l.currentItem--
+				current--
 			}
 		case tcell.KeyHome:
-			l.currentItem = 0
+			current = 0
 		case tcell.KeyEnd:
-			l.currentItem = len(l.items) - 1
+			current = len(visible) - 1
 		case tcell.KeyPgDn:
 			_, _, _, height := l.GetInnerRect()
-			l.currentItem += height
-			if l.currentItem >= len(l.items) {
-				l.currentItem = len(l.items) - 1
+			current += height
+			if current >= len(visible) {
+				current = len(visible) - 1
 			}
 		case tcell.KeyPgUp:
 			_, _, _, height := l.GetInnerRect()
-			l.currentItem -= height
-			if l.currentItem < 0 {
-				l.currentItem = 0
+			current -= height
+			if current < 0 {
+				current = 0
 			}
 		case tcell.KeyEnter:
-			if l.currentItem >= 0 && l.currentItem < len(l.items) {
-				item := l.items[l.currentItem]
-				if item.Selected != nil {
-					item.Selected()
-				}
-				if l.selected != nil {
-					l.selected(l.currentItem, item.MainText, item.SecondaryText, item.Shortcut)
-				}
-			}
+			selectCurrent()
 		case tcell.KeyRune:
 			ch := event.Rune()
 			if ch != ' ' {
 				// It's not a space bar. Is it a shortcut?
 				var found bool
-				for index, item := range l.items {
-					if item.Shortcut == ch {
+				for position, index := range visible {
+					if l.items[index].Shortcut == ch {
 						// We have a shortcut.
 						found = true
-						l.currentItem = index
+						current = position
 						break
 					}
 				}
@@ -649,28 +1282,28 @@ func (l *List) InputHandler() func(event *tcell.EventKey, setFocus func(p Primit
 					break
 				}
 			}
-			item := l.items[l.currentItem]
-			if item.Selected != nil {
-				item.Selected()
-			}
-			if l.selected != nil {
-				l.selected(l.currentItem, item.MainText, item.SecondaryText, item.Shortcut)
-			}
+			selectCurrent()
 		}
 
-		if l.currentItem < 0 {
+		if current < 0 {
 			if l.wrapAround {
-				l.currentItem = len(l.items) - 1
+				current = len(visible) - 1
 			} else {
-				l.currentItem = 0
+				current = 0
 			}
-		} else if l.currentItem >= len(l.items) {
+		} else if current >= len(visible) {
 			if l.wrapAround {
-				l.currentItem = 0
+				current = 0
 			} else {
-				l.currentItem = len(l.items) - 1
+				current = len(visible) - 1
 			}
 		}
+		l.currentItem = visible[current]
+		if extend {
+			l.selectRange(l.currentItem)
+		} else if l.currentItem != previousItem {
+			l.anchorItem = l.currentItem
+		}
 
 		if l.currentItem != previousItem && l.currentItem < len(l.items) {
 			if l.changed != nil {
@@ -690,16 +1323,27 @@ func (l *List) indexAtPoint(x, y int) int {
 		return -1
 	}
 
-	index := y - rectY
-	if l.showSecondaryText {
-		index /= 2
+	row := rectY
+	visible := l.visibleIndices()
+	if header := l.stickyHeader(visible, l.itemOffset); header >= 0 {
+		if y == row {
+			return header
+		}
+		row++
 	}
-	index += l.itemOffset
-
-	if index >= len(l.items) {
-		return -1
+	for position := l.itemOffset; position < len(visible); position++ {
+		index := visible[position]
+		itemHeight := l.itemHeight(index)
+		if y < row+itemHeight {
+			if l.items[index].separatorAfter && y == row+itemHeight-1 {
+				return -1 // This is the separator line.
+			}
+			return index
+		}
+		row += itemHeight
 	}
-	return index
+
+	return -1
 }
 
 // MouseHandler returns the mouse handler for this primitive.
@@ -714,7 +1358,32 @@ func (l *List) MouseHandler() func(action MouseAction, event *tcell.EventMouse,
 		case MouseLeftClick:
 			setFocus(l)
 			index := l.indexAtPoint(event.Position())
-			if index != -1 {
+			if index != -1 && l.items[index].header {
+				if l.collapsible {
+					if index != l.currentItem && l.changed != nil {
+						l.changed(index, l.items[index].MainText, "", 0)
+					}
+					l.currentItem = index
+					l.toggleSection(index)
+				}
+				return true, nil
+			}
+			if index != -1 && l.multiSelect && event.Modifiers()&(tcell.ModShift|tcell.ModCtrl) != 0 {
+				// Change the selection only.
+				if event.Modifiers()&tcell.ModShift != 0 {
+					l.selectRange(index)
+				} else {
+					l.items[index].marked = !l.items[index].marked
+					l.anchorItem = index
+				}
+				if index != l.currentItem && l.changed != nil {
+					item := l.items[index]
+					l.changed(index, item.MainText, item.SecondaryText, item.Shortcut)
+				}
+				l.currentItem = index
+				l.adjustOffset()
+			} else if index != -1 {
+				l.anchorItem = index
 				item := l.items[index]
 				if item.Selected != nil {
 					item.Selected()
@@ -737,9 +1406,13 @@ func (l *List) MouseHandler() func(action MouseAction, event *tcell.EventMouse,
 			}
 			consumed = true
 		case MouseScrollDown:
-			lines := len(l.items) - l.itemOffset
-			if l.showSecondaryText {
-				lines *= 2
+			var lines int
+			visible := l.visibleIndices()
+			if l.stickyHeader(visible, l.itemOffset) >= 0 {
+				lines++
+			}
+			for position := l.itemOffset; position < len(visible); position++ {
+				lines += l.itemHeight(visible[position])
 			}
 			if _, _, _, height := l.GetInnerRect(); lines > height {
 				l.itemOffset++
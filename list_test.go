@@ -0,0 +1,167 @@
+package tview
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestListMainTextFunc(t *testing.T) {
+	screen := newTestScreen(t, 20, 4)
+	list := NewList().ShowSecondaryText(false).
+		AddItem("stored 1", "", 0, nil).
+		AddItem("stored 2", "", 0, nil)
+	list.SetItemReference(0, "alpha").SetItemReference(1, "beta")
+	list.SetRect(0, 0, 20, 4)
+
+	// Without a function, the stored texts are drawn.
+	list.Draw(screen)
+	for row, expected := range []string{"stored 1", "stored 2"} {
+		if line := screenLine(screen, row); !strings.HasPrefix(line, expected) {
+			t.Errorf("row %d is %q, expected %q", row, line, expected)
+		}
+	}
+
+	// With a function, the computed texts are drawn.
+	list.SetMainTextFunc(func(index int, reference any) string {
+		return strings.ToUpper(reference.(string))
+	})
+	screen.Clear()
+	list.Draw(screen)
+	for row, expected := range []string{"ALPHA   ", "BETA    "} {
+		if line := screenLine(screen, row); !strings.HasPrefix(line, expected) {
+			t.Errorf("row %d is %q, expected %q", row, line, expected)
+		}
+	}
+	if main, _ := list.GetItemText(0); main != "stored 1" {
+		t.Errorf("stored text changed to %q", main)
+	}
+
+	// Removing the function restores the stored texts.
+	list.SetMainTextFunc(nil)
+	screen.Clear()
+	list.Draw(screen)
+	if line := screenLine(screen, 0); !strings.HasPrefix(line, "stored 1") {
+		t.Errorf("row 0 is %q, expected the stored text", line)
+	}
+}
+
+func TestListFilterCurrentItem(t *testing.T) {
+	list := NewList().ShowSecondaryText(false)
+	for _, text := range []string{"apple", "banana", "cherry", "blueberry"} {
+		list.AddItem(text, "", 0, nil)
+	}
+	list.SetFilter(func(index int, mainText, secondaryText string) bool {
+		return strings.HasPrefix(mainText, "b")
+	})
+
+	// The current item moved to the first visible item.
+	if index := list.GetCurrentItem(); index != 1 {
+		t.Errorf("current item is %d, expected 1", index)
+	}
+
+	// GetCurrentItem() and SetCurrentItem() use the same indices.
+	list.SetCurrentItem(3)
+	if index := list.GetCurrentItem(); index != 3 {
+		t.Errorf("current item is %d after selecting 3, expected 3", index)
+	}
+	list.SetCurrentItem(list.GetCurrentItem())
+	if index := list.GetCurrentItem(); index != 3 {
+		t.Errorf("current item is %d after reselecting it, expected 3", index)
+	}
+	if index := list.GetCurrentItemOriginalIndex(); index != 3 {
+		t.Errorf("original index is %d, expected 3", index)
+	}
+	if position := list.GetCurrentItemPosition(); position != 1 {
+		t.Errorf("position is %d, expected 1", position)
+	}
+
+	// Changed items update the visible items.
+	list.SetItemText(0, "bilberry", "")
+	if position := list.GetCurrentItemPosition(); position != 2 {
+		t.Errorf("position is %d after showing another item, expected 2", position)
+	}
+	list.InsertItem(0, "blackberry", "", 0, nil)
+	if position := list.GetCurrentItemPosition(); position != 3 {
+		t.Errorf("position is %d after inserting an item, expected 3", position)
+	}
+	list.RemoveItem(1)
+	if position := list.GetCurrentItemPosition(); position != 2 {
+		t.Errorf("position is %d after removing an item, expected 2", position)
+	}
+	list.ClearFilter()
+	if index, position := list.GetCurrentItem(), list.GetCurrentItemPosition(); index != 3 || position != 3 {
+		t.Errorf("current item is %d at position %d without a filter, expected 3 at 3", index, position)
+	}
+}
+
+func TestListSeparatorAfter(t *testing.T) {
+	screen := newTestScreen(t, 10, 5)
+	list := NewList().ShowSecondaryText(false).
+		AddItem("one", "", 0, nil).
+		AddItem("two", "", 0, nil).
+		AddItem("three", "", 0, nil).
+		SetSeparatorAfter(0, true)
+	list.SetRect(0, 0, 10, 5)
+
+	// The separator is drawn on the row after the item.
+	list.Draw(screen)
+	separator := strings.Repeat(string(Borders.Horizontal), 10)
+	for y, expected := range []string{"one", separator, "two", "three"} {
+		if line := screenLine(screen, y); !strings.HasPrefix(line, expected) {
+			t.Errorf("row %d is %q, expected %q", y, line, expected)
+		}
+	}
+	if !list.HasSeparatorAfter(0) || list.HasSeparatorAfter(1) {
+		t.Error("HasSeparatorAfter() doesn't match the separators set")
+	}
+
+	// Navigation skips the separator.
+	list.InputHandler()(tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModNone), func(p Primitive) {})
+	if index := list.GetCurrentItem(); index != 1 {
+		t.Errorf("current item is %d after moving down, expected 1", index)
+	}
+	list.InputHandler()(tcell.NewEventKey(tcell.KeyUp, 0, tcell.ModNone), func(p Primitive) {})
+	if index := list.GetCurrentItem(); index != 0 {
+		t.Errorf("current item is %d after moving up, expected 0", index)
+	}
+
+	// Clicking the separator selects nothing.
+	list.MouseHandler()(MouseLeftClick, tcell.NewEventMouse(2, 1, tcell.Button1, tcell.ModNone), func(p Primitive) {})
+	if index := list.GetCurrentItem(); index != 0 {
+		t.Errorf("current item is %d after clicking the separator, expected 0", index)
+	}
+	list.MouseHandler()(MouseLeftClick, tcell.NewEventMouse(2, 2, tcell.Button1, tcell.ModNone), func(p Primitive) {})
+	if index := list.GetCurrentItem(); index != 1 {
+		t.Errorf("current item is %d after clicking the second item, expected 1", index)
+	}
+
+	// Removing the separator moves the following items up.
+	list.SetSeparatorAfter(0, false)
+	screen.Clear()
+	list.Draw(screen)
+	if line := screenLine(screen, 1); !strings.HasPrefix(line, "two") {
+		t.Errorf("row 1 is %q without a separator, expected %q", line, "two")
+	}
+}
+
+func TestListItemEllipsis(t *testing.T) {
+	screen := newTestScreen(t, 8, 2)
+	list := NewList().AddItem("abcdefghij", "日本語タイトル", 0, nil)
+	list.SetRect(0, 0, 6, 2)
+	list.Draw(screen)
+	for row, expected := range []string{"abcde…  ", "日 本 …   "} {
+		if line := screenLine(screen, row); line != expected {
+			t.Errorf("row %d is %q, expected %q", row, line, expected)
+		}
+	}
+
+	// Scrolled horizontally, the text is cut off without an ellipsis.
+	list.SetOffset(0, 2)
+	screen.Clear()
+	list.Draw(screen)
+	if line := screenLine(screen, 0); line != "cdefgh  " {
+		t.Errorf("row 0 is %q after scrolling, expected %q", line, "cdefgh  ")
+	}
+}
@@ -26,6 +26,17 @@ type Modal struct {
 	// The text color.
 	textColor tcell.Color
 
+	// An optional primitive shown instead of the message text. nil if the
+	// text is shown.
+	content Primitive
+
+	// The requested size of the content primitive.
+	contentWidth, contentHeight int
+
+	// The layout combining the content primitive and the buttons. nil if no
+	// content primitive was set.
+	layout *Flex
+
 	// The optional callback for when the user clicked one of the buttons. It
 	// receives the index of the clicked button and the button's label.
 	done func(buttonIndex int, buttonLabel string)
@@ -58,24 +69,28 @@ func NewModal() *Modal {
 func (m *Modal) SetBackgroundColor(color tcell.Color) *Modal {
 	m.form.SetBackgroundColor(color)
 	m.frame.SetBackgroundColor(color)
+	m.overrides.set(&m.form.backgroundColor, &m.frame.backgroundColor)
 	return m
 }
 
 // SetTextColor sets the color of the message text.
 func (m *Modal) SetTextColor(color tcell.Color) *Modal {
 	m.textColor = color
+	m.overrides.set(&m.textColor)
 	return m
 }
 
 // SetButtonBackgroundColor sets the background color of the buttons.
 func (m *Modal) SetButtonBackgroundColor(color tcell.Color) *Modal {
 	m.form.SetButtonBackgroundColor(color)
+	m.overrides.set(&m.form.buttonBackgroundColor)
 	return m
 }
 
 // SetButtonTextColor sets the color of the button texts.
 func (m *Modal) SetButtonTextColor(color tcell.Color) *Modal {
 	m.form.SetButtonTextColor(color)
+	m.overrides.set(&m.form.buttonTextColor)
 	return m
 }
 
@@ -96,6 +111,46 @@ func (m *Modal) SetText(text string) *Modal {
 	return m
 }
 
+// SetContent sets a primitive (e.g. a Form or a List) which is shown instead of
+// the message text, above the buttons. The modal is sized to the content's
+// current dimensions (see SetContentSize() to change them), bounded by the
+// screen size, and remains centered on the screen. The content receives focus
+// when the modal receives focus. The Tab and Backtab keys move the focus
+// between the content and the buttons. If the content is a Form, they do so
+// only when leaving its last or first element, respectively.
+//
+// Provide nil to show the message text again.
+func (m *Modal) SetContent(content Primitive) *Modal {
+	m.content = content
+	if content == nil {
+		m.layout = nil
+		m.frame.SetFramed(m.form)
+		return m
+	}
+	_, _, m.contentWidth, m.contentHeight = content.GetRect()
+	m.layout = NewFlex().
+		SetDirection(FlexRow).
+		SetGap(1).
+		AddItem(content, 0, 1, true).
+		AddItem(m.form, 1, 0, false)
+	m.frame.SetFramed(m.layout)
+	return m
+}
+
+// GetContent returns the primitive set with SetContent() or nil if the message
+// text is shown.
+func (m *Modal) GetContent() Primitive {
+	return m.content
+}
+
+// SetContentSize sets the width and height requested for the content
+// primitive, excluding the modal's border and buttons. The modal will not grow
+// beyond the screen size.
+func (m *Modal) SetContentSize(width, height int) *Modal {
+	m.contentWidth, m.contentHeight = width, height
+	return m
+}
+
 // AddButtons adds buttons to the window. There must be at least one button and
 // a "done" handler so the window can be closed again.
 func (m *Modal) AddButtons(labels []string) *Modal {
@@ -135,12 +190,28 @@ func (m *Modal) SetFocus(index int) *Modal {
 
 // Focus is called when this primitive receives focus.
 func (m *Modal) Focus(delegate func(p Primitive)) {
+	if m.content != nil {
+		delegate(m.content)
+		return
+	}
 	delegate(m.form)
 }
 
 // HasFocus returns whether or not this primitive has focus.
 func (m *Modal) HasFocus() bool {
-	return m.form.HasFocus()
+	return m.frame.HasFocus()
+}
+
+// applyTheme implements the themeable interface. The modal's frame and form
+// are themed separately but their colors which differ from those of other
+// frames and forms are set here.
+func (m *Modal) applyTheme(from, to *Theme) {
+	m.Box.applyTheme(from, to)
+	m.overrides.color(&m.textColor, to.PrimaryTextColor)
+	m.overrides.color(&m.form.backgroundColor, to.ContrastBackgroundColor)
+	m.overrides.color(&m.frame.backgroundColor, to.ContrastBackgroundColor)
+	m.overrides.color(&m.form.buttonBackgroundColor, to.PrimitiveBackgroundColor)
+	m.overrides.color(&m.form.buttonTextColor, to.PrimaryTextColor)
 }
 
 // Draw draws this primitive onto the screen.
@@ -152,6 +223,10 @@ func (m *Modal) Draw(screen tcell.Screen) {
 	}
 	buttonsWidth -= 2
 	screenWidth, screenHeight := screen.Size()
+	if m.content != nil {
+		m.drawContent(screen, buttonsWidth, screenWidth, screenHeight)
+		return
+	}
 	width := screenWidth / 3
 	if width < buttonsWidth {
 		width = buttonsWidth
@@ -185,11 +260,46 @@ func (m *Modal) Draw(screen tcell.Screen) {
 	m.frame.Draw(screen)
 }
 
+// drawContent draws the modal with its content primitive.
+func (m *Modal) drawContent(screen tcell.Screen, buttonsWidth, screenWidth, screenHeight int) {
+	// Content size, bounded by the buttons and the screen. The border and the
+	// padding take two cells on each side, the gap and the buttons two rows.
+	width, height := m.contentWidth, m.contentHeight
+	if width < buttonsWidth {
+		width = buttonsWidth
+	}
+	if width > screenWidth-4 {
+		width = screenWidth - 4
+	}
+	if height > screenHeight-6 {
+		height = screenHeight - 6
+	}
+	if height < 1 {
+		height = 1
+	}
+
+	// Set the modal's position and size.
+	width += 4
+	height += 6
+	x := (screenWidth - width) / 2
+	y := (screenHeight - height) / 2
+	m.SetRect(x, y, width, height)
+
+	// Draw the frame.
+	m.frame.Clear()
+	m.frame.SetRect(x, y, width, height)
+	m.frame.Draw(screen)
+}
+
 // MouseHandler returns the mouse handler for this primitive.
 func (m *Modal) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
 	return m.WrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
-		// Pass mouse events on to the form.
-		consumed, capture = m.form.MouseHandler()(action, event, setFocus)
+		// Pass mouse events on to the form (or the content and the form).
+		if m.content != nil {
+			consumed, capture = m.frame.MouseHandler()(action, event, setFocus)
+		} else {
+			consumed, capture = m.form.MouseHandler()(action, event, setFocus)
+		}
 		if !consumed && action == MouseLeftClick && m.InRect(event.Position()) {
 			setFocus(m)
 			consumed = true
@@ -201,6 +311,9 @@ func (m *Modal) MouseHandler() func(action MouseAction, event *tcell.EventMouse,
 // InputHandler returns the handler for this primitive.
 func (m *Modal) InputHandler() func(event *tcell.EventKey, setFocus func(p Primitive)) {
 	return m.WrapInputHandler(func(event *tcell.EventKey, setFocus func(p Primitive)) {
+		if m.content != nil && m.cycleFocus(event, setFocus) {
+			return
+		}
 		if m.frame.HasFocus() {
 			if handler := m.frame.InputHandler(); handler != nil {
 				handler(event, setFocus)
@@ -209,3 +322,53 @@ func (m *Modal) InputHandler() func(event *tcell.EventKey, setFocus func(p Primi
 		}
 	})
 }
+
+// cycleFocus moves the focus between the content primitive and the buttons if
+// the given key event leaves one of them. Returns whether the focus was moved.
+func (m *Modal) cycleFocus(event *tcell.EventKey, setFocus func(p Primitive)) bool {
+	key := event.Key()
+	if key != tcell.KeyTab && key != tcell.KeyBacktab {
+		return false
+	}
+	// Disabled buttons are skipped.
+	first, last := -1, -1
+	for index, button := range m.form.buttons {
+		if !button.IsDisabled() {
+			if first < 0 {
+				first = index
+			}
+			last = index
+		}
+	}
+	if m.content.HasFocus() {
+		if form, ok := m.content.(*Form); ok {
+			index, last := form.focusIndex(), len(form.items)+len(form.buttons)-1
+			if key == tcell.KeyTab && index != last || key == tcell.KeyBacktab && index != 0 {
+				return false
+			}
+		}
+		if first < 0 {
+			return false
+		}
+		if key == tcell.KeyTab {
+			m.form.SetFocus(first)
+		} else {
+			m.form.SetFocus(last)
+		}
+		setFocus(m.form)
+		return true
+	}
+	index := m.form.focusIndex() - len(m.form.items)
+	if key == tcell.KeyTab && index == last || key == tcell.KeyBacktab && index == first {
+		if form, ok := m.content.(*Form); ok {
+			if key == tcell.KeyTab {
+				form.SetFocus(0)
+			} else {
+				form.SetFocus(len(form.items) + len(form.buttons) - 1)
+			}
+		}
+		setFocus(m.content)
+		return true
+	}
+	return false
+}
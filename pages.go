@@ -1,9 +1,15 @@
 package tview
 
 import (
+	"time"
+
 	"github.com/gdamore/tcell/v2"
 )
 
+// pagesTransitionDuration is the default duration of page transitions, see
+// Pages.SetTransitionDuration().
+const pagesTransitionDuration = 250 * time.Millisecond
+
 // page represents one page of a Pages object.
 type page struct {
 	Name    string    // The page's name.
@@ -18,6 +24,11 @@ type Page = page
 // overlapping or not. It is often used as the application's root primitive. It
 // allows to easily switch the visibility of the contained primitives.
 //
+// Pages can also be shown as modals with ShowModal(). Modal pages are stacked
+// on top of all other pages, dim the pages beneath them (see
+// SetModalOverlayStyle()), and are the only pages receiving key and mouse
+// events until they are dismissed with DismissModal().
+//
 // See https://github.com/rivo/tview/wiki/Pages for an example.
 type Pages struct {
 	*Box
@@ -32,12 +43,31 @@ type Pages struct {
 	// An optional handler which is called whenever the visibility or the order of
 	// pages changes.
 	changed func()
+
+	// An optional function which draws the transition between two pages when
+	// SwitchToPage() is called and the duration of such a transition.
+	transition         func(from, to Primitive, screen tcell.Screen, progress float64)
+	transitionDuration time.Duration
+
+	// The pages of the currently running transition (nil if there is none) and
+	// the time it started.
+	transitionFrom, transitionTo *page
+	transitionStart              time.Time
+
+	// The modal pages, from bottom to top.
+	modals []*page
+
+	// The style whose colors the content beneath a modal page is blended
+	// with and whose attributes are added to it.
+	modalStyle tcell.Style
 }
 
 // NewPages returns a new Pages object.
 func NewPages() *Pages {
 	p := &Pages{
-		Box: NewBox(),
+		Box:                NewBox(),
+		transitionDuration: pagesTransitionDuration,
+		modalStyle:         tcell.StyleDefault.Foreground(tcell.ColorBlack).Background(tcell.ColorBlack),
 	}
 	return p
 }
@@ -49,6 +79,155 @@ func (p *Pages) SetChangedFunc(handler func()) *Pages {
 	return p
 }
 
+// SetTransition sets a function which animates the switch between two pages
+// when SwitchToPage() is called. For the duration set with
+// SetTransitionDuration(), instead of drawing its visible pages, the Pages
+// primitive calls the function with the previously front-most page, the new
+// front-most page, and a progress value running from 0 to 1. The function is
+// expected to draw both primitives onto the screen, e.g. with
+// [PagesSlideTransition].
+//
+// The application draws the frames of the transition as long as the Pages
+// primitive is its root or contained in it through the package's container
+// primitives. Otherwise, the transition only advances when the pages are
+// redrawn for other reasons. Set the function to nil to switch pages without a
+// transition (the default).
+func (p *Pages) SetTransition(transition func(from, to Primitive, screen tcell.Screen, progress float64)) *Pages {
+	p.transition = transition
+	if transition == nil {
+		p.transitionFrom, p.transitionTo = nil, nil
+	}
+	return p
+}
+
+// SetTransitionDuration sets the duration of the transition set with
+// SetTransition(). A duration of 0 or less switches pages without a
+// transition. The default is 250 milliseconds.
+func (p *Pages) SetTransitionDuration(duration time.Duration) *Pages {
+	p.transitionDuration = duration
+	return p
+}
+
+// startTransition starts a transition from one page to another.
+func (p *Pages) startTransition(from, to *page) {
+	if p.transition == nil || p.transitionDuration <= 0 || from == nil || to == nil || from == to {
+		return
+	}
+	p.transitionFrom, p.transitionTo = from, to
+	p.transitionStart = time.Now()
+}
+
+// animating returns whether a page transition is running and the pages need
+// to be redrawn.
+func (p *Pages) animating() bool {
+	return p.transitionFrom != nil && p.transition != nil
+}
+
+// SetModalOverlayStyle sets the style used to dim the content beneath modal
+// pages (see ShowModal()). The foreground and background colors of that content
+// are blended halfway towards the style's foreground and background colors
+// respectively, and the style's attributes are added. A color of
+// tcell.ColorDefault leaves the corresponding colors unchanged. As every modal
+// page applies the overlay again, stacked modals dim cumulatively.
+//
+// The default blends all colors with black.
+func (p *Pages) SetModalOverlayStyle(style tcell.Style) *Pages {
+	p.modalStyle = style
+	return p
+}
+
+// ShowModal shows the page with the given name as a modal on top of all other
+// pages, including other modal pages. The pages beneath it are dimmed and don't
+// receive any key or mouse events until the modal page is dismissed with
+// DismissModal(). Nothing happens if there is no page with the given name.
+func (p *Pages) ShowModal(name string) *Pages {
+	modal := p.GetPage(name)
+	if modal == nil {
+		return p
+	}
+	p.removeModal(modal)
+	p.modals = append(p.modals, modal)
+	modal.Visible = true
+	if modal.Page != nil {
+		modal.Page.Shown(p)
+	}
+	if p.changed != nil {
+		p.changed()
+	}
+	if p.HasFocus() {
+		p.Focus(p.setFocus)
+	}
+	return p
+}
+
+// DismissModal hides the top-most modal page shown with ShowModal(). The modal
+// page beneath it, if any, then receives events again, or all pages do if
+// there are no more modal pages.
+func (p *Pages) DismissModal() *Pages {
+	if len(p.modals) == 0 {
+		return p
+	}
+	modal := p.modals[len(p.modals)-1]
+	p.modals = p.modals[:len(p.modals)-1]
+	modal.Visible = false
+	if modal.Page != nil {
+		modal.Page.Hidden(p)
+	}
+	if p.changed != nil {
+		p.changed()
+	}
+	if p.HasFocus() {
+		p.Focus(p.setFocus)
+	}
+	return p
+}
+
+// HasModal returns whether a modal page is shown (see ShowModal()).
+func (p *Pages) HasModal() bool {
+	return len(p.modals) > 0
+}
+
+// removeModal removes the given page from the modal stack, if it is on it.
+func (p *Pages) removeModal(pg *page) {
+	for index, modal := range p.modals {
+		if modal == pg {
+			p.modals = append(p.modals[:index], p.modals[index+1:]...)
+			return
+		}
+	}
+}
+
+// isModal returns whether the given page is on the modal stack.
+func (p *Pages) isModal(pg *page) bool {
+	for _, modal := range p.modals {
+		if modal == pg {
+			return true
+		}
+	}
+	return false
+}
+
+// dim blends the content within the given rectangle with the modal overlay
+// style.
+func (p *Pages) dim(screen tcell.Screen, x, y, width, height int) {
+	for row := y; row < y+height; row++ {
+		for column := x; column < x+width; column++ {
+			m, c, style, _ := screen.GetContent(column, row)
+			screen.SetContent(column, row, m, c, blendStyle(style, p.modalStyle))
+		}
+	}
+}
+
+// frontPage returns the front-most visible page or nil if there is none.
+func (p *Pages) frontPage() *page {
+	for index := len(p.pages) - 1; index >= 0; index-- {
+		if p.pages[index].Visible {
+			return p.pages[index]
+		}
+	}
+	return nil
+}
+
 // GetPageCount returns the number of pages currently stored in this object.
 func (p *Pages) ClearPages() *Pages {
   p.pages = make([]*page, 0)
@@ -87,6 +266,7 @@ func (p *Pages) Addpage(pg *page) *Pages {
 	for index, pgs := range p.pages {
 		if pg.Name == pgs.Name {
 			p.pages = append(p.pages[:index], p.pages[index+1:]...)
+			p.removeModal(pgs)
 			break
 		}
 	}
@@ -126,6 +306,7 @@ func (p *Pages) RemovePage(name string) *Pages {
 		if page.Name == name {
 			isVisible = page.Visible
 			p.pages = append(p.pages[:index], p.pages[index+1:]...)
+			p.removeModal(page)
       if page.Page != nil {
         page.Page.Changed(page, p, Removed)
       }
@@ -198,6 +379,7 @@ func (p *Pages) HidePage(name string) *Pages {
 	for _, page := range p.pages {
 		if page.Name == name {
 			page.Visible = false
+			p.removeModal(page)
       if page.Page != nil {
         page.Page.Hidden(p)
       }
@@ -215,7 +397,11 @@ func (p *Pages) HidePage(name string) *Pages {
 
 // SwitchToPage sets a page's visibility to "true" and all other pages'
 // visibility to "false".
+//
+// If a transition was set with SetTransition(), it is started when the
+// front-most page changes.
 func (p *Pages) SwitchToPage(name string) *Pages {
+	from := p.frontPage()
 	for _, page := range p.pages {
 		if page.Name == name {
 			page.Visible = true
@@ -226,6 +412,7 @@ func (p *Pages) SwitchToPage(name string) *Pages {
 			page.Visible = false
 		}
 	}
+	p.startTransition(from, p.frontPage())
 	if p.changed != nil {
 		p.changed()
 	}
@@ -318,6 +505,10 @@ func (p *Pages) Focus(delegate func(p Primitive)) {
       topPage = page
 		}
 	}
+	if len(p.modals) > 0 {
+		topPage = p.modals[len(p.modals)-1]
+		topItem = topPage.Item
+	}
 	if topItem != nil {
 		delegate(topItem)
 	} else {
@@ -331,12 +522,29 @@ func (p *Pages) Focus(delegate func(p Primitive)) {
 // Draw draws this primitive onto the screen.
 func (p *Pages) Draw(screen tcell.Screen) {
 	p.Box.DrawForSubclass(screen, p)
+
+	// Draw the current frame of a running transition.
+	if p.transitionFrom != nil && p.transition != nil {
+		progress := float64(time.Since(p.transitionStart)) / float64(p.transitionDuration)
+		if progress < 1 {
+			x, y, width, height := p.GetInnerRect()
+			for _, page := range []*page{p.transitionFrom, p.transitionTo} {
+				if page.Resize {
+					page.Item.SetRect(x, y, width, height)
+				}
+			}
+			p.transition(p.transitionFrom.Item, p.transitionTo.Item, screen, progress)
+			return
+		}
+		p.transitionFrom, p.transitionTo = nil, nil
+	}
+
+	x, y, width, height := p.GetInnerRect()
 	for _, page := range p.pages {
-		if !page.Visible {
+		if !page.Visible || p.isModal(page) {
 			continue
 		}
 		if page.Resize {
-			x, y, width, height := p.GetInnerRect()
 			page.Item.SetRect(x, y, width, height)
 		}
 		page.Item.Draw(screen)
@@ -344,6 +552,15 @@ func (p *Pages) Draw(screen tcell.Screen) {
       //   page.Page.Changed(page, p, Drawn)
       // }
 	}
+
+	// Draw the modal pages on top, each dimming everything beneath it.
+	for _, page := range p.modals {
+		p.dim(screen, x, y, width, height)
+		if page.Resize {
+			page.Item.SetRect(x, y, width, height)
+		}
+		page.Item.Draw(screen)
+	}
 }
 
 // MouseHandler returns the mouse handler for this primitive.
@@ -353,6 +570,13 @@ func (p *Pages) MouseHandler() func(action MouseAction, event *tcell.EventMouse,
 			return false, nil
 		}
 
+		// Only the top-most modal page receives mouse events. Events outside of
+		// it are swallowed.
+		if len(p.modals) > 0 {
+			_, capture = p.modals[len(p.modals)-1].Item.MouseHandler()(action, event, setFocus)
+			return true, capture
+		}
+
 		// Pass mouse events along to the last visible page item that takes it.
 		for index := len(p.pages) - 1; index >= 0; index-- {
 			page := p.pages[index]
@@ -371,6 +595,19 @@ func (p *Pages) MouseHandler() func(action MouseAction, event *tcell.EventMouse,
 // InputHandler returns the handler for this primitive.
 func (p *Pages) InputHandler() func(event *tcell.EventKey, setFocus func(p Primitive)) {
 	return p.WrapInputHandler(func(event *tcell.EventKey, setFocus func(p Primitive)) {
+		// Only the top-most modal page receives key events.
+		if len(p.modals) > 0 {
+			modal := p.modals[len(p.modals)-1].Item
+			if !modal.HasFocus() {
+				setFocus(modal)
+				return
+			}
+			if handler := modal.InputHandler(); handler != nil {
+				handler(event, setFocus)
+			}
+			return
+		}
+
 		for _, page := range p.pages {
 			if page.Item.HasFocus() {
 				if handler := page.Item.InputHandler(); handler != nil {
@@ -381,3 +618,56 @@ func (p *Pages) InputHandler() func(event *tcell.EventKey, setFocus func(p Primi
 		}
 	})
 }
+
+// PagesSlideTransition is a page transition for Pages.SetTransition() which
+// slides the new page in from the right while the previous page moves out to
+// the left. Both primitives are clipped to the area of the previous page.
+func PagesSlideTransition(from, to Primitive, screen tcell.Screen, progress float64) {
+	x, y, width, height := from.GetRect()
+	toX, toY, toWidth, toHeight := to.GetRect()
+	defer to.SetRect(toX, toY, toWidth, toHeight)
+
+	offset := int(float64(width) * progress)
+	from.Draw(&shiftedScreen{Screen: screen, dx: -offset, x: x, y: y, width: width, height: height})
+	to.SetRect(x, y, width, height)
+	to.Draw(&shiftedScreen{Screen: screen, dx: width - offset, x: x, y: y, width: width, height: height})
+}
+
+// shiftedScreen is a screen which moves all content horizontally by dx cells
+// and then ignores all content outside of a rectangle.
+type shiftedScreen struct {
+	tcell.Screen
+	dx                  int
+	x, y, width, height int
+}
+
+// SetContent sets the contents of the shifted cell if it lies within the
+// rectangle.
+func (s *shiftedScreen) SetContent(x, y int, primary rune, combining []rune, style tcell.Style) {
+	x += s.dx
+	if x < s.x || x >= s.x+s.width || y < s.y || y >= s.y+s.height {
+		return
+	}
+	s.Screen.SetContent(x, y, primary, combining, style)
+}
+
+// SetCell sets the contents of the shifted cell if it lies within the
+// rectangle.
+func (s *shiftedScreen) SetCell(x, y int, style tcell.Style, ch ...rune) {
+	x += s.dx
+	if x < s.x || x >= s.x+s.width || y < s.y || y >= s.y+s.height {
+		return
+	}
+	s.Screen.SetCell(x, y, style, ch...)
+}
+
+// GetContent returns the contents of the shifted cell.
+func (s *shiftedScreen) GetContent(x, y int) (primary rune, combining []rune, style tcell.Style, width int) {
+	return s.Screen.GetContent(x+s.dx, y)
+}
+
+// ShowCursor hides the cursor as it cannot be placed reliably while content
+// is moving.
+func (s *shiftedScreen) ShowCursor(x, y int) {
+	s.Screen.HideCursor()
+}
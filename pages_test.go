@@ -0,0 +1,77 @@
+package tview
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestPagesTransition(t *testing.T) {
+	var (
+		mutex      sync.Mutex
+		progresses []float64
+	)
+	pages := NewPages().
+		AddPage("first", NewTextView().SetText("first"), true, true).
+		AddPage("second", NewTextView().SetText("second"), true, false).
+		SetTransitionDuration(100 * time.Millisecond).
+		SetTransition(func(from, to Primitive, screen tcell.Screen, progress float64) {
+			mutex.Lock()
+			progresses = append(progresses, progress)
+			mutex.Unlock()
+			PagesSlideTransition(from, to, screen, progress)
+		})
+	app := NewApplication().SetRoot(pages, true)
+	screen := startApp(t, app, 20, 3)
+
+	// The application draws the frames until the transition has finished. The
+	// transition is over once the final frame was drawn.
+	app.QueueUpdateDraw(func() {
+		pages.SwitchToPage("second")
+	})
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		done := make(chan bool)
+		app.QueueUpdate(func() {
+			done <- !pages.animating()
+		})
+		if <-done {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("transition did not finish")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	if len(progresses) < 2 {
+		t.Fatalf("transition was drawn %d times, expected several frames", len(progresses))
+	}
+	for index, progress := range progresses {
+		if progress < 0 || progress >= 1 || index > 0 && progress < progresses[index-1] {
+			t.Errorf("transition progresses are %v, expected ascending values in [0,1)", progresses)
+			break
+		}
+	}
+	if line := screenLine(screen, 0); !strings.HasPrefix(line, "second") {
+		t.Errorf("row 0 is %q after the transition, expected the second page", line)
+	}
+}
+
+func TestPagesSlideTransition(t *testing.T) {
+	screen := newTestScreen(t, 10, 1)
+	from, to := NewTextView().SetText("aaaaaaaaaa"), NewTextView().SetText("bbbbbbbbbb")
+	from.SetRect(0, 0, 10, 1)
+	to.SetRect(0, 0, 10, 1)
+
+	// Halfway through, the new page has moved in halfway from the right.
+	PagesSlideTransition(from, to, screen, 0.5)
+	if line := screenLine(screen, 0); line != "aaaaabbbbb" {
+		t.Errorf("halfway slide is %q, expected %q", line, "aaaaabbbbb")
+	}
+}
@@ -0,0 +1,220 @@
+package tview
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// ProgressBarMarqueeCycle is the time it takes the moving segment of an
+// indeterminate ProgressBar to travel across the bar once. With a value of 0
+// or less, the segment stays at the start of the bar.
+var ProgressBarMarqueeCycle = 2 * time.Second
+
+// ProgressBar is a primitive which indicates the progress of an operation. In
+// determinate mode, the bar is filled according to the current progress value
+// (see SetProgress() and SetMax()). In indeterminate mode (see
+// SetIndeterminate()), a short segment travels across the bar to indicate that
+// an operation of unknown length is running.
+//
+// The bar is drawn horizontally from left to right by default, or from bottom
+// to top if it is vertical (see SetVertical()). It fills the box's entire inner
+// rectangle.
+//
+// The progress bar does not redraw itself. The position of the indeterminate
+// segment is derived from the current time whenever the bar is drawn so it
+// moves with every redraw, e.g. when using Application.SetTickInterval().
+type ProgressBar struct {
+	*Box
+
+	// The current progress, between 0 and max.
+	progress float64
+
+	// The progress value of a complete operation.
+	max float64
+
+	// Whether or not the progress is unknown.
+	indeterminate bool
+
+	// Whether or not the bar is drawn from bottom to top.
+	vertical bool
+
+	// Whether or not the progress is shown as a percentage label.
+	showPercentage bool
+
+	// The runes used for the filled and the empty part of the bar.
+	filledRune, emptyRune rune
+
+	// The styles of the filled and the empty part of the bar.
+	filledStyle, emptyStyle tcell.Style
+
+	// The style of the percentage label.
+	labelStyle tcell.Style
+}
+
+// NewProgressBar returns a new, empty, horizontal progress bar with a maximum
+// progress value of 1.
+func NewProgressBar() *ProgressBar {
+	return &ProgressBar{
+		Box:         NewBox(),
+		max:         1,
+		filledRune:  '█',
+		emptyRune:   '░',
+		filledStyle: tcell.StyleDefault.Foreground(Styles.PrimaryTextColor).Background(Styles.PrimitiveBackgroundColor),
+		emptyStyle:  tcell.StyleDefault.Foreground(Styles.TertiaryTextColor).Background(Styles.PrimitiveBackgroundColor),
+		labelStyle:  tcell.StyleDefault.Foreground(Styles.InverseTextColor).Background(Styles.PrimaryTextColor),
+	}
+}
+
+// SetProgress sets the current progress, a value between 0 and the maximum
+// value (see SetMax()). Values outside of this range are clamped.
+func (p *ProgressBar) SetProgress(progress float64) *ProgressBar {
+	p.progress = progress
+	return p
+}
+
+// GetProgress returns the current progress.
+func (p *ProgressBar) GetProgress() float64 {
+	return p.progress
+}
+
+// SetMax sets the progress value which marks the operation as complete. The
+// default is 1. Values of 0 or less are ignored.
+func (p *ProgressBar) SetMax(max float64) *ProgressBar {
+	if max > 0 {
+		p.max = max
+	}
+	return p
+}
+
+// GetMax returns the progress value which marks the operation as complete.
+func (p *ProgressBar) GetMax() float64 {
+	return p.max
+}
+
+// SetIndeterminate sets whether the progress is unknown, in which case a short
+// segment travels across the bar instead of the bar being filled.
+func (p *ProgressBar) SetIndeterminate(indeterminate bool) *ProgressBar {
+	p.indeterminate = indeterminate
+	return p
+}
+
+// SetVertical sets whether the bar is drawn from bottom to top instead of from
+// left to right.
+func (p *ProgressBar) SetVertical(vertical bool) *ProgressBar {
+	p.vertical = vertical
+	return p
+}
+
+// SetShowPercentage sets whether the progress is shown as a percentage label
+// in the middle of a horizontal bar. The label is never shown in indeterminate
+// mode or for vertical bars.
+func (p *ProgressBar) SetShowPercentage(show bool) *ProgressBar {
+	p.showPercentage = show
+	return p
+}
+
+// SetFilledRune sets the rune used to draw the filled part of the bar.
+func (p *ProgressBar) SetFilledRune(r rune) *ProgressBar {
+	p.filledRune = r
+	return p
+}
+
+// SetEmptyRune sets the rune used to draw the empty part of the bar.
+func (p *ProgressBar) SetEmptyRune(r rune) *ProgressBar {
+	p.emptyRune = r
+	return p
+}
+
+// SetFilledStyle sets the style of the filled part of the bar.
+func (p *ProgressBar) SetFilledStyle(style tcell.Style) *ProgressBar {
+	p.filledStyle = style
+	p.overrides.set(&p.filledStyle)
+	return p
+}
+
+// SetEmptyStyle sets the style of the empty part of the bar.
+func (p *ProgressBar) SetEmptyStyle(style tcell.Style) *ProgressBar {
+	p.emptyStyle = style
+	p.overrides.set(&p.emptyStyle)
+	return p
+}
+
+// SetLabelStyle sets the style of the percentage label.
+func (p *ProgressBar) SetLabelStyle(style tcell.Style) *ProgressBar {
+	p.labelStyle = style
+	p.overrides.set(&p.labelStyle)
+	return p
+}
+
+// fraction returns the current progress as a value between 0 and 1.
+func (p *ProgressBar) fraction() float64 {
+	fraction := p.progress / p.max
+	if fraction < 0 {
+		return 0
+	}
+	if fraction > 1 {
+		return 1
+	}
+	return fraction
+}
+
+// applyTheme implements the themeable interface.
+func (p *ProgressBar) applyTheme(from, to *Theme) {
+	p.Box.applyTheme(from, to)
+	p.overrides.style(&p.filledStyle, tcell.StyleDefault.Foreground(to.PrimaryTextColor).Background(to.PrimitiveBackgroundColor))
+	p.overrides.style(&p.emptyStyle, tcell.StyleDefault.Foreground(to.TertiaryTextColor).Background(to.PrimitiveBackgroundColor))
+	p.overrides.style(&p.labelStyle, tcell.StyleDefault.Foreground(to.InverseTextColor).Background(to.PrimaryTextColor))
+}
+
+// Draw draws this primitive onto the screen.
+func (p *ProgressBar) Draw(screen tcell.Screen) {
+	p.Box.DrawForSubclass(screen, p)
+	x, y, width, height := p.GetInnerRect()
+	if width <= 0 || height <= 0 {
+		return
+	}
+
+	// Determine the filled range along the bar.
+	length := width
+	if p.vertical {
+		length = height
+	}
+	from, to := 0, int(p.fraction()*float64(length)+0.5)
+	if p.indeterminate {
+		segment := length / 5
+		if segment < 1 {
+			segment = 1
+		}
+		from = 0
+		if cycle := ProgressBarMarqueeCycle; cycle > 0 {
+			elapsed := time.Duration(time.Now().UnixNano()) % cycle
+			from = int(float64(length+segment)*float64(elapsed)/float64(cycle)) - segment
+		}
+		to = from + segment
+	}
+
+	// Draw the bar.
+	for position := 0; position < length; position++ {
+		r, style := p.emptyRune, p.emptyStyle
+		if position >= from && position < to {
+			r, style = p.filledRune, p.filledStyle
+		}
+		if p.vertical {
+			for column := x; column < x+width; column++ {
+				screen.SetContent(column, y+height-1-position, r, nil, style)
+			}
+		} else {
+			for row := y; row < y+height; row++ {
+				screen.SetContent(x+position, row, r, nil, style)
+			}
+		}
+	}
+
+	// Draw the percentage label.
+	if p.showPercentage && !p.indeterminate && !p.vertical {
+		label := fmt.Sprintf("%d%%", int(p.fraction()*100))
+		printWithStyle(screen, label, x, y+height/2, 0, width, AlignCenter, p.labelStyle, false)
+	}
+}
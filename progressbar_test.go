@@ -0,0 +1,27 @@
+package tview
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestProgressBarMarqueeWithoutCycle(t *testing.T) {
+	defer func(cycle time.Duration) {
+		ProgressBarMarqueeCycle = cycle
+	}(ProgressBarMarqueeCycle)
+
+	screen := newTestScreen(t, 10, 1)
+	bar := NewProgressBar().SetIndeterminate(true)
+	bar.SetRect(0, 0, 10, 1)
+
+	// A cycle of 0 or less keeps the segment at the start instead of panicking.
+	for _, cycle := range []time.Duration{0, -time.Second} {
+		ProgressBarMarqueeCycle = cycle
+		screen.Clear()
+		bar.Draw(screen)
+		if line := screenLine(screen, 0); !strings.HasPrefix(line, "██") || strings.Count(line, "█") != 2 {
+			t.Errorf("bar is %q with a cycle of %s, expected the segment at the start", line, cycle)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package tview
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// ErrApplicationRunning is returned by Application.RunSimulation() if the
+// application already has a screen.
+var ErrApplicationRunning = errors.New("application already has a screen")
+
+// simulationBarrier is posted to a simulation screen by RunSimulation(). The
+// event loop closes it when it is reached, i.e. after all events posted before
+// it were handled and drawn.
+type simulationBarrier chan struct{}
+
+// RunSimulation runs the application on a tcell simulation screen of the given
+// size instead of a terminal. This is meant for testing primitives and
+// applications without a terminal.
+//
+// Once the application has drawn its root primitive for the first time, the
+// script function is called with the simulation screen. It may inject events
+// (e.g. with InjectKey() or InjectMouse()) and inspect the screen's contents
+// (with GetContents()). Note that injected events are handled asynchronously
+// by the application's event loop. Events are dropped if more than about ten
+// are pending, so scripts injecting many events should pause between them.
+//
+// When the script returns, RunSimulation waits until all injected events have
+// been handled, stops the application, and returns a copy of the final screen
+// contents, e.g. for a comparison with a golden file. The cells are ordered row
+// by row, with width cells per row. The contents are nil if the application
+// stopped before the script returned.
+//
+// The application must not have been run before and must not have a screen
+// (see SetScreen()). RunSimulation blocks until the application has stopped.
+func (a *Application) RunSimulation(width, height int, script func(sim tcell.SimulationScreen)) ([]tcell.SimCell, error) {
+	a.RLock()
+	hasScreen, enableMouse := a.screen != nil, a.enableMouse
+	a.RUnlock()
+	if hasScreen {
+		return nil, ErrApplicationRunning
+	}
+
+	// Set up the screen.
+	sim := tcell.NewSimulationScreen("UTF-8")
+	if err := sim.Init(); err != nil {
+		return nil, err
+	}
+	sim.SetSize(width, height)
+	if enableMouse {
+		sim.EnableMouse()
+	}
+	a.SetScreen(sim)
+
+	// Run the application.
+	runErr := make(chan error, 1)
+	go func() {
+		runErr <- a.Run()
+	}()
+
+	// settle waits until all events posted so far were handled. It returns
+	// false if the application stopped in the meantime. If the script replaced
+	// the screen, the events posted to the new screen are waited for.
+	settle := func() bool {
+		a.RLock()
+		screen := a.screen
+		a.RUnlock()
+		if screen == nil {
+			return false
+		}
+		barrier := make(simulationBarrier)
+		screen.PostEventWait(tcell.NewEventInterrupt(barrier))
+		select {
+		case <-barrier:
+			return true
+		case err := <-runErr:
+			runErr <- err
+			return false
+		}
+	}
+
+	// Execute the script and take a snapshot of the result.
+	var contents []tcell.SimCell
+	if settle() {
+		if script != nil {
+			script(sim)
+		}
+		if settle() {
+			cells, _, _ := sim.GetContents()
+			contents = make([]tcell.SimCell, len(cells))
+			copy(contents, cells)
+		}
+	}
+
+	// Tear down.
+	a.Stop()
+	return contents, <-runErr
+}
+
+// Snapshot returns the current contents of the application's screen, one
+// slice of runes and one slice of styles per row and one element per cell.
+// Cells covered by the right half of a wide character contain whatever was
+// last drawn there. Combining characters are not included, see
+// SnapshotString() for them. Both return values are nil if the application
+// has no screen.
+//
+// The screen contents change with every redraw. To read them in a consistent
+// state, call this function from within a queued update (see QueueUpdate()).
+func (a *Application) Snapshot() ([][]rune, [][]tcell.Style) {
+	a.RLock()
+	screen := a.screen
+	a.RUnlock()
+	if screen == nil {
+		return nil, nil
+	}
+
+	width, height := screen.Size()
+	runes := make([][]rune, height)
+	styles := make([][]tcell.Style, height)
+	for y := 0; y < height; y++ {
+		runes[y] = make([]rune, width)
+		styles[y] = make([]tcell.Style, width)
+		for x := 0; x < width; x++ {
+			runes[y][x], _, styles[y][x], _ = screen.GetContent(x, y)
+		}
+	}
+	return runes, styles
+}
+
+// SnapshotString returns the current contents of the application's screen as
+// text, one line per row, separated by newlines. Styles are ignored. Wide
+// characters are included once, the cells they cover are skipped. Trailing
+// spaces are not removed so that all lines have the same width in cells. An
+// empty string is returned if the application has no screen.
+//
+// See Snapshot() for when to call this function.
+func (a *Application) SnapshotString() string {
+	a.RLock()
+	screen := a.screen
+	a.RUnlock()
+	if screen == nil {
+		return ""
+	}
+
+	var b strings.Builder
+	width, height := screen.Size()
+	for y := 0; y < height; y++ {
+		if y > 0 {
+			b.WriteByte('\n')
+		}
+		for x := 0; x < width; {
+			mainc, combc, _, cellWidth := screen.GetContent(x, y)
+			if mainc == 0 {
+				mainc = ' '
+			}
+			b.WriteRune(mainc)
+			for _, r := range combc {
+				b.WriteRune(r)
+			}
+			if cellWidth < 1 {
+				cellWidth = 1
+			}
+			x += cellWidth
+		}
+	}
+	return b.String()
+}
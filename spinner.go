@@ -0,0 +1,223 @@
+package tview
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// Frame sets for Spinner.SetFrames().
+var (
+	SpinnerDots    = []rune("⠋⠙⠹⠸⠼⠴⠦⠧⠇⠏")
+	SpinnerLine    = []rune(`|/-\`)
+	SpinnerBraille = []rune("⣾⣽⣻⢿⡿⣟⣯⣷")
+)
+
+// Spinner is a small animation indicating that an operation is in progress,
+// optionally followed by a label, e.g. "Loading…". It cycles through a set of
+// frames (see SetFrames()) while it is running (see Start() and Stop()).
+//
+// The animation is driven by Application.QueueUpdateDraw(). It pauses while
+// the spinner is not drawn, e.g. because it is hidden or on a page which is not
+// shown, and resumes when it is drawn again. It ends when the application
+// stops.
+//
+// To show the spinner inside other text instead of as a primitive of its own,
+// use Frame() to get the current frame while the spinner is running.
+type Spinner struct {
+	*Box
+
+	// The frames of the animation.
+	frames []rune
+
+	// The index of the current frame.
+	frame int
+
+	// The time between two frames.
+	interval time.Duration
+
+	// The text shown after the current frame.
+	label string
+
+	// The style of the frames and of the label.
+	style tcell.Style
+
+	// Guards the fields below which may be accessed from other goroutines.
+	mutex sync.Mutex
+
+	// The application which redraws the spinner. nil if it is not running.
+	app *Application
+
+	// Closed to end the animation goroutine. nil if there is none.
+	stop chan struct{}
+
+	// Whether the spinner was drawn since the last frame.
+	drawn bool
+}
+
+// NewSpinner returns a new, stopped spinner using the SpinnerDots frames at ten
+// frames per second.
+func NewSpinner() *Spinner {
+	return &Spinner{
+		Box:      NewBox(),
+		frames:   SpinnerDots,
+		interval: 100 * time.Millisecond,
+		style:    tcell.StyleDefault.Foreground(Styles.PrimaryTextColor).Background(Styles.PrimitiveBackgroundColor),
+	}
+}
+
+// SetFrames sets the frames of the animation, e.g. SpinnerDots (the default),
+// SpinnerLine, or SpinnerBraille. An empty slice is ignored.
+func (s *Spinner) SetFrames(frames []rune) *Spinner {
+	if len(frames) > 0 {
+		s.frames = frames
+		s.frame = 0
+	}
+	return s
+}
+
+// SetInterval sets the time between two frames of the animation. The default
+// is 100 milliseconds. Values of 0 or less are ignored.
+func (s *Spinner) SetInterval(interval time.Duration) *Spinner {
+	if interval <= 0 {
+		return s
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.interval = interval
+	if s.stop != nil {
+		// Restart with the new interval.
+		close(s.stop)
+		s.animate()
+	}
+	return s
+}
+
+// SetLabel sets the text shown after the animation, separated by a space.
+func (s *Spinner) SetLabel(label string) *Spinner {
+	s.label = label
+	return s
+}
+
+// GetLabel returns the text shown after the animation.
+func (s *Spinner) GetLabel() string {
+	return s.label
+}
+
+// SetStyle sets the style of the animation and of the label.
+func (s *Spinner) SetStyle(style tcell.Style) *Spinner {
+	s.style = style
+	s.overrides.set(&s.style)
+	return s
+}
+
+// Start starts the animation which causes the given application to redraw
+// regularly. Nothing happens if the spinner is already running.
+func (s *Spinner) Start(app *Application) *Spinner {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.app != nil {
+		return s
+	}
+	s.app = app
+	s.drawn = true // Show the first frame change even before the first draw.
+	s.animate()
+	return s
+}
+
+// Stop stops the animation. The current frame remains visible.
+func (s *Spinner) Stop() *Spinner {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.app = nil
+	if s.stop != nil {
+		close(s.stop)
+		s.stop = nil
+	}
+	return s
+}
+
+// IsRunning returns whether the animation was started and not stopped yet.
+func (s *Spinner) IsRunning() bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.app != nil
+}
+
+// Frame returns the current frame of the animation, e.g. to include it in the
+// text of another primitive.
+func (s *Spinner) Frame() rune {
+	return s.frames[s.frame%len(s.frames)]
+}
+
+// animate starts the goroutine which advances the animation. The mutex must be
+// locked and the spinner must be running.
+func (s *Spinner) animate() {
+	stop := make(chan struct{})
+	s.stop = stop
+	app, interval := s.app, s.interval
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-app.runContext.Done():
+				return
+			case <-ticker.C:
+				app.QueueUpdateDraw(func() {
+					s.advance(stop)
+				})
+			}
+		}
+	}()
+}
+
+// advance moves to the next frame. If the spinner wasn't drawn since the last
+// frame, the animation goroutine with the given stop channel is ended instead
+// and the animation continues when the spinner is drawn next.
+func (s *Spinner) advance(stop chan struct{}) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.stop != stop {
+		return // This goroutine was already stopped.
+	}
+	if !s.drawn || !s.IsVisible() {
+		close(s.stop)
+		s.stop = nil
+		return
+	}
+	s.drawn = false
+	s.frame = (s.frame + 1) % len(s.frames)
+}
+
+// applyTheme implements the themeable interface.
+func (s *Spinner) applyTheme(from, to *Theme) {
+	s.Box.applyTheme(from, to)
+	s.overrides.style(&s.style, tcell.StyleDefault.Foreground(to.PrimaryTextColor).Background(to.PrimitiveBackgroundColor))
+}
+
+// Draw draws this primitive onto the screen.
+func (s *Spinner) Draw(screen tcell.Screen) {
+	s.Box.DrawForSubclass(screen, s)
+
+	// Resume a paused animation.
+	s.mutex.Lock()
+	s.drawn = true
+	if s.app != nil && s.stop == nil {
+		s.animate()
+	}
+	s.mutex.Unlock()
+
+	x, y, width, height := s.GetInnerRect()
+	if width <= 0 || height <= 0 {
+		return
+	}
+	text := string(s.Frame())
+	if s.label != "" {
+		text += " " + s.label
+	}
+	printWithStyle(screen, text, x, y, 0, width, AlignLeft, s.style, true)
+}
@@ -0,0 +1,350 @@
+package tview
+
+import (
+	"github.com/gdamore/tcell/v2"
+)
+
+// SplitView is a layout container which shows two primitives next to each
+// other (FlexColumn, the default) or one above the other (FlexRow), separated
+// by a divider bar. The user can move the divider by dragging it with the left
+// mouse button or by pressing Alt together with an arrow key while one of the
+// panes has focus.
+//
+// The position of the divider is stored as a ratio of the available space (see
+// SetRatio()) so the layout can be saved and restored and scales with the
+// split view's size. Each pane may have a minimum size (see SetMinSizes()).
+type SplitView struct {
+	*Box
+
+	// The two panes. Each may be nil.
+	first, second Primitive
+
+	// FlexColumn or FlexRow.
+	direction int
+
+	// The share of the available space given to the first pane, between 0
+	// and 1.
+	ratio float64
+
+	// The minimum sizes of the two panes.
+	minFirst, minSecond int
+
+	// The style of the divider bar and the style of the divider bar while it
+	// is being dragged.
+	dividerStyle, dragStyle tcell.Style
+
+	// Whether or not the divider is currently being dragged.
+	dragging bool
+
+	// An optional function which is called when the user moves the divider.
+	changed func(ratio float64)
+}
+
+// NewSplitView returns a new split view with the two given panes, either of
+// which may be nil. The panes are arranged next to each other with the
+// divider in the middle.
+func NewSplitView(first, second Primitive) *SplitView {
+	return &SplitView{
+		Box:          NewBox(),
+		first:        first,
+		second:       second,
+		direction:    FlexColumn,
+		ratio:        0.5,
+		dividerStyle: tcell.StyleDefault.Foreground(Styles.BorderColor).Background(Styles.PrimitiveBackgroundColor),
+		dragStyle:    tcell.StyleDefault.Foreground(Styles.SecondaryTextColor).Background(Styles.PrimitiveBackgroundColor),
+	}
+}
+
+// SetPanes replaces the two panes of the split view. Either may be nil.
+func (s *SplitView) SetPanes(first, second Primitive) *SplitView {
+	s.first, s.second = first, second
+	return s
+}
+
+// GetPanes returns the two panes of the split view.
+func (s *SplitView) GetPanes() (first, second Primitive) {
+	return s.first, s.second
+}
+
+// SetDirection sets how the panes are arranged, one of FlexColumn (next to
+// each other, with a vertical divider) or FlexRow (one above the other, with a
+// horizontal divider).
+func (s *SplitView) SetDirection(direction int) *SplitView {
+	s.direction = direction
+	return s
+}
+
+// SetRatio sets the share of the available space which is given to the first
+// pane, a value between 0 and 1. Values outside of this range are clamped. The
+// minimum pane sizes take precedence over the ratio.
+func (s *SplitView) SetRatio(ratio float64) *SplitView {
+	if ratio < 0 {
+		ratio = 0
+	} else if ratio > 1 {
+		ratio = 1
+	}
+	s.ratio = ratio
+	return s
+}
+
+// GetRatio returns the share of the available space which is given to the
+// first pane, a value between 0 and 1.
+func (s *SplitView) GetRatio() float64 {
+	return s.ratio
+}
+
+// SetMinSizes sets the minimum number of cells of the first and the second
+// pane along the split direction. Negative values are treated as 0. If there
+// is not enough space for both, the first pane's minimum size wins.
+func (s *SplitView) SetMinSizes(first, second int) *SplitView {
+	if first < 0 {
+		first = 0
+	}
+	if second < 0 {
+		second = 0
+	}
+	s.minFirst, s.minSecond = first, second
+	return s
+}
+
+// SetDividerStyle sets the style of the divider bar.
+func (s *SplitView) SetDividerStyle(style tcell.Style) *SplitView {
+	s.dividerStyle = style
+	s.overrides.set(&s.dividerStyle)
+	return s
+}
+
+// SetDragStyle sets the style of the divider bar while it is being dragged.
+func (s *SplitView) SetDragStyle(style tcell.Style) *SplitView {
+	s.dragStyle = style
+	s.overrides.set(&s.dragStyle)
+	return s
+}
+
+// SetChangedFunc sets a handler which is called when the user moves the
+// divider, either with the mouse or with the keyboard. The handler receives
+// the new ratio (see GetRatio()).
+func (s *SplitView) SetChangedFunc(handler func(ratio float64)) *SplitView {
+	s.changed = handler
+	return s
+}
+
+// available returns the number of cells along the split direction which are
+// shared by the two panes, i.e. without the divider.
+func (s *SplitView) available() int {
+	_, _, width, height := s.GetInnerRect()
+	available := width - 1
+	if s.direction == FlexRow {
+		available = height - 1
+	}
+	if available < 0 {
+		return 0
+	}
+	return available
+}
+
+// firstSize returns the size of the first pane along the split direction,
+// given the number of available cells.
+func (s *SplitView) firstSize(available int) int {
+	return s.clampSize(int(s.ratio*float64(available)+0.5), available)
+}
+
+// clampSize limits the given size of the first pane such that both panes
+// receive their minimum sizes, if possible.
+func (s *SplitView) clampSize(size, available int) int {
+	if size > available-s.minSecond {
+		size = available - s.minSecond
+	}
+	if size < s.minFirst {
+		size = s.minFirst
+	}
+	if size > available {
+		size = available
+	}
+	if size < 0 {
+		size = 0
+	}
+	return size
+}
+
+// moveDivider places the divider such that the first pane receives the given
+// number of cells, within the limits of the minimum pane sizes, and notifies
+// the changed handler if the position changed.
+func (s *SplitView) moveDivider(size int) {
+	available := s.available()
+	if available == 0 {
+		return
+	}
+	size = s.clampSize(size, available)
+	if size == s.firstSize(available) {
+		return
+	}
+	s.ratio = float64(size) / float64(available)
+	if s.changed != nil {
+		s.changed(s.ratio)
+	}
+}
+
+// applyTheme implements the themeable interface. The panes are themed
+// separately.
+func (s *SplitView) applyTheme(from, to *Theme) {
+	s.Box.applyTheme(from, to)
+	s.overrides.style(&s.dividerStyle, tcell.StyleDefault.Foreground(to.BorderColor).Background(to.PrimitiveBackgroundColor))
+	s.overrides.style(&s.dragStyle, tcell.StyleDefault.Foreground(to.SecondaryTextColor).Background(to.PrimitiveBackgroundColor))
+}
+
+// Draw draws this primitive onto the screen.
+func (s *SplitView) Draw(screen tcell.Screen) {
+	s.Box.DrawForSubclass(screen, s)
+	x, y, width, height := s.GetInnerRect()
+	if width <= 0 || height <= 0 {
+		return
+	}
+	size := s.firstSize(s.available())
+
+	// Position the panes.
+	if s.direction == FlexRow {
+		if s.first != nil {
+			s.first.SetRect(x, y, width, size)
+		}
+		if s.second != nil {
+			s.second.SetRect(x, y+size+1, width, height-size-1)
+		}
+	} else {
+		if s.first != nil {
+			s.first.SetRect(x, y, size, height)
+		}
+		if s.second != nil {
+			s.second.SetRect(x+size+1, y, width-size-1, height)
+		}
+	}
+
+	// Draw the panes, the focused one last.
+	for _, pane := range []Primitive{s.first, s.second} {
+		if pane != nil && !pane.HasFocus() {
+			pane.Draw(screen)
+		}
+	}
+	for _, pane := range []Primitive{s.first, s.second} {
+		if pane != nil && pane.HasFocus() {
+			pane.Draw(screen)
+		}
+	}
+
+	// Draw the divider.
+	style := s.dividerStyle
+	if s.dragging {
+		style = s.dragStyle
+	}
+	if s.direction == FlexRow {
+		if size < height {
+			for column := x; column < x+width; column++ {
+				screen.SetContent(column, y+size, Borders.Horizontal, nil, style)
+			}
+		}
+	} else if size < width {
+		for row := y; row < y+height; row++ {
+			screen.SetContent(x+size, row, Borders.Vertical, nil, style)
+		}
+	}
+}
+
+// Focus is called when this primitive receives focus.
+func (s *SplitView) Focus(delegate func(p Primitive)) {
+	if s.first != nil {
+		delegate(s.first)
+		return
+	}
+	if s.second != nil {
+		delegate(s.second)
+		return
+	}
+	s.Box.Focus(delegate)
+}
+
+// HasFocus returns whether or not this primitive has focus.
+func (s *SplitView) HasFocus() bool {
+	if s.first != nil && s.first.HasFocus() {
+		return true
+	}
+	if s.second != nil && s.second.HasFocus() {
+		return true
+	}
+	return s.Box.HasFocus()
+}
+
+// MouseHandler returns the mouse handler for this primitive.
+func (s *SplitView) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+	return s.WrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+		x, y, width, height := s.GetInnerRect()
+		mouseX, mouseY := event.Position()
+		position, start := mouseX, x
+		if s.direction == FlexRow {
+			position, start = mouseY, y
+		}
+
+		// Dragging the divider.
+		if s.dragging {
+			switch action {
+			case MouseLeftDrag, MouseMove:
+				s.moveDivider(position - start)
+				return true, s
+			case MouseLeftUp:
+				s.dragging = false
+				return true, nil
+			}
+		}
+
+		if mouseX < x || mouseX >= x+width || mouseY < y || mouseY >= y+height {
+			return false, nil
+		}
+
+		// Grabbing the divider.
+		if action == MouseLeftDown && position-start == s.firstSize(s.available()) {
+			s.dragging = true
+			return true, s
+		}
+
+		// Pass mouse events on to the panes.
+		for _, pane := range []Primitive{s.first, s.second} {
+			if pane == nil {
+				continue
+			}
+			consumed, capture = pane.MouseHandler()(action, event, setFocus)
+			if consumed {
+				return
+			}
+		}
+		return
+	})
+}
+
+// InputHandler returns the handler for this primitive.
+func (s *SplitView) InputHandler() func(event *tcell.EventKey, setFocus func(p Primitive)) {
+	return s.WrapInputHandler(func(event *tcell.EventKey, setFocus func(p Primitive)) {
+		// Moving the divider.
+		if event.Modifiers()&tcell.ModAlt != 0 {
+			size := s.firstSize(s.available())
+			key := event.Key()
+			if s.direction == FlexRow && (key == tcell.KeyUp || key == tcell.KeyDown) ||
+				s.direction != FlexRow && (key == tcell.KeyLeft || key == tcell.KeyRight) {
+				if key == tcell.KeyUp || key == tcell.KeyLeft {
+					s.moveDivider(size - 1)
+				} else {
+					s.moveDivider(size + 1)
+				}
+				return
+			}
+		}
+
+		// Pass key events on to the focused pane.
+		for _, pane := range []Primitive{s.first, s.second} {
+			if pane != nil && pane.HasFocus() {
+				if handler := pane.InputHandler(); handler != nil {
+					handler(event, setFocus)
+				}
+				return
+			}
+		}
+	})
+}
@@ -0,0 +1,242 @@
+package tview
+
+import (
+	"github.com/gdamore/tcell/v2"
+)
+
+// statusBarSegment is one named field of a StatusBar.
+type statusBarSegment struct {
+	name  string      // The segment's name.
+	text  string      // The text to be displayed, may contain color tags.
+	align int         // AlignLeft, AlignCenter, or AlignRight.
+	style tcell.Style // The text style. tcell.StyleDefault uses the bar's style.
+}
+
+// StatusBar is a single row of text fields ("segments"), typically placed at
+// the bottom of the screen as the last item of a vertical Flex with a fixed
+// size of 1:
+//
+//	flex.AddItem(statusBar, 1, 0, false)
+//
+// Segments are added with AddSegment() and aligned to the left, to the center,
+// or to the right. Segments with the same alignment are shown in the order they
+// were added, separated by the separator (see SetSeparator()). Their text can
+// be updated independently with SetSegment(). If there is not enough space,
+// the right segments are shown first, then the left segments, and the center
+// segments are truncated.
+type StatusBar struct {
+	*Box
+
+	// The segments in the order they were added.
+	segments []*statusBarSegment
+
+	// The text drawn between two segments with the same alignment.
+	separator string
+
+	// The default style of the segments.
+	style tcell.Style
+}
+
+// NewStatusBar returns a new status bar without segments.
+func NewStatusBar() *StatusBar {
+	s := &StatusBar{
+		Box:       NewBox(),
+		separator: " │ ",
+		style:     tcell.StyleDefault.Foreground(Styles.PrimaryTextColor).Background(Styles.ContrastBackgroundColor),
+	}
+	s.backgroundColor = Styles.ContrastBackgroundColor
+	return s
+}
+
+// AddSegment adds a segment with the given name, text, and alignment (one of
+// AlignLeft, AlignCenter, or AlignRight). If there already is a segment with
+// the given name, its text and alignment are changed instead.
+func (s *StatusBar) AddSegment(name, text string, align int) *StatusBar {
+	if segment := s.segment(name); segment != nil {
+		segment.text, segment.align = text, align
+		return s
+	}
+	s.segments = append(s.segments, &statusBarSegment{
+		name:  name,
+		text:  text,
+		align: align,
+		style: tcell.StyleDefault,
+	})
+	return s
+}
+
+// SetSegment sets the text of the segment with the given name. If there is no
+// such segment, it is added with left alignment.
+func (s *StatusBar) SetSegment(name, text string) *StatusBar {
+	if segment := s.segment(name); segment != nil {
+		segment.text = text
+		return s
+	}
+	return s.AddSegment(name, text, AlignLeft)
+}
+
+// GetSegment returns the text of the segment with the given name or an empty
+// string if there is no such segment.
+func (s *StatusBar) GetSegment(name string) string {
+	if segment := s.segment(name); segment != nil {
+		return segment.text
+	}
+	return ""
+}
+
+// SetSegmentStyle sets the style of the segment with the given name. A style of
+// tcell.StyleDefault (the default) uses the style of the status bar (see
+// SetStyle()).
+func (s *StatusBar) SetSegmentStyle(name string, style tcell.Style) *StatusBar {
+	if segment := s.segment(name); segment != nil {
+		segment.style = style
+	}
+	return s
+}
+
+// RemoveSegment removes the segment with the given name.
+func (s *StatusBar) RemoveSegment(name string) *StatusBar {
+	for index, segment := range s.segments {
+		if segment.name == name {
+			s.segments = append(s.segments[:index], s.segments[index+1:]...)
+			break
+		}
+	}
+	return s
+}
+
+// ClearSegments removes all segments.
+func (s *StatusBar) ClearSegments() *StatusBar {
+	s.segments = nil
+	return s
+}
+
+// SetSeparator sets the text drawn between two adjacent segments with the same
+// alignment. The default is a vertical line surrounded by spaces.
+func (s *StatusBar) SetSeparator(separator string) *StatusBar {
+	s.separator = separator
+	return s
+}
+
+// SetStyle sets the default style of the segments and of the separators.
+func (s *StatusBar) SetStyle(style tcell.Style) *StatusBar {
+	s.style = style
+	s.overrides.set(&s.style)
+	return s
+}
+
+// segment returns the segment with the given name or nil if there is none.
+func (s *StatusBar) segment(name string) *statusBarSegment {
+	for _, segment := range s.segments {
+		if segment.name == name {
+			return segment
+		}
+	}
+	return nil
+}
+
+// group returns the segments with the given alignment and their total width,
+// including separators.
+func (s *StatusBar) group(align int) (segments []*statusBarSegment, width int) {
+	separatorWidth := TaggedStringWidth(s.separator)
+	for _, segment := range s.segments {
+		if segment.align != align {
+			continue
+		}
+		if len(segments) > 0 {
+			width += separatorWidth
+		}
+		segments = append(segments, segment)
+		width += TaggedStringWidth(segment.text)
+	}
+	return
+}
+
+// drawGroup prints the given segments starting at the given position, using at
+// most the given width. If the segments don't fit, the last visible cell is
+// replaced with an ellipsis.
+func (s *StatusBar) drawGroup(screen tcell.Screen, segments []*statusBarSegment, x, y, width, totalWidth int) {
+	if width <= 0 {
+		return
+	}
+	start, end := x, x+width
+	for index, segment := range segments {
+		if index > 0 {
+			_, printed, _, _ := printWithStyle(screen, s.separator, x, y, 0, end-x, AlignLeft, s.style, false)
+			x += printed
+		}
+		style := segment.style
+		if style == tcell.StyleDefault {
+			style = s.style
+		}
+		_, printed, _, _ := printWithStyle(screen, segment.text, x, y, 0, end-x, AlignLeft, style, false)
+		x += printed
+	}
+	if totalWidth > width {
+		_, _, style, _ := screen.GetContent(start+width-1, y)
+		screen.SetContent(start+width-1, y, SemigraphicsHorizontalEllipsis, nil, style)
+	}
+}
+
+// applyTheme implements the themeable interface.
+func (s *StatusBar) applyTheme(from, to *Theme) {
+	s.Box.applyTheme(from, to)
+	s.overrides.color(&s.backgroundColor, to.ContrastBackgroundColor)
+	s.overrides.style(&s.style, tcell.StyleDefault.Foreground(to.PrimaryTextColor).Background(to.ContrastBackgroundColor))
+}
+
+// Draw draws this primitive onto the screen.
+func (s *StatusBar) Draw(screen tcell.Screen) {
+	s.Box.DrawForSubclass(screen, s)
+	x, y, width, height := s.GetInnerRect()
+	if width <= 0 || height <= 0 {
+		return
+	}
+
+	// The right segments have precedence, then the left ones.
+	right, rightWidth := s.group(AlignRight)
+	left, leftWidth := s.group(AlignLeft)
+	center, centerWidth := s.group(AlignCenter)
+	if rightWidth > width {
+		s.drawGroup(screen, right, x, y, width, rightWidth)
+		return
+	}
+	s.drawGroup(screen, right, x+width-rightWidth, y, rightWidth, rightWidth)
+	available := width - rightWidth
+	if rightWidth > 0 {
+		available-- // Keep a gap.
+	}
+	if leftWidth > available {
+		s.drawGroup(screen, left, x, y, available, leftWidth)
+		return
+	}
+	s.drawGroup(screen, left, x, y, leftWidth, leftWidth)
+
+	// The center segments are centered on the entire bar if possible and
+	// squeezed between the other segments otherwise.
+	if len(center) == 0 {
+		return
+	}
+	from, to := x, x+width
+	if leftWidth > 0 {
+		from += leftWidth + 1
+	}
+	if rightWidth > 0 {
+		to -= rightWidth + 1
+	}
+	if to-from <= 0 {
+		return
+	}
+	start := x + (width-centerWidth)/2
+	if start+centerWidth > to {
+		start = to - centerWidth
+	}
+	if start < from {
+		start = from
+	}
+	visible := centerWidth
+	if start+visible > to {
+		visible = to - start
+	}
+	s.drawGroup(screen, center, start, y, visible, centerWidth)
+}
@@ -18,10 +18,9 @@ type Theme struct {
 	ContrastSecondaryTextColor  tcell.Color // Secondary text on ContrastBackgroundColor-colored backgrounds.
 }
 
-// Styles defines the theme for applications. The default is for a black
-// background and some basic colors: black, white, yellow, green, cyan, and
-// blue.
-var Styles = Theme{
+// DarkTheme is a theme with a black background and some basic colors: black,
+// white, yellow, green, cyan, and blue. It is the default theme.
+var DarkTheme = Theme{
 	// PrimitiveBackgroundColor:    tcell.GetColor("#101010").TrueColor(),
   PrimitiveBackgroundColor:    tcell.ColorBlack,
 	ContrastBackgroundColor:     tcell.ColorBlue,
@@ -36,3 +35,97 @@ var Styles = Theme{
 	InverseTextColor:            tcell.ColorBlue,
 	ContrastSecondaryTextColor:  tcell.ColorDarkCyan,
 }
+
+// LightTheme is a theme with a white background and dark text.
+var LightTheme = Theme{
+	PrimitiveBackgroundColor:    tcell.ColorWhite,
+	ContrastBackgroundColor:     tcell.ColorLightGray,
+	MoreContrastBackgroundColor: tcell.ColorLightSkyBlue,
+	BorderColor:                 tcell.ColorBlack,
+	BorderFocusColor:            tcell.ColorBlue,
+	TitleColor:                  tcell.ColorBlack,
+	GraphicsColor:               tcell.ColorBlack,
+	PrimaryTextColor:            tcell.ColorBlack,
+	SecondaryTextColor:          tcell.ColorNavy,
+	TertiaryTextColor:           tcell.ColorDarkGreen,
+	InverseTextColor:            tcell.ColorLightCyan,
+	ContrastSecondaryTextColor:  tcell.ColorMaroon,
+}
+
+// Styles defines the theme for applications. Primitives take their colors
+// from this theme when they are created. The default is DarkTheme. Use
+// Application.SetTheme() to change the theme while the application is running.
+var Styles = DarkTheme
+
+// themeOverrides records which of a primitive's theme colors and styles were
+// set explicitly with its setters, keyed by the fields' addresses. These are
+// not changed by Application.SetTheme().
+type themeOverrides map[any]struct{}
+
+// set records that the given fields were set explicitly.
+func (o *themeOverrides) set(fields ...any) {
+	if *o == nil {
+		*o = make(themeOverrides)
+	}
+	for _, field := range fields {
+		(*o)[field] = struct{}{}
+	}
+}
+
+// isSet returns whether the given field was set explicitly.
+func (o themeOverrides) isSet(field any) bool {
+	_, ok := o[field]
+	return ok
+}
+
+// color sets the given color field to the given theme color unless it was set
+// explicitly.
+func (o themeOverrides) color(field *tcell.Color, color tcell.Color) {
+	if !o.isSet(field) {
+		*field = color
+	}
+}
+
+// style sets the given style field to the given theme style unless it was set
+// explicitly.
+func (o themeOverrides) style(field *tcell.Style, style tcell.Style) {
+	if !o.isSet(field) {
+		*field = style
+	}
+}
+
+// themeable is implemented by primitives whose colors can be changed from one
+// theme to another.
+type themeable interface {
+	// applyTheme replaces the primitive's colors and styles which were not set
+	// explicitly with those derived from the "to" theme. The "from" theme is
+	// the one which was replaced.
+	applyTheme(from, to *Theme)
+}
+
+// applyTheme changes the colors of the given primitive and of all primitives
+// contained in it from one theme to the other, including hidden pages.
+func applyTheme(primitive Primitive, from, to *Theme) {
+	if primitive == nil {
+		return
+	}
+	if t, ok := primitive.(themeable); ok {
+		t.applyTheme(from, to)
+	}
+	var children []Primitive
+	switch p := primitive.(type) {
+	case *Pages:
+		for _, page := range p.pages {
+			children = append(children, page.Item)
+		}
+	case *Grid:
+		for _, item := range p.items {
+			children = append(children, item.Item)
+		}
+	default:
+		children = childPrimitives(primitive)
+	}
+	for _, child := range children {
+		applyTheme(child, from, to)
+	}
+}
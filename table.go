@@ -1,7 +1,11 @@
 package tview
 
 import (
+	"encoding/csv"
+	"io"
 	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/gdamore/tcell/v2"
 	colorful "github.com/lucasb-eyer/go-colorful"
@@ -54,6 +58,10 @@ type TableCell struct {
 
 	// The position and width of the cell the last time table was drawn.
 	x, y, width int
+
+	// The colors which were set with the cell's setters and are kept by
+	// Application.SetTheme().
+	overrides themeOverrides
 }
 
 // NewTableCell returns a new table cell with sensible defaults. That is, left
@@ -114,6 +122,7 @@ func (c *TableCell) SetExpansion(expansion int) *TableCell {
 // SetTextColor sets the cell's text color.
 func (c *TableCell) SetTextColor(color tcell.Color) *TableCell {
 	c.Color = color
+	c.overrides.set(&c.Color)
 	return c
 }
 
@@ -122,6 +131,7 @@ func (c *TableCell) SetTextColor(color tcell.Color) *TableCell {
 func (c *TableCell) SetBackgroundColor(color tcell.Color) *TableCell {
 	c.BackgroundColor = color
 	c.Transparent = false
+	c.overrides.set(&c.BackgroundColor)
 	return c
 }
 
@@ -146,9 +156,22 @@ func (c *TableCell) SetAttributes(attr tcell.AttrMask) *TableCell {
 // attributes) all at once.
 func (c *TableCell) SetStyle(style tcell.Style) *TableCell {
 	c.Color, c.BackgroundColor, c.Attributes = style.Decompose()
+	c.overrides.set(&c.Color, &c.BackgroundColor)
 	return c
 }
 
+// applyTheme changes the cell's colors from the "from" theme to the "to" theme
+// unless they were set explicitly, either with the cell's setters or by
+// assigning other colors to the cell's fields.
+func (c *TableCell) applyTheme(from, to *Theme) {
+	if c.Color == from.PrimaryTextColor {
+		c.overrides.color(&c.Color, to.PrimaryTextColor)
+	}
+	if c.BackgroundColor == from.PrimitiveBackgroundColor {
+		c.overrides.color(&c.BackgroundColor, to.PrimitiveBackgroundColor)
+	}
+}
+
 // SetSelectable sets whether or not this cell can be selected by the user.
 func (c *TableCell) SetSelectable(selectable bool) *TableCell {
 	c.NotSelectable = !selectable
@@ -413,6 +436,9 @@ func (t *tableDefaultContent) GetColumnCount() int {
 // set, individual cells can be selected. The "selected" handler set via
 // SetSelectedFunc() is invoked when the user presses Enter on a selection.
 //
+// If cells are selectable, their text may also be edited in place (see
+// SetCellEditable()).
+//
 // Navigation
 //
 // If the table extends beyond the available space, it can be navigated with
@@ -456,6 +482,30 @@ type Table struct {
 	// The number of fixed rows / columns.
 	fixedRows, fixedColumns int
 
+	// The runes drawn between the fixed and the scrollable rows / columns while
+	// the table is scrolled. A value of 0 disables the separator.
+	fixedRowSeparator, fixedColumnSeparator rune
+
+	// The styles of the fixed row / column separators.
+	fixedRowSeparatorStyle, fixedColumnSeparatorStyle tcell.Style
+
+	// The style applied to the first scrollable row / column while content is
+	// scrolled under the fixed rows / columns. The empty style disables it.
+	fixedShadowStyle tcell.Style
+
+	// Functions which compare two cells when sorting by a column, keyed by
+	// column index. Columns without a function are sorted with TableCellLess.
+	sortFuncs map[int]func(a, b *TableCell) bool
+
+	// If set to true, clicking a cell of a fixed row sorts the table by the
+	// cell's column.
+	sortClicks bool
+
+	// The column the table was last sorted by (-1 if it wasn't sorted) and
+	// whether it was sorted in ascending order.
+	sortColumn    int
+	sortAscending bool
+
 	// Whether or not rows or columns can be selected. If both are set to true,
 	// cells can be selected.
 	rowsSelectable, columnsSelectable bool
@@ -506,6 +556,18 @@ type Table struct {
 	// An optional function which gets called when the user presses Escape, Tab,
 	// or Backtab. Also when the user presses Enter if nothing is selectable.
 	done func(key tcell.Key)
+
+	// If set to true, pressing Enter on a selected cell edits its text.
+	cellEditable bool
+
+	// An optional function which gets called when the user has edited the
+	// text of a cell.
+	cellEdited func(row, column int, text string)
+
+	// The input field used to edit a cell and the position of the cell. The
+	// editor is nil if no cell is being edited.
+	editor              *InputField
+	editRow, editColumn int
 }
 
 // NewTable returns a new table.
@@ -514,6 +576,7 @@ func NewTable() *Table {
 		Box:          NewBox(),
 		bordersColor: Styles.GraphicsColor,
 		separator:    ' ',
+		sortColumn:   -1,
 	}
 	t.SetContent(nil)
 	return t
@@ -553,6 +616,7 @@ func (t *Table) SetBorders(show bool) *Table {
 // SetBordersColor sets the color of the cell borders.
 func (t *Table) SetBordersColor(color tcell.Color) *Table {
 	t.bordersColor = color
+	t.overrides.set(&t.bordersColor)
 	return t
 }
 
@@ -588,6 +652,166 @@ func (t *Table) SetFixed(rows, columns int) *Table {
 	return t
 }
 
+// SetFixedRowSeparator sets the rune and style of a horizontal line which is
+// drawn between the fixed rows and the remaining rows while the table is
+// scrolled down, i.e. while content is hidden under the fixed rows. If cell
+// borders are activated, the line replaces the border below the fixed rows.
+// Otherwise, an additional screen row is used for it. A rune of 0 (the
+// default) disables the separator.
+func (t *Table) SetFixedRowSeparator(separator rune, style tcell.Style) *Table {
+	t.fixedRowSeparator, t.fixedRowSeparatorStyle = separator, style
+	return t
+}
+
+// SetFixedColumnSeparator sets the rune and style of a vertical line which is
+// drawn between the fixed columns and the remaining columns while the table is
+// scrolled to the right, i.e. while content is hidden under the fixed columns.
+// The line replaces the column separator (see [Table.SetSeparator]) or, if cell
+// borders are activated, the cell border. A rune of 0 (the default) disables
+// the separator.
+func (t *Table) SetFixedColumnSeparator(separator rune, style tcell.Style) *Table {
+	t.fixedColumnSeparator, t.fixedColumnSeparatorStyle = separator, style
+	return t
+}
+
+// SetFixedShadowStyle sets a style which is applied to the first scrollable row
+// (or column) while the table is scrolled under its fixed rows (or columns).
+// Only the background color and the attributes of the style are used. The
+// empty style (the default) disables the shadow.
+func (t *Table) SetFixedShadowStyle(style tcell.Style) *Table {
+	t.fixedShadowStyle = style
+	return t
+}
+
+// TableCellLess compares the texts of two cells lexicographically, ignoring
+// style tags. A nil cell is treated as an empty cell. This is the default
+// comparison when sorting a table by a column.
+func TableCellLess(a, b *TableCell) bool {
+	return tableCellText(a) < tableCellText(b)
+}
+
+// TableCellNumericLess compares the texts of two cells as floating point
+// numbers, ignoring style tags and surrounding whitespace. Cells whose texts
+// are not numbers are sorted after those which are, lexicographically among
+// themselves. Use it with Table.SetSortFunc() for numeric columns.
+func TableCellNumericLess(a, b *TableCell) bool {
+	textA, textB := tableCellText(a), tableCellText(b)
+	numberA, errA := strconv.ParseFloat(strings.TrimSpace(textA), 64)
+	numberB, errB := strconv.ParseFloat(strings.TrimSpace(textB), 64)
+	switch {
+	case errA == nil && errB == nil:
+		return numberA < numberB
+	case errA == nil:
+		return true
+	case errB == nil:
+		return false
+	}
+	return textA < textB
+}
+
+// tableCellText returns the text of a cell without style tags or an empty
+// string for a nil cell.
+func tableCellText(cell *TableCell) string {
+	if cell == nil {
+		return ""
+	}
+	return stripTags(cell.Text)
+}
+
+// SetSortFunc sets the function which compares two cells of the given column
+// when the table is sorted by that column. It returns true if cell "a" comes
+// before cell "b" in ascending order. Cells may be nil. Columns without a
+// function are compared with TableCellLess. Provide nil to reset a column to
+// the default comparison. See also TableCellNumericLess.
+func (t *Table) SetSortFunc(column int, less func(a, b *TableCell) bool) *Table {
+	if less == nil {
+		delete(t.sortFuncs, column)
+		return t
+	}
+	if t.sortFuncs == nil {
+		t.sortFuncs = make(map[int]func(a, b *TableCell) bool)
+	}
+	t.sortFuncs[column] = less
+	return t
+}
+
+// SetSortClicksEnabled sets whether clicking a cell in one of the fixed rows
+// (see SetFixed()) sorts the table by that cell's column. Clicking the same
+// column again toggles between ascending and descending order. This is off by
+// default.
+func (t *Table) SetSortClicksEnabled(enabled bool) *Table {
+	t.sortClicks = enabled
+	return t
+}
+
+// SortByColumn sorts all rows below the fixed rows by the cells of the given
+// column, in ascending or descending order. The sort is stable. The selected
+// row follows its data to its new position.
+//
+// Rows are rearranged with the content's GetCell() and SetCell() functions so
+// sorting has no effect on read-only content (see TableContentReadOnly).
+func (t *Table) SortByColumn(column int, ascending bool) *Table {
+	rowCount, columnCount := t.content.GetRowCount(), t.content.GetColumnCount()
+	if column < 0 || column >= columnCount || t.fixedRows >= rowCount {
+		return t
+	}
+	t.sortColumn, t.sortAscending = column, ascending
+
+	// Sort the row indices.
+	less := t.sortFuncs[column]
+	if less == nil {
+		less = TableCellLess
+	}
+	order := make([]int, 0, rowCount-t.fixedRows)
+	for row := t.fixedRows; row < rowCount; row++ {
+		order = append(order, row)
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		a, b := t.content.GetCell(order[i], column), t.content.GetCell(order[j], column)
+		if ascending {
+			return less(a, b)
+		}
+		return less(b, a)
+	})
+
+	// Rearrange the rows.
+	rows := make([][]*TableCell, len(order))
+	for index, row := range order {
+		rows[index] = make([]*TableCell, columnCount)
+		for c := 0; c < columnCount; c++ {
+			rows[index][c] = t.content.GetCell(row, c)
+		}
+	}
+	selectedRow := t.selectedRow
+	for index, cells := range rows {
+		row := t.fixedRows + index
+		for c, cell := range cells {
+			t.content.SetCell(row, c, cell)
+		}
+		if order[index] == selectedRow {
+			t.selectedRow = row
+		}
+	}
+	if t.selectedRow != selectedRow {
+		t.clampToSelection = true
+	}
+
+	return t
+}
+
+// GetSortColumn returns the column the table was last sorted by and whether
+// it was sorted in ascending order. The column is -1 if the table hasn't been
+// sorted.
+func (t *Table) GetSortColumn() (column int, ascending bool) {
+	return t.sortColumn, t.sortAscending
+}
+
+// showFixedRowSeparator returns whether an additional screen row is used to
+// draw the fixed row separator.
+func (t *Table) showFixedRowSeparator() bool {
+	return t.fixedRowSeparator != 0 && !t.borders && t.fixedRows > 0 && t.rowOffset > 0
+}
+
 // SetSelectable sets the flags which determine what can be selected in a table.
 // There are three selection modi:
 //
@@ -759,6 +983,69 @@ func (t *Table) SetDoneFunc(handler func(key tcell.Key)) *Table {
 	return t
 }
 
+// SetCellEditable sets whether the text of cells can be edited in place. If
+// enabled and individual cells are selectable (see SetSelectable()), pressing
+// Enter on the selected cell opens an input field on top of it instead of
+// invoking the "selected" handler. Enter commits the new text, Escape cancels
+// the edit. Clicking somewhere else or moving the focus away from the table
+// also commits it.
+func (t *Table) SetCellEditable(editable bool) *Table {
+	t.cellEditable = editable
+	if !editable {
+		t.stopEditing(false)
+	}
+	return t
+}
+
+// SetCellEditedFunc sets a handler which is called when the user has edited
+// the text of a cell (see SetCellEditable()). The new text has already been
+// set on the cell when the handler is called.
+func (t *Table) SetCellEditedFunc(handler func(row, column int, text string)) *Table {
+	t.cellEdited = handler
+	return t
+}
+
+// IsEditing returns whether a cell is currently being edited.
+func (t *Table) IsEditing() bool {
+	return t.editor != nil
+}
+
+// startEditing opens the editor on the given cell.
+func (t *Table) startEditing(row, column int) {
+	cell := t.content.GetCell(row, column)
+	if cell == nil {
+		return
+	}
+	t.editRow, t.editColumn = row, column
+	t.editor = NewInputField().
+		SetText(cell.Text).
+		SetDoneFunc(func(key tcell.Key) {
+			t.stopEditing(key != tcell.KeyEscape)
+		})
+	t.editor.SetRect(0, 0, 0, 0) // Positioned when drawn.
+	t.editor.Focus(nil)
+	t.clampToSelection = true
+}
+
+// stopEditing closes the editor, if it is open. If commit is true, the edited
+// text is written to the cell and the "edited" handler is called.
+func (t *Table) stopEditing(commit bool) {
+	if t.editor == nil {
+		return
+	}
+	text := t.editor.GetText()
+	t.editor = nil
+	if !commit {
+		return
+	}
+	if cell := t.content.GetCell(t.editRow, t.editColumn); cell != nil {
+		cell.SetText(text)
+	}
+	if t.cellEdited != nil {
+		t.cellEdited(t.editRow, t.editColumn, text)
+	}
+}
+
 // SetCell sets the content of a cell the specified position. It is ok to
 // directly instantiate a TableCell object. If the cell has content, at least
 // the Text and Color fields should be set.
@@ -834,6 +1121,79 @@ func (t *Table) GetColumnCount() int {
 	return t.content.GetColumnCount()
 }
 
+// ExportCSV writes the text of all table cells to the given writer in CSV
+// format (RFC 4180), one record per row. Color tags are stripped from the cell
+// texts. Cells containing commas, quotes, or line breaks are quoted. Missing
+// cells are written as empty fields so that all records have the same number
+// of fields.
+func (t *Table) ExportCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	columnCount := t.content.GetColumnCount()
+	for row := 0; row < t.content.GetRowCount(); row++ {
+		record := make([]string, columnCount)
+		for column := range record {
+			record[column] = tableCellText(t.content.GetCell(row, column))
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// ExportMarkdown writes the text of all table cells to the given writer as a
+// GitHub-flavored Markdown table. Color tags are stripped from the cell texts.
+// If the table has fixed rows (see SetFixed()), the first row is used as the
+// table's header. Otherwise, the header is left empty. Pipe characters are
+// escaped and line breaks are replaced with "<br>".
+func (t *Table) ExportMarkdown(w io.Writer) error {
+	rowCount, columnCount := t.content.GetRowCount(), t.content.GetColumnCount()
+	if columnCount == 0 {
+		return nil
+	}
+	writeRow := func(row int) error {
+		var b strings.Builder
+		b.WriteString("|")
+		for column := 0; column < columnCount; column++ {
+			text := ""
+			if row >= 0 {
+				text = tableCellText(t.content.GetCell(row, column))
+				text = strings.ReplaceAll(text, "|", `\|`)
+				text = strings.ReplaceAll(text, "\r\n", "<br>")
+				text = strings.ReplaceAll(text, "\n", "<br>")
+			}
+			b.WriteString(" " + text + " |")
+		}
+		b.WriteString("\n")
+		_, err := io.WriteString(w, b.String())
+		return err
+	}
+
+	// The header.
+	firstRow := 0
+	if t.fixedRows > 0 && rowCount > 0 {
+		firstRow = 1
+		if err := writeRow(0); err != nil {
+			return err
+		}
+	} else if err := writeRow(-1); err != nil {
+		return err
+	}
+	if _, err := io.WriteString(w, "|"+strings.Repeat(" --- |", columnCount)+"\n"); err != nil {
+		return err
+	}
+
+	// The body.
+	for row := firstRow; row < rowCount; row++ {
+		if err := writeRow(row); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // cellAt returns the row and column located at the given screen coordinates.
 // Each returned value may be negative if there is no row and/or cell. This
 // function will also process coordinates outside the table's inner rectangle so
@@ -850,6 +1210,13 @@ func (t *Table) cellAt(x, y int) (row, column int) {
 
 	// Respect fixed rows and row offset.
 	if row >= 0 {
+		if t.showFixedRowSeparator() && row >= t.fixedRows {
+			if row == t.fixedRows {
+				row = -1 // The separator.
+			} else {
+				row--
+			}
+		}
 		if row >= t.fixedRows {
 			row += t.rowOffset
 		}
@@ -914,6 +1281,20 @@ func (t *Table) SetWrapSelection(vertical, horizontal bool) *Table {
 	return t
 }
 
+// applyTheme implements the themeable interface. Cells which are created on
+// the fly by a custom TableContent are not changed.
+func (t *Table) applyTheme(from, to *Theme) {
+	t.Box.applyTheme(from, to)
+	t.overrides.color(&t.bordersColor, to.GraphicsColor)
+	for row := 0; row < t.content.GetRowCount(); row++ {
+		for column := 0; column < t.content.GetColumnCount(); column++ {
+			if cell := t.content.GetCell(row, column); cell != nil {
+				cell.applyTheme(from, to)
+			}
+		}
+	}
+}
+
 // Draw draws this primitive onto the screen.
 func (t *Table) Draw(screen tcell.Screen) {
 	t.Box.DrawForSubclass(screen, t)
@@ -951,6 +1332,28 @@ func (t *Table) Draw(screen tcell.Screen) {
 		}
 	}
 
+	// While the table is scrolled down, a separator below the fixed rows may
+	// take one screen row from the scrollable rows.
+	rowSeparatorAllowed := t.fixedRowSeparator != 0 && !t.borders && t.fixedRows > 0
+	scrollHeight := func() int { // The number of screen rows for table rows.
+		if rowSeparatorAllowed && t.rowOffset > 0 {
+			return height - 1
+		}
+		return height
+	}
+	lastRowOffset := func(row int) int { // The row offset which shows the given row last.
+		offset := row + 1 - height
+		if rowSeparatorAllowed && offset > 0 {
+			offset++
+		}
+		return offset
+	}
+
+	// Keep the edited cell visible.
+	if t.editor != nil {
+		t.clampToSelection = true
+	}
+
 	// Clamp row offsets if requested.
 	defer func() {
 		t.clampToSelection = false // Only once.
@@ -966,8 +1369,8 @@ func (t *Table) Draw(screen tcell.Screen) {
 				t.trackEnd = false
 			}
 		} else {
-			if t.selectedRow+1-t.rowOffset >= height {
-				t.rowOffset = t.selectedRow + 1 - height
+			if t.selectedRow+1-t.rowOffset >= scrollHeight() {
+				t.rowOffset = lastRowOffset(t.selectedRow)
 				t.trackEnd = false
 			}
 		}
@@ -980,7 +1383,7 @@ func (t *Table) Draw(screen tcell.Screen) {
 			t.trackEnd = true
 		}
 	} else {
-		if rowCount-t.rowOffset < height {
+		if rowCount-t.rowOffset < scrollHeight() {
 			t.trackEnd = true
 		}
 	}
@@ -988,7 +1391,7 @@ func (t *Table) Draw(screen tcell.Screen) {
 		if t.borders {
 			t.rowOffset = rowCount - height/2
 		} else {
-			t.rowOffset = rowCount - height
+			t.rowOffset = lastRowOffset(rowCount - 1)
 		}
 	}
 	if t.rowOffset < 0 {
@@ -1033,6 +1436,10 @@ func (t *Table) Draw(screen tcell.Screen) {
 		}
 	}
   overUp = t.rowOffset > 0
+	rowSeparator := t.showFixedRowSeparator() && len(rows) == t.fixedRows && tableHeight < height
+	if rowSeparator {
+		tableHeight++ // The separator takes one screen row.
+	}
 	for row := t.fixedRows + t.rowOffset; row < rowCount; row++ { // Then the remaining rows.
 		if !indexRow(row) {
       overDown=true
@@ -1148,7 +1555,11 @@ func (t *Table) Draw(screen tcell.Screen) {
 			if !includesSelection {
 				// Clamp to selection.
 				resetColumns()
-				if t.selectedColumn <= t.fixedColumns+t.columnOffset {
+				if t.selectedColumn < t.fixedColumns {
+					// It's a fixed column and cannot be scrolled into view.
+					// Starting with it would show fixed columns twice.
+					indexColumns(t.fixedColumns+t.columnOffset, columnCount)
+				} else if t.selectedColumn <= t.fixedColumns+t.columnOffset {
 					// It's on the left. Start with the selection.
 					t.columnOffset = t.selectedColumn - t.fixedColumns
 					indexColumns(t.fixedColumns+t.columnOffset, columnCount)
@@ -1200,10 +1611,18 @@ func (t *Table) Draw(screen tcell.Screen) {
 	if t.borders {
 		columnX++
 	}
+	columnSeparatorX, shadowX, shadowWidth := -1, -1, 0
+	columnSeparator := t.columnOffset > 0 && t.fixedColumns > 0 && len(columns) > t.fixedColumns
   // overflown := false
 	for columnIndex, column := range columns {
 		columnWidth := widths[columnIndex]
+		if columnSeparator && columnIndex == t.fixedColumns {
+			columnSeparatorX, shadowX, shadowWidth = columnX-1, columnX, columnWidth
+		}
 		for rowY, row := range rows {
+			if rowSeparator && row >= t.fixedRows {
+				rowY++
+			}
 			if t.borders {
 				// Draw borders.
 				rowY *= 2
@@ -1244,11 +1663,7 @@ func (t *Table) Draw(screen tcell.Screen) {
 				finalWidth = width - columnX
 			}
 			cell.x, cell.y, cell.width = x+columnX, y+rowY, finalWidth
-			_, printed, _, _ := printWithStyle(screen, cell.Text, x+columnX, y+rowY, 0, finalWidth, cell.Align, tcell.StyleDefault.Foreground(cell.Color).Attributes(cell.Attributes), true)
-			if TaggedStringWidth(cell.Text)-printed > 0 && printed > 0 {
-				_, _, style, _ := screen.GetContent(x+columnX+finalWidth-1, y+rowY)
-				printWithStyle(screen, string(SemigraphicsHorizontalEllipsis), x+columnX+finalWidth-1, y+rowY, 0, 1, AlignLeft, style, false)
-			}
+			printWithEllipsis(screen, cell.Text, x+columnX, y+rowY, finalWidth, cell.Align, tcell.StyleDefault.Foreground(cell.Color).Attributes(cell.Attributes), true)
 		}
 
 		// Draw bottom border.
@@ -1346,6 +1761,9 @@ func (t *Table) Draw(screen tcell.Screen) {
 	for rowY, row := range rows {
 		columnX := 0
 		rowSelected := t.rowsSelectable && !t.columnsSelectable && row == t.selectedRow
+		if rowSeparator && row >= t.fixedRows {
+			rowY++
+		}
 		for columnIndex, column := range columns {
 			columnWidth := widths[columnIndex]
 			cell := t.content.GetCell(row, column)
@@ -1406,17 +1824,130 @@ func (t *Table) Draw(screen tcell.Screen) {
 			}
 		}
 	}
+
+	// Draw the separators between the fixed and the scrollable cells and the
+	// shadow on the scrollable side.
+	tableScreenHeight := tableHeight
+	if t.borders {
+		tableScreenHeight = 2*len(rows) + 1
+	}
+	if tableScreenHeight > height {
+		tableScreenHeight = height
+	}
+	tableScreenWidth := columnX
+	if t.borders {
+		tableScreenWidth++ // Include the right border.
+	}
+	if tableScreenWidth > width {
+		tableScreenWidth = width
+	}
+	separatorStyle := func(style tcell.Style) tcell.Style {
+		if style == (tcell.Style{}) {
+			return borderStyle
+		}
+		return style
+	}
+	shade := func(fromX, fromY, w, h int) {
+		if t.fixedShadowStyle == (tcell.Style{}) {
+			return
+		}
+		_, shadowBg, shadowAttr := t.fixedShadowStyle.Decompose()
+		for by := fromY; by < fromY+h && by < tableScreenHeight; by++ {
+			for bx := fromX; bx < fromX+w && bx < tableScreenWidth; bx++ {
+				m, c, style, _ := screen.GetContent(x+bx, y+by)
+				_, _, attr := style.Decompose()
+				if shadowBg != tcell.ColorDefault {
+					style = style.Background(shadowBg)
+				}
+				screen.SetContent(x+bx, y+by, m, c, style.Attributes(attr|shadowAttr))
+			}
+		}
+	}
+	// Both shadows are drawn before the separators so that neither separator
+	// is shaded where they cross in the corner of the fixed cells.
+	separatorY := -1
+	if t.rowOffset > 0 && t.fixedRows > 0 && len(rows) > t.fixedRows {
+		shadowY := t.fixedRows
+		if t.borders {
+			shadowY = 2*t.fixedRows + 1
+		} else if rowSeparator {
+			shadowY++
+		}
+		shade(0, shadowY, tableScreenWidth, 1)
+		if t.fixedRowSeparator != 0 && (t.borders || rowSeparator) {
+			separatorY = t.fixedRows
+			if t.borders {
+				separatorY = 2 * t.fixedRows
+			}
+		}
+	}
+	if columnSeparatorX >= 0 {
+		shade(shadowX, 0, shadowWidth, tableScreenHeight)
+	}
+	if separatorY >= 0 && separatorY < tableScreenHeight {
+		style := separatorStyle(t.fixedRowSeparatorStyle)
+		for bx := 0; bx < tableScreenWidth; bx++ {
+			screen.SetContent(x+bx, y+separatorY, t.fixedRowSeparator, nil, style)
+		}
+	}
+	if columnSeparatorX >= 0 && t.fixedColumnSeparator != 0 && columnSeparatorX < width {
+		style := separatorStyle(t.fixedColumnSeparatorStyle)
+		for by := 0; by < tableScreenHeight; by++ {
+			ch := t.fixedColumnSeparator
+			if by == separatorY && ch == Borders.Vertical && t.fixedRowSeparator == Borders.Horizontal {
+				ch = Borders.Cross // Join the two separators.
+			}
+			screen.SetContent(x+columnSeparatorX, y+by, ch, nil, style)
+		}
+	}
+
 if overUp || overDown {
     defer t.DrawOverflow(screen, overUp,overDown, float64(float64(t.selectedRow) / float64(t.GetRowCount())))
   }
 
+	// Draw the cell editor on top of the edited cell.
+	if t.editor != nil {
+		if cell := t.content.GetCell(t.editRow, t.editColumn); cell != nil && containsInt(rows, t.editRow) && containsInt(columns, t.editColumn) {
+			cellX, cellY, cellWidth := cell.GetLastPosition()
+			t.editor.SetRect(cellX, cellY, cellWidth, 1)
+			t.editor.Draw(screen)
+		} else {
+			t.editor.SetRect(0, 0, 0, 0)
+		}
+	}
+
 	// Remember column infos.
 	t.visibleColumnIndices, t.visibleColumnWidths = columns, widths
 }
 
+// containsInt returns whether the given slice contains the given value.
+func containsInt(values []int, value int) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Blur is called when this primitive loses focus. A cell which is being edited
+// is committed.
+func (t *Table) Blur() {
+	t.stopEditing(true)
+	t.Box.Blur()
+}
+
 // InputHandler returns the handler for this primitive.
 func (t *Table) InputHandler() func(event *tcell.EventKey, setFocus func(p Primitive)) {
 	return t.WrapInputHandler(func(event *tcell.EventKey, setFocus func(p Primitive)) {
+		// Forward key events to the cell editor.
+		if t.editor != nil {
+			if handler := t.editor.InputHandler(); handler != nil {
+				handler(event, setFocus)
+			}
+			return
+		}
+
 		key := event.Key()
 
 		if (!t.rowsSelectable && !t.columnsSelectable && key == tcell.KeyEnter) ||
@@ -1682,6 +2213,10 @@ func (t *Table) InputHandler() func(event *tcell.EventKey, setFocus func(p Primi
 		case tcell.KeyPgUp, tcell.KeyCtrlB:
 			pageUp()
 		case tcell.KeyEnter:
+			if t.cellEditable && t.rowsSelectable && t.columnsSelectable {
+				t.startEditing(t.selectedRow, t.selectedColumn)
+				return
+			}
 			// if (t.rowsSelectable || t.columnsSelectable) {
         if t.GetCell(t.selectedRow,t.selectedColumn).DoSelected() {
           if (t.rowsSelectable || t.columnsSelectable) && t.selected != nil {
@@ -1705,6 +2240,20 @@ func (t *Table) InputHandler() func(event *tcell.EventKey, setFocus func(p Primi
 func (t *Table) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
 	return t.WrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
 		x, y := event.Position()
+
+		// Forward mouse events on the edited cell to the cell editor. Clicking
+		// anywhere else commits the edit.
+		if t.editor != nil {
+			if t.editor.InRect(x, y) {
+				return t.editor.MouseHandler()(action, event, func(p Primitive) {
+					setFocus(t)
+				})
+			}
+			if action == MouseLeftDown || action == MouseLeftClick {
+				t.stopEditing(true)
+			}
+		}
+
 		if !t.InRect(x, y) {
 			return false, nil
 		}
@@ -1719,6 +2268,11 @@ func (t *Table) MouseHandler() func(action MouseAction, event *tcell.EventMouse,
 					selectEvent = false
 				}
 			}
+			if t.sortClicks && row >= 0 && row < t.fixedRows && column >= 0 {
+				// Clicked a header. Sort instead of selecting.
+				t.SortByColumn(column, t.sortColumn != column || !t.sortAscending)
+				selectEvent = false
+			}
 			if selectEvent && (t.rowsSelectable || t.columnsSelectable) {
 				t.Select(row, column)
 			}
@@ -1731,6 +2285,12 @@ func (t *Table) MouseHandler() func(action MouseAction, event *tcell.EventMouse,
 		case MouseScrollDown:
 			t.rowOffset++
 			consumed = true
+		case MouseScrollLeft:
+			t.columnOffset--
+			consumed = true
+		case MouseScrollRight:
+			t.columnOffset++
+			consumed = true
 		}
 
 		return
@@ -0,0 +1,201 @@
+package tview
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// newNumberedTable returns a table with the given number of rows, each with a
+// single cell containing "row <index>".
+func newNumberedTable(rows int) *Table {
+	table := NewTable()
+	for row := 0; row < rows; row++ {
+		table.SetCell(row, 0, NewTableCell(fmt.Sprintf("row %d", row)))
+	}
+	return table
+}
+
+func TestTableFixedRowSeparator(t *testing.T) {
+	screen := newTestScreen(t, 10, 4)
+	table := newNumberedTable(10).
+		SetFixed(1, 0).
+		SetFixedRowSeparator('-', tcell.StyleDefault)
+	table.SetSelectable(true, false).SetRect(0, 0, 10, 4)
+
+	// Not scrolled: no separator, all screen rows show table rows.
+	table.Draw(screen)
+	for y, expected := range []string{"row 0", "row 1", "row 2", "row 3"} {
+		if line := screenLine(screen, y); !strings.HasPrefix(line, expected) {
+			t.Errorf("unscrolled row %d is %q, expected %q", y, line, expected)
+		}
+	}
+
+	// Selecting the last visible row doesn't scroll.
+	table.Select(3, 0)
+	screen.Clear()
+	table.Draw(screen)
+	if row, _ := table.GetOffset(); row != 0 {
+		t.Errorf("row offset is %d after selecting the last visible row, expected 0", row)
+	}
+
+	// Scrolled below the fixed rows: the separator takes one row.
+	table.Select(4, 0)
+	screen.Clear()
+	table.Draw(screen)
+	for y, expected := range []string{"row 0", "-----", "row 3", "row 4"} {
+		if line := screenLine(screen, y); !strings.HasPrefix(line, expected) {
+			t.Errorf("scrolled row %d is %q, expected %q", y, line, expected)
+		}
+	}
+
+	// Back at the top, the separator disappears again.
+	table.Select(1, 0)
+	screen.Clear()
+	table.Draw(screen)
+	if line := screenLine(screen, 1); !strings.HasPrefix(line, "row 1") {
+		t.Errorf("row 1 is %q after scrolling back, expected %q", line, "row 1")
+	}
+}
+
+// newExportTable returns a table with a header row and cells which need
+// escaping on export.
+func newExportTable() *Table {
+	return NewTable().
+		SetCell(0, 0, NewTableCell("Name")).
+		SetCell(0, 1, NewTableCell("Note")).
+		SetCell(1, 0, NewTableCell("[red]Smith, J.")).
+		SetCell(1, 1, NewTableCell(`says "hi"`)).
+		SetCell(2, 0, NewTableCell("a|b")).
+		SetCell(2, 1, NewTableCell("two\nlines")).
+		SetCell(3, 0, NewTableCell("only"))
+}
+
+func TestTableExportCSV(t *testing.T) {
+	var b strings.Builder
+	if err := newExportTable().ExportCSV(&b); err != nil {
+		t.Fatal(err)
+	}
+	expected := "Name,Note\n" +
+		"\"Smith, J.\",\"says \"\"hi\"\"\"\n" +
+		"a|b,\"two\nlines\"\n" +
+		"only,\n"
+	if b.String() != expected {
+		t.Errorf("CSV is\n%q\nexpected\n%q", b.String(), expected)
+	}
+}
+
+func TestTableExportMarkdown(t *testing.T) {
+	// Without fixed rows, the header is empty.
+	var b strings.Builder
+	if err := newExportTable().ExportMarkdown(&b); err != nil {
+		t.Fatal(err)
+	}
+	expected := "|  |  |\n" +
+		"| --- | --- |\n" +
+		"| Name | Note |\n" +
+		"| Smith, J. | says \"hi\" |\n" +
+		"| a\\|b | two<br>lines |\n" +
+		"| only |  |\n"
+	if b.String() != expected {
+		t.Errorf("Markdown is\n%s\nexpected\n%s", b.String(), expected)
+	}
+
+	// A fixed row becomes the header.
+	b.Reset()
+	if err := newExportTable().SetFixed(1, 0).ExportMarkdown(&b); err != nil {
+		t.Fatal(err)
+	}
+	expected = "| Name | Note |\n" +
+		"| --- | --- |\n" +
+		"| Smith, J. | says \"hi\" |\n" +
+		"| a\\|b | two<br>lines |\n" +
+		"| only |  |\n"
+	if b.String() != expected {
+		t.Errorf("Markdown with a header is\n%s\nexpected\n%s", b.String(), expected)
+	}
+
+	// An empty table writes nothing.
+	b.Reset()
+	if err := NewTable().ExportMarkdown(&b); err != nil || b.Len() > 0 {
+		t.Errorf("empty table exported %q (%v), expected nothing", b.String(), err)
+	}
+}
+
+func TestTableFixedDiagonalScroll(t *testing.T) {
+	screen := newTestScreen(t, 12, 4)
+	table := NewTable().SetFixed(2, 2)
+	for row := 0; row < 10; row++ {
+		for column := 0; column < 10; column++ {
+			table.SetCell(row, column, NewTableCell(fmt.Sprintf("%d%d", row, column)))
+		}
+	}
+	table.SetRect(0, 0, 12, 4)
+
+	// Scroll down and right at the same time. The fixed corner stays in
+	// place, the header rows follow the columns, the fixed columns follow the
+	// rows, and the body cells line up with both.
+	for offset := 0; offset <= 4; offset++ {
+		table.SetOffset(offset, offset)
+		screen.Clear()
+		table.Draw(screen)
+		for y, row := range []int{0, 1, offset + 2, offset + 3} {
+			expected := []string{
+				fmt.Sprintf("%d0", row),
+				fmt.Sprintf("%d1", row),
+				fmt.Sprintf("%d%d", row, offset+2),
+				fmt.Sprintf("%d%d", row, offset+3),
+			}
+			if cells := strings.Fields(screenLine(screen, y)); strings.Join(cells, " ") != strings.Join(expected, " ") {
+				t.Errorf("offset %d, row %d shows %q, expected %q", offset, y, cells, expected)
+			}
+		}
+	}
+
+	// The same when following the selection, with separators which cross in
+	// the corner. Selecting fixed cells doesn't scroll them into view again.
+	screen.SetSize(14, 6)
+	table.SetFixedRowSeparator(Borders.Horizontal, tcell.StyleDefault).
+		SetFixedColumnSeparator(Borders.Vertical, tcell.StyleDefault).
+		SetSelectable(true, true).
+		SetRect(0, 0, 14, 6)
+	table.SetOffset(0, 0)
+	for _, test := range []struct {
+		row, column int
+		expected    []string
+	}{
+		{7, 7, []string{"00 01│05 06 07", "10 11│15 16 17", "─────┼────────", "50 51│55 56 57", "60 61│65 66 67", "70 71│75 76 77"}},
+		{8, 0, []string{"00 01│05 06 07", "10 11│15 16 17", "─────┼────────", "60 61│65 66 67", "70 71│75 76 77", "80 81│85 86 87"}},
+		{1, 8, []string{"00 01│06 07 08", "10 11│16 17 18", "─────┼────────", "60 61│66 67 68", "70 71│76 77 78", "80 81│86 87 88"}},
+	} {
+		table.Select(test.row, test.column)
+		screen.Clear()
+		table.Draw(screen)
+		for y, expected := range test.expected {
+			if line := strings.TrimRight(screenLine(screen, y), " "); line != expected {
+				t.Errorf("selection %d,%d, row %d is %q, expected %q", test.row, test.column, y, line, expected)
+			}
+		}
+	}
+}
+
+func TestTableCellEllipsis(t *testing.T) {
+	screen := newTestScreen(t, 12, 2)
+	table := NewTable().
+		SetCell(0, 0, NewTableCell("abcdefgh").SetMaxWidth(4)).
+		SetCell(0, 1, NewTableCell("xy")).
+		SetCell(1, 0, NewTableCell("[red]日本語").SetMaxWidth(4))
+	table.SetRect(0, 0, 12, 2)
+	table.Draw(screen)
+	for row, expected := range []string{"abc… xy     ", "日 … "} {
+		if line := screenLine(screen, row); !strings.HasPrefix(line, expected) {
+			t.Errorf("row %d is %q, expected it to start with %q", row, line, expected)
+		}
+	}
+	_, _, style, _ := screen.GetContent(2, 1)
+	if fg, _, _ := style.Decompose(); fg != tcell.ColorRed {
+		t.Errorf("ellipsis has style %v, expected the color of the cell text", style)
+	}
+}
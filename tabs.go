@@ -0,0 +1,432 @@
+package tview
+
+import (
+	"strconv"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// tabsCloseGlyph is drawn after the label of each tab if tabs can be closed
+// (see Tabs.SetTabClosedFunc()).
+const tabsCloseGlyph = '×'
+
+// tab is one tab of a Tabs primitive.
+type tab struct {
+	label   string    // The tab's label, may contain color tags.
+	content Primitive // The tab's content.
+	name    string    // The name of the content's page.
+}
+
+// Tabs is a container which shows one of several primitives ("tabs") below a
+// row of tab labels. The content of the tabs is managed by a Pages primitive
+// (see GetPages()).
+//
+// When the row of tab labels has focus, the following keys can be used:
+//
+//   - Left arrow, right arrow: Switch to the previous or next tab.
+//   - Home, End: Switch to the first or last tab.
+//   - Enter, down arrow: Move focus to the content of the current tab.
+//   - Delete: Close the current tab if tabs can be closed.
+//
+// While the content of a tab has focus, Ctrl-Page up and Ctrl-Page down switch
+// to the previous or next tab. Tabs can also be switched by clicking on their
+// labels. If the labels don't fit into the row, it is scrolled such that the
+// current tab is visible and arrows at its ends indicate (and, when clicked,
+// reveal) hidden tabs.
+//
+// If a handler is set with SetTabClosedFunc(), a close glyph is drawn after
+// each label which closes the tab when clicked.
+type Tabs struct {
+	*Box
+
+	// The tabs, in the order they are shown.
+	tabs []*tab
+
+	// The container of the tabs' contents.
+	pages *Pages
+
+	// The index of the current tab, -1 if there are no tabs.
+	current int
+
+	// The index of the first visible tab label.
+	offset int
+
+	// Used to generate unique page names.
+	nextName int
+
+	// The style of the tab labels and of the current tab label.
+	tabStyle, currentTabStyle tcell.Style
+
+	// The screen positions of the visible tab labels as of the last call to
+	// Draw(), indexed like tabs. Hidden labels have a start position of -1.
+	tabStart, tabEnd []int
+
+	// The screen positions of the overflow arrows as of the last call to
+	// Draw(), -1 if they are not shown.
+	leftArrow, rightArrow int
+
+	// The row of the tab labels as of the last call to Draw().
+	tabRow int
+
+	// An optional function which is called when the current tab changes.
+	changed func(index int, label string, content Primitive)
+
+	// An optional function which is called when a tab was closed.
+	closed func(index int, label string, content Primitive)
+}
+
+// NewTabs returns a new Tabs primitive without tabs.
+func NewTabs() *Tabs {
+	return &Tabs{
+		Box:             NewBox(),
+		pages:           NewPages(),
+		current:         -1,
+		tabStyle:        tcell.StyleDefault.Foreground(Styles.SecondaryTextColor).Background(Styles.ContrastBackgroundColor),
+		currentTabStyle: tcell.StyleDefault.Foreground(Styles.PrimitiveBackgroundColor).Background(Styles.PrimaryTextColor),
+		leftArrow:       -1,
+		rightArrow:      -1,
+	}
+}
+
+// AddTab adds a tab with the given label and content at the end of the row of
+// tabs. The first tab which is added becomes the current tab.
+func (t *Tabs) AddTab(label string, content Primitive) *Tabs {
+	name := strconv.Itoa(t.nextName)
+	t.nextName++
+	t.tabs = append(t.tabs, &tab{label: label, content: content, name: name})
+	t.pages.AddPage(name, content, true, false)
+	if t.current < 0 {
+		t.SetCurrentTab(0)
+	}
+	return t
+}
+
+// RemoveTab removes the tab with the given index. If it was the current tab,
+// the tab which takes its place (or the new last tab) becomes the current tab.
+func (t *Tabs) RemoveTab(index int) *Tabs {
+	if index < 0 || index >= len(t.tabs) {
+		return t
+	}
+	removed := t.tabs[index]
+	t.tabs = append(t.tabs[:index], t.tabs[index+1:]...)
+	t.pages.RemovePage(removed.name)
+	switch {
+	case index < t.current:
+		t.current--
+	case index == t.current:
+		current := t.current
+		if current >= len(t.tabs) {
+			current = len(t.tabs) - 1
+		}
+		t.current = -1
+		t.SetCurrentTab(current)
+	}
+	return t
+}
+
+// GetTabCount returns the number of tabs.
+func (t *Tabs) GetTabCount() int {
+	return len(t.tabs)
+}
+
+// GetTab returns the label and the content of the tab with the given index.
+// If there is no such tab, an empty label and nil are returned.
+func (t *Tabs) GetTab(index int) (label string, content Primitive) {
+	if index < 0 || index >= len(t.tabs) {
+		return "", nil
+	}
+	return t.tabs[index].label, t.tabs[index].content
+}
+
+// SetTabLabel changes the label of the tab with the given index.
+func (t *Tabs) SetTabLabel(index int, label string) *Tabs {
+	if index >= 0 && index < len(t.tabs) {
+		t.tabs[index].label = label
+	}
+	return t
+}
+
+// SetCurrentTab makes the tab with the given index the current tab, showing
+// its content. Indices out of range are clamped.
+func (t *Tabs) SetCurrentTab(index int) *Tabs {
+	if len(t.tabs) == 0 {
+		t.current = -1
+		return t
+	}
+	if index < 0 {
+		index = 0
+	} else if index >= len(t.tabs) {
+		index = len(t.tabs) - 1
+	}
+	if index == t.current {
+		return t
+	}
+	t.current = index
+	current := t.tabs[index]
+	t.pages.SwitchToPage(current.name)
+	if t.changed != nil {
+		t.changed(index, current.label, current.content)
+	}
+	return t
+}
+
+// GetCurrentTab returns the index of the current tab or -1 if there are no
+// tabs.
+func (t *Tabs) GetCurrentTab() int {
+	return t.current
+}
+
+// GetPages returns the Pages primitive which contains the tabs' contents. Its
+// pages are managed by the Tabs primitive and should not be added or removed
+// directly.
+func (t *Tabs) GetPages() *Pages {
+	return t.pages
+}
+
+// SetTabStyle sets the style of the tab labels.
+func (t *Tabs) SetTabStyle(style tcell.Style) *Tabs {
+	t.tabStyle = style
+	t.overrides.set(&t.tabStyle)
+	return t
+}
+
+// SetCurrentTabStyle sets the style of the current tab's label.
+func (t *Tabs) SetCurrentTabStyle(style tcell.Style) *Tabs {
+	t.currentTabStyle = style
+	t.overrides.set(&t.currentTabStyle)
+	return t
+}
+
+// SetChangedFunc sets a handler which is called when the current tab changes.
+// The handler receives the index, the label, and the content of the new
+// current tab.
+func (t *Tabs) SetChangedFunc(handler func(index int, label string, content Primitive)) *Tabs {
+	t.changed = handler
+	return t
+}
+
+// SetTabClosedFunc sets a handler which is called after the user closed a
+// tab, either by clicking on the close glyph drawn after its label or by
+// pressing Delete while the row of tab labels has focus. The handler receives
+// the former index, the label, and the content of the closed tab. Tabs can
+// only be closed by the user while this handler is set, so set it to nil to
+// remove the close glyphs.
+func (t *Tabs) SetTabClosedFunc(handler func(index int, label string, content Primitive)) *Tabs {
+	t.closed = handler
+	return t
+}
+
+// closeTab removes the tab with the given index and notifies the closed
+// handler.
+func (t *Tabs) closeTab(index int) {
+	if t.closed == nil || index < 0 || index >= len(t.tabs) {
+		return
+	}
+	closed := t.tabs[index]
+	t.RemoveTab(index)
+	t.closed(index, closed.label, closed.content)
+}
+
+// labelWidth returns the screen width of the label of the given tab,
+// including the padding and the close glyph.
+func (t *Tabs) labelWidth(tb *tab) int {
+	width := TaggedStringWidth(tb.label) + 2
+	if t.closed != nil {
+		width += 2
+	}
+	return width
+}
+
+// applyTheme implements the themeable interface. The tabs' contents are
+// themed separately.
+func (t *Tabs) applyTheme(from, to *Theme) {
+	t.Box.applyTheme(from, to)
+	t.overrides.style(&t.tabStyle, tcell.StyleDefault.Foreground(to.SecondaryTextColor).Background(to.ContrastBackgroundColor))
+	t.overrides.style(&t.currentTabStyle, tcell.StyleDefault.Foreground(to.PrimitiveBackgroundColor).Background(to.PrimaryTextColor))
+}
+
+// Draw draws this primitive onto the screen.
+func (t *Tabs) Draw(screen tcell.Screen) {
+	t.Box.DrawForSubclass(screen, t)
+	x, y, width, height := t.GetInnerRect()
+	t.tabStart, t.tabEnd = t.tabStart[:0], t.tabEnd[:0]
+	t.leftArrow, t.rightArrow = -1, -1
+	t.tabRow = y
+	if width <= 0 || height <= 0 {
+		return
+	}
+
+	// Draw the content.
+	t.pages.SetRect(x, y+1, width, height-1)
+	t.pages.Draw(screen)
+
+	// Determine which tab labels are visible, scrolling such that the current
+	// tab is.
+	for column := x; column < x+width; column++ {
+		screen.SetContent(column, y, ' ', nil, t.tabStyle)
+	}
+	total := 0
+	for _, tb := range t.tabs {
+		total += t.labelWidth(tb)
+	}
+	start, end := x, x+width
+	if total > width {
+		start, end = x+1, x+width-1 // Leave room for the arrows.
+	} else {
+		t.offset = 0
+	}
+	if t.offset > t.current && t.current >= 0 {
+		t.offset = t.current
+	}
+	for t.offset < t.current {
+		used := 0
+		for index := t.offset; index <= t.current; index++ {
+			used += t.labelWidth(t.tabs[index])
+		}
+		if used <= end-start {
+			break
+		}
+		t.offset++
+	}
+	if t.offset >= len(t.tabs) {
+		t.offset = 0
+	}
+
+	// Draw the tab labels.
+	column := start
+	for index, tb := range t.tabs {
+		if index < t.offset || column >= end {
+			t.tabStart, t.tabEnd = append(t.tabStart, -1), append(t.tabEnd, -1)
+			if column >= end {
+				t.rightArrow = x + width - 1
+			}
+			continue
+		}
+		style := t.tabStyle
+		if index == t.current {
+			style = t.currentTabStyle
+		}
+		labelEnd := column + t.labelWidth(tb)
+		if labelEnd > end {
+			labelEnd = end
+			t.rightArrow = x + width - 1
+		}
+		for c := column; c < labelEnd; c++ {
+			screen.SetContent(c, y, ' ', nil, style)
+		}
+		printWithStyle(screen, tb.label, column+1, y, 0, labelEnd-column-1, AlignLeft, style, true)
+		if t.closed != nil && labelEnd-column == t.labelWidth(tb) {
+			screen.SetContent(labelEnd-2, y, tabsCloseGlyph, nil, style)
+		}
+		t.tabStart, t.tabEnd = append(t.tabStart, column), append(t.tabEnd, labelEnd)
+		column = labelEnd
+	}
+
+	// Draw the overflow arrows.
+	if t.offset > 0 {
+		t.leftArrow = x
+		screen.SetContent(x, y, '◀', nil, t.tabStyle)
+	}
+	if t.rightArrow >= 0 {
+		screen.SetContent(t.rightArrow, y, '▶', nil, t.tabStyle)
+	}
+}
+
+// HasFocus returns whether or not this primitive has focus.
+func (t *Tabs) HasFocus() bool {
+	return t.pages.HasFocus() || t.Box.HasFocus()
+}
+
+// MouseHandler returns the mouse handler for this primitive.
+func (t *Tabs) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+	return t.WrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+		x, y := event.Position()
+		if !t.InRect(x, y) {
+			return false, nil
+		}
+
+		// Pass mouse events on to the content.
+		if y != t.tabRow {
+			return t.pages.MouseHandler()(action, event, setFocus)
+		}
+
+		switch action {
+		case MouseLeftDown:
+			setFocus(t)
+			consumed = true
+		case MouseLeftClick:
+			consumed = true
+			switch {
+			case x == t.leftArrow:
+				t.SetCurrentTab(t.offset - 1) // The last hidden tab on the left.
+			case x == t.rightArrow:
+				for index := t.offset; index < len(t.tabStart); index++ {
+					if t.tabStart[index] < 0 || t.tabEnd[index] > t.rightArrow {
+						t.SetCurrentTab(index) // The first (partly) hidden tab on the right.
+						break
+					}
+				}
+			default:
+				for index := range t.tabs {
+					if index >= len(t.tabStart) || x < t.tabStart[index] || x >= t.tabEnd[index] {
+						continue
+					}
+					if t.closed != nil && x == t.tabEnd[index]-2 && t.tabEnd[index]-t.tabStart[index] == t.labelWidth(t.tabs[index]) {
+						t.closeTab(index)
+					} else {
+						t.SetCurrentTab(index)
+					}
+					break
+				}
+			}
+		case MouseScrollUp:
+			t.SetCurrentTab(t.current - 1)
+			consumed = true
+		case MouseScrollDown:
+			t.SetCurrentTab(t.current + 1)
+			consumed = true
+		}
+		return
+	})
+}
+
+// InputHandler returns the handler for this primitive.
+func (t *Tabs) InputHandler() func(event *tcell.EventKey, setFocus func(p Primitive)) {
+	return t.WrapInputHandler(func(event *tcell.EventKey, setFocus func(p Primitive)) {
+		key := event.Key()
+
+		// Pass key events on to the content.
+		if t.pages.HasFocus() {
+			if event.Modifiers()&tcell.ModCtrl != 0 && (key == tcell.KeyPgUp || key == tcell.KeyPgDn) {
+				// The Pages primitive moves the focus to the new content.
+				if key == tcell.KeyPgUp {
+					t.SetCurrentTab(t.current - 1)
+				} else {
+					t.SetCurrentTab(t.current + 1)
+				}
+				return
+			}
+			if handler := t.pages.InputHandler(); handler != nil {
+				handler(event, setFocus)
+			}
+			return
+		}
+
+		switch key {
+		case tcell.KeyLeft:
+			t.SetCurrentTab(t.current - 1)
+		case tcell.KeyRight:
+			t.SetCurrentTab(t.current + 1)
+		case tcell.KeyHome:
+			t.SetCurrentTab(0)
+		case tcell.KeyEnd:
+			t.SetCurrentTab(len(t.tabs) - 1)
+		case tcell.KeyEnter, tcell.KeyDown:
+			if t.current >= 0 {
+				setFocus(t.pages)
+			}
+		case tcell.KeyDelete:
+			t.closeTab(t.current)
+		}
+	})
+}
@@ -3,6 +3,7 @@ package tview
 import (
 	"fmt"
 	"strings"
+	"time"
 	"unicode"
 	"unicode/utf8"
 
@@ -39,6 +40,19 @@ const (
 	taActionDelete                // Deleting the next character.
 )
 
+// StyledRange describes a range of text in one line of a TextArea which is to
+// be drawn in a specific style. From and To are byte offsets into the line
+// text (To being exclusive). See [TextArea.SetHighlightFunc].
+type StyledRange struct {
+	From, To int
+	Style    tcell.Style
+}
+
+// TextAreaUndoInterval is the maximum time between two consecutively typed
+// characters for them to be reverted together by a single undo. A value of 0
+// groups them regardless of the time between them.
+var TextAreaUndoInterval = time.Second
+
 // NewLine is the string sequence to be inserted when hitting the Enter key in a
 // TextArea. The default is "\n" but you may change it to "\r\n" if required.
 var NewLine = "\n"
@@ -168,11 +182,11 @@ type textAreaUndoItem struct {
 // using your operating system's or terminal's own methods may be very slow as
 // each character will be pasted individually.
 //
-// The default clipboard is an internal text buffer, i.e. the operating system's
-// clipboard is not used. If you want to implement your own clipboard (or make
-// use of your operating system's clipboard), you can use
-// [TextArea.SetClipboard] which  provides all the functionality needed to
-// implement your own clipboard.
+// By default, the text area uses the clipboard shared by all primitives which
+// is an internal text buffer, i.e. the operating system's clipboard is not
+// used. To use the operating system's clipboard for all primitives, see
+// [Application.SetClipboard]. To give only this text area its own clipboard,
+// use [TextArea.SetClipboard].
 //
 // The text area also supports Undo:
 //
@@ -208,6 +222,20 @@ type TextArea struct {
 	// The style of the placeholder text.
 	placeholderStyle tcell.Style
 
+	// An optional function which returns the styled ranges of a line of text,
+	// e.g. for syntax highlighting.
+	highlight func(line string) []StyledRange
+
+	// If set to true, line numbers are shown in a gutter left of the text.
+	lineNumbers bool
+
+	// The style of the line number gutter.
+	lineNumberStyle tcell.Style
+
+	// The width of the line number gutter the last time the text area was
+	// drawn. 0 if there is no gutter.
+	gutterWidth int
+
 	// Text manipulation related fields:
 
 	// The text area's text prior to any editing. It is referenced by spans with
@@ -281,9 +309,6 @@ type TextArea struct {
 
 	// Clipboard related fields:
 
-	// The internal clipboard.
-	clipboard string
-
 	// The function to call when the user copies/cuts a text selection to the
 	// clipboard.
 	copyToClipboard func(string)
@@ -307,6 +332,14 @@ type TextArea struct {
 	// been performed yet, this is the same as len(undoStack).
 	nextUndo int
 
+	// The maximum number of undo steps kept on the undo stack. 0 means there
+	// is no limit.
+	maxUndoSteps int
+
+	// The time of the last edit, used to decide whether an edit continues the
+	// previous undo step.
+	lastEdit time.Time
+
 	// Event handlers:
 
 	// An optional function which is called when the input has changed.
@@ -327,6 +360,7 @@ func NewTextArea() *TextArea {
 		placeholderStyle: tcell.StyleDefault.Background(Styles.PrimitiveBackgroundColor).Foreground(Styles.TertiaryTextColor),
 		textStyle:        tcell.StyleDefault.Background(Styles.PrimitiveBackgroundColor).Foreground(Styles.PrimaryTextColor),
 		selectedStyle:    tcell.StyleDefault.Background(Styles.PrimaryTextColor).Foreground(Styles.PrimitiveBackgroundColor),
+		lineNumberStyle:  tcell.StyleDefault.Background(Styles.PrimitiveBackgroundColor).Foreground(Styles.TertiaryTextColor),
 		spans:            make([]textAreaSpan, 2, pieceChainMinCap), // We reserve some space to avoid reallocations right when editing starts.
 		lastAction:       taActionOther,
 	}
@@ -706,18 +740,53 @@ func (t *TextArea) SetMaxLength(maxLength int) *TextArea {
 // Box's background color may lead to unwanted artefacts.
 func (t *TextArea) SetTextStyle(style tcell.Style) *TextArea {
 	t.textStyle = style
+	t.overrides.set(&t.textStyle)
 	return t
 }
 
 // SetSelectedStyle sets the style of the selected text.
 func (t *TextArea) SetSelectedStyle(style tcell.Style) *TextArea {
 	t.selectedStyle = style
+	t.overrides.set(&t.selectedStyle)
 	return t
 }
 
 // SetPlaceholderStyle sets the style of the placeholder text.
 func (t *TextArea) SetPlaceholderStyle(style tcell.Style) *TextArea {
 	t.placeholderStyle = style
+	t.overrides.set(&t.placeholderStyle)
+	return t
+}
+
+// SetHighlightFunc sets a function which determines the styles of the text,
+// e.g. for syntax highlighting. It is called for each line of text which is at
+// least partly visible whenever the text area is drawn. The line text does not
+// include the trailing newline. Lines broken over due to wrapping are passed in
+// their entirety. The function returns the ranges of the line which are to be
+// drawn in a style other than the text style, with positions being byte
+// offsets into the line text. Where ranges overlap, later ranges take
+// precedence. Highlighted text keeps its style when it is selected, only text
+// without highlighting is drawn in the selection style.
+//
+// The function must not modify the text area. Set it to nil to draw all text
+// in the text style.
+func (t *TextArea) SetHighlightFunc(handler func(line string) []StyledRange) *TextArea {
+	t.highlight = handler
+	return t
+}
+
+// SetLineNumbers sets whether line numbers are shown in a gutter on the left
+// side of the text area. The gutter grows with the number of lines in the text.
+// Rows which result from wrapping a line do not receive a number.
+func (t *TextArea) SetLineNumbers(show bool) *TextArea {
+	t.lineNumbers = show
+	return t
+}
+
+// SetLineNumberStyle sets the style of the line number gutter.
+func (t *TextArea) SetLineNumberStyle(style tcell.Style) *TextArea {
+	t.lineNumberStyle = style
+	t.overrides.set(&t.lineNumberStyle)
 	return t
 }
 
@@ -742,20 +811,20 @@ func (t *TextArea) SetOffset(row, column int) *TextArea {
 // (copyToClipboard) and a function that is called when the user wishes to
 // retrieve text from the clipboard (pasteFromClipboard).
 //
-// Providing nil values will cause the default clipboard implementation to be
-// used.
+// Providing nil values will cause the clipboard shared by all primitives to be
+// used (see [Application.SetClipboard]).
 func (t *TextArea) SetClipboard(copyToClipboard func(string), pasteFromClipboard func() string) *TextArea {
 	t.copyToClipboard = copyToClipboard
 	if t.copyToClipboard == nil {
 		t.copyToClipboard = func(text string) {
-			t.clipboard = text
+			getClipboard().Set(text)
 		}
 	}
 
 	t.pasteFromClipboard = pasteFromClipboard
 	if t.pasteFromClipboard == nil {
 		t.pasteFromClipboard = func() string {
-			return t.clipboard
+			return getClipboard().Get()
 		}
 	}
 
@@ -799,6 +868,13 @@ func (t *TextArea) replace(deleteStart, deleteEnd [3]int, insert string, continu
 		defer t.changed()
 	}
 
+	// Edits which are too far apart are not grouped into one undo step.
+	now := time.Now()
+	if continuation && TextAreaUndoInterval > 0 && now.Sub(t.lastEdit) > TextAreaUndoInterval {
+		continuation = false
+	}
+	t.lastEdit = now
+
 	// Handle a few cases where we don't put anything onto the undo stack for
 	// increased efficiency.
 	if continuation {
@@ -860,6 +936,7 @@ func (t *TextArea) replace(deleteStart, deleteEnd [3]int, insert string, continu
 	t.spans = append(t.spans, t.spans[before])
 	t.spans = append(t.spans, t.spans[after])
 	t.nextUndo++
+	t.trimUndoStack()
 
 	// Adjust total text length by subtracting everything between "before" and
 	// "after". Inserted spans will be added back.
@@ -942,13 +1019,31 @@ func (t *TextArea) replace(deleteStart, deleteEnd [3]int, insert string, continu
 	return deleteEnd
 }
 
+// applyTheme implements the themeable interface.
+func (t *TextArea) applyTheme(from, to *Theme) {
+	t.Box.applyTheme(from, to)
+	t.overrides.style(&t.placeholderStyle, tcell.StyleDefault.Background(to.PrimitiveBackgroundColor).Foreground(to.TertiaryTextColor))
+	t.overrides.style(&t.textStyle, tcell.StyleDefault.Background(to.PrimitiveBackgroundColor).Foreground(to.PrimaryTextColor))
+	t.overrides.style(&t.selectedStyle, tcell.StyleDefault.Background(to.PrimaryTextColor).Foreground(to.PrimitiveBackgroundColor))
+	t.overrides.style(&t.lineNumberStyle, tcell.StyleDefault.Background(to.PrimitiveBackgroundColor).Foreground(to.TertiaryTextColor))
+}
+
 // Draw draws this primitive onto the screen.
 func (t *TextArea) Draw(screen tcell.Screen) {
 	t.Box.DrawForSubclass(screen, t)
 
 	// Prepare
 	x, y, width, height := t.GetInnerRect()
-	if width == 0 || height == 0 {
+	t.gutterWidth = 0
+	if t.lineNumbers {
+		t.gutterWidth = len(fmt.Sprint(t.countLines())) + 1
+		if t.gutterWidth >= width {
+			t.gutterWidth = 0
+		}
+		x += t.gutterWidth
+		width -= t.gutterWidth
+	}
+	if width <= 0 || height == 0 {
 		return // We have no space for anything.
 	}
 	columnOffset := t.columnOffset
@@ -974,6 +1069,11 @@ func (t *TextArea) Draw(screen tcell.Screen) {
 		}
 	}()
 
+	// An empty text still has one line.
+	if t.gutterWidth > 0 && t.length == 0 {
+		t.drawLineNumber(screen, x-t.gutterWidth, y, 1)
+	}
+
 	// Placeholder.
 	if t.length == 0 && len(t.placeholder) > 0 {
 		t.drawPlaceholder(screen, x, y, width, height)
@@ -991,6 +1091,11 @@ func (t *TextArea) Draw(screen tcell.Screen) {
 		return // It's scrolled out of view.
 	}
 
+	// Draw line numbers.
+	if t.gutterWidth > 0 && t.length > 0 {
+		t.drawLineNumbers(screen, x-t.gutterWidth, y, height)
+	}
+
 	// If the cursor position is unknown, find it. This usually only happens
 	// before the screen is drawn for the first time.
 	if t.cursor.row < 0 {
@@ -1003,8 +1108,17 @@ func (t *TextArea) Draw(screen tcell.Screen) {
 		}
 	}
 
+	// Determine syntax highlighting.
+	var highlights []*tcell.Style
+	if t.highlight != nil {
+		highlights = t.highlightRows(t.rowOffset, t.rowOffset+height)
+	}
+
 	// Print the text.
-	var cluster, text string
+	var (
+		cluster, text string
+		clusterIndex  int
+	)
 	line := t.rowOffset
 	pos := t.lineStarts[line]
 	endPos := pos
@@ -1027,6 +1141,10 @@ func (t *TextArea) Draw(screen tcell.Screen) {
 			fromRow == line && fromColumn > posX {
 			style = t.textStyle
 		}
+		if clusterIndex < len(highlights) && highlights[clusterIndex] != nil {
+			style = *highlights[clusterIndex] // Highlights take precedence over the selection.
+		}
+		clusterIndex++
 
 		// Draw character.
 		if posX+clusterWidth-columnOffset <= width && posX-columnOffset >= 0 && clusterWidth > 0 {
@@ -1047,6 +1165,136 @@ func (t *TextArea) Draw(screen tcell.Screen) {
 	}
 }
 
+// countLines returns the number of lines in the text, i.e. the number of
+// newline characters plus one.
+func (t *TextArea) countLines() int {
+	lines := 1
+	editText := t.editText.String()
+	for spanIndex := t.spans[0].next; spanIndex != 1; spanIndex = t.spans[spanIndex].next {
+		span := &t.spans[spanIndex]
+		if span.length < 0 {
+			lines += strings.Count(t.initialText[span.offset:span.offset-span.length], "\n")
+		} else {
+			lines += strings.Count(editText[span.offset:span.offset+span.length], "\n")
+		}
+	}
+	return lines
+}
+
+// drawLineNumbers draws the numbers of the lines which start in the visible
+// rows into the gutter at the given position. It is assumed that
+// [TextArea.lineStarts] contains the visible rows.
+func (t *TextArea) drawLineNumbers(screen tcell.Screen, x, y, height int) {
+	// Find the number of the first visible line.
+	number := 1
+	for row := 0; row < t.rowOffset; row++ {
+		if t.endsWithLineBreak(row) {
+			number++
+		}
+	}
+
+	for row := t.rowOffset; row < t.rowOffset+height && row < len(t.lineStarts); row++ {
+		if row > 0 && !t.endsWithLineBreak(row-1) {
+			continue // A wrapped row.
+		}
+		if row > t.rowOffset {
+			number++
+		}
+		t.drawLineNumber(screen, x, y+row-t.rowOffset, number)
+	}
+}
+
+// drawLineNumber draws a single line number into the gutter at the given
+// position, right-aligned.
+func (t *TextArea) drawLineNumber(screen tcell.Screen, x, y, number int) {
+	text := fmt.Sprintf("%*d ", t.gutterWidth-1, number)
+	for index, ch := range text {
+		screen.SetContent(x+index, y, ch, nil, t.lineNumberStyle)
+	}
+}
+
+// highlightRows calls the highlight function for all lines of text which are
+// at least partly contained in the given range of rows (toRow exclusive) and
+// returns the resulting styles of the grapheme clusters in those rows, in the
+// order of the text, starting with the first cluster of fromRow. A nil element
+// indicates that the cluster has no highlighting. It is assumed that
+// [TextArea.lineStarts] contains fromRow.
+func (t *TextArea) highlightRows(fromRow, toRow int) (styles []*tcell.Style) {
+	// Find the start of the line which contains the first row.
+	row := fromRow
+	for row > 0 && !t.endsWithLineBreak(row-1) {
+		row--
+	}
+
+	// Collect the lines and call the highlight function for each.
+	var (
+		cluster, text string
+		boundaries    int
+		lineText      strings.Builder
+		lineClusters  []int // The byte offsets of the clusters within the line, -1 for line breaks.
+	)
+	pos := t.lineStarts[row]
+	endPos := pos
+	for pos[0] != 1 {
+		cluster, text, boundaries, _, pos, endPos = t.step(text, pos, endPos)
+		if row >= fromRow {
+			if uniseg.HasTrailingLineBreakInString(cluster) {
+				lineClusters = append(lineClusters, -1)
+			} else {
+				lineClusters = append(lineClusters, lineText.Len())
+			}
+		}
+		if !uniseg.HasTrailingLineBreakInString(cluster) {
+			lineText.WriteString(cluster)
+		}
+		if row < toRow && row+1 < len(t.lineStarts) && t.lineStarts[row+1] == pos {
+			row++
+		}
+
+		// Highlight the line when we reach its end.
+		lineEnd := pos[0] == 1 || boundaries&uniseg.MaskLine == uniseg.LineMustBreak && (len(text) > 0 || uniseg.HasTrailingLineBreakInString(cluster))
+		if !lineEnd {
+			continue
+		}
+		ranges := t.highlight(lineText.String())
+		for _, offset := range lineClusters {
+			var style *tcell.Style
+			for index := range ranges {
+				if offset >= 0 && offset >= ranges[index].From && offset < ranges[index].To {
+					style = &ranges[index].Style
+				}
+			}
+			styles = append(styles, style)
+		}
+		lineText.Reset()
+		lineClusters = lineClusters[:0]
+		if row >= toRow {
+			break // No more visible rows.
+		}
+	}
+
+	return
+}
+
+// endsWithLineBreak returns whether the given row ends with a hard line break
+// (as opposed to being broken over due to wrapping). It is assumed that
+// [TextArea.lineStarts] contains the given row.
+func (t *TextArea) endsWithLineBreak(row int) bool {
+	var (
+		cluster, text string
+		boundaries    int
+	)
+	pos := t.lineStarts[row]
+	endPos := pos
+	for pos[0] != 1 {
+		cluster, text, boundaries, _, pos, endPos = t.step(text, pos, endPos)
+		if row+1 < len(t.lineStarts) && t.lineStarts[row+1] == pos {
+			break
+		}
+	}
+	return boundaries&uniseg.MaskLine == uniseg.LineMustBreak && (len(text) > 0 || uniseg.HasTrailingLineBreakInString(cluster))
+}
+
 // drawPlaceholder draws the placeholder text into the given rectangle. It does
 // not do anything if the text area already contains text or if there is no
 // placeholder text.
@@ -1663,6 +1911,19 @@ func (t *TextArea) getSelection() ([3]int, [3]int, int) {
 	return from, to, row
 }
 
+// paste replaces the current selection with the given text or, if no text is
+// selected, inserts it at the cursor position. This is used for both pasting
+// from the clipboard and bracketed paste so that both are undone in the same
+// way.
+func (t *TextArea) paste(text string) {
+	from, to, row := t.getSelection()
+	t.cursor.pos = t.replace(from, to, text, false)
+	t.cursor.row = -1
+	t.truncateLines(row - 1)
+	t.findCursor(true, row)
+	t.selectionStart = t.cursor
+}
+
 // getSelectedText returns the text of the current selection.
 func (t *TextArea) getSelectedText() string {
 	var text strings.Builder
@@ -2011,60 +2272,143 @@ func (t *TextArea) InputHandler() func(event *tcell.EventKey, setFocus func(p Pr
 				t.selectionStart = t.cursor
 			}
 		case tcell.KeyCtrlV: // Paste from clipboard.
-			from, to, row := t.getSelection()
-			t.cursor.pos = t.replace(from, to, t.pasteFromClipboard(), false)
-			t.cursor.row = -1
-			t.truncateLines(row - 1)
-			t.findCursor(true, row)
-			t.selectionStart = t.cursor
+			t.paste(t.pasteFromClipboard())
 		case tcell.KeyCtrlZ: // Undo.
-			if t.nextUndo <= 0 {
-				break
-			}
-			for t.nextUndo > 0 {
-				t.nextUndo--
-				undo := t.undoStack[t.nextUndo]
-				t.spans[undo.originalBefore], t.spans[undo.before] = t.spans[undo.before], t.spans[undo.originalBefore]
-				t.spans[undo.originalAfter], t.spans[undo.after] = t.spans[undo.after], t.spans[undo.originalAfter]
-				t.cursor.pos, t.undoStack[t.nextUndo].pos = undo.pos, t.cursor.pos
-				t.length, t.undoStack[t.nextUndo].length = undo.length, t.length
-				if !undo.continuation {
-					break
-				}
-			}
-			t.cursor.row = -1
-			t.truncateLines(0) // This is why Undo is expensive for large texts. (t.lineStarts can get largely unusable after an undo.)
-			t.findCursor(true, 0)
-			t.selectionStart = t.cursor
-			if t.changed != nil {
-				defer t.changed()
-			}
+			t.undo()
 		case tcell.KeyCtrlY: // Redo.
-			if t.nextUndo >= len(t.undoStack) {
-				break
-			}
-			for t.nextUndo < len(t.undoStack) {
-				undo := t.undoStack[t.nextUndo]
-				t.spans[undo.originalBefore], t.spans[undo.before] = t.spans[undo.before], t.spans[undo.originalBefore]
-				t.spans[undo.originalAfter], t.spans[undo.after] = t.spans[undo.after], t.spans[undo.originalAfter]
-				t.cursor.pos, t.undoStack[t.nextUndo].pos = undo.pos, t.cursor.pos
-				t.length, t.undoStack[t.nextUndo].length = undo.length, t.length
-				t.nextUndo++
-				if t.nextUndo < len(t.undoStack) && !t.undoStack[t.nextUndo].continuation {
-					break
-				}
-			}
-			t.cursor.row = -1
-			t.truncateLines(0) // This is why Redo is expensive for large texts. (t.lineStarts can get largely unusable after an undo.)
-			t.findCursor(true, 0)
-			t.selectionStart = t.cursor
-			if t.changed != nil {
-				defer t.changed()
-			}
+			t.redo()
 		}
 	})
 }
 
+// Undo reverts the last change to the text, if any. Consecutive typed
+// characters are reverted together (see [TextArea.SetMaxUndoSteps]). This is
+// the same as pressing Ctrl-Z. The changed handler is called if the text
+// changed.
+func (t *TextArea) Undo() *TextArea {
+	if t.undo() && t.moved != nil {
+		t.moved()
+	}
+	return t
+}
+
+// Redo reapplies the last change reverted with [TextArea.Undo], if any. This
+// is the same as pressing Ctrl-Y. The changed handler is called if the text
+// changed.
+func (t *TextArea) Redo() *TextArea {
+	if t.redo() && t.moved != nil {
+		t.moved()
+	}
+	return t
+}
+
+// CanUndo returns whether there is a change which can be reverted with
+// [TextArea.Undo].
+func (t *TextArea) CanUndo() bool {
+	return t.nextUndo > 0
+}
+
+// CanRedo returns whether there is a reverted change which can be reapplied
+// with [TextArea.Redo].
+func (t *TextArea) CanRedo() bool {
+	return t.nextUndo < len(t.undoStack)
+}
+
+// SetMaxUndoSteps sets the maximum number of undo steps which are kept. A step
+// is a group of changes reverted together, e.g. consecutively typed characters.
+// Typed characters are no longer grouped once more than [TextAreaUndoInterval]
+// has passed since the previous edit. Pastes and deletions of a selection are
+// always separate steps. When the limit is exceeded, the oldest steps are
+// dropped. A value of 0 (the default) means that there is no limit.
+func (t *TextArea) SetMaxUndoSteps(steps int) *TextArea {
+	if steps < 0 {
+		steps = 0
+	}
+	t.maxUndoSteps = steps
+	t.trimUndoStack()
+	return t
+}
+
+// trimUndoStack drops the oldest undo steps until there are no more than the
+// maximum number of undo steps.
+func (t *TextArea) trimUndoStack() {
+	if t.maxUndoSteps <= 0 {
+		return
+	}
+	var steps int
+	for _, undo := range t.undoStack {
+		if !undo.continuation {
+			steps++
+		}
+	}
+	for ; steps > t.maxUndoSteps; steps-- {
+		drop := 1
+		for drop < len(t.undoStack) && t.undoStack[drop].continuation {
+			drop++
+		}
+		if drop > t.nextUndo {
+			break // Don't drop steps which were undone.
+		}
+		t.undoStack = append(t.undoStack[:0], t.undoStack[drop:]...)
+		t.nextUndo -= drop
+	}
+}
+
+// undo reverts the last undo step. It returns whether anything was reverted.
+func (t *TextArea) undo() bool {
+	if t.nextUndo <= 0 {
+		return false
+	}
+	for t.nextUndo > 0 {
+		t.nextUndo--
+		undo := t.undoStack[t.nextUndo]
+		t.spans[undo.originalBefore], t.spans[undo.before] = t.spans[undo.before], t.spans[undo.originalBefore]
+		t.spans[undo.originalAfter], t.spans[undo.after] = t.spans[undo.after], t.spans[undo.originalAfter]
+		t.cursor.pos, t.undoStack[t.nextUndo].pos = undo.pos, t.cursor.pos
+		t.length, t.undoStack[t.nextUndo].length = undo.length, t.length
+		if !undo.continuation {
+			break
+		}
+	}
+	t.cursor.row = -1
+	t.truncateLines(0) // This is why Undo is expensive for large texts. (t.lineStarts can get largely unusable after an undo.)
+	t.findCursor(true, 0)
+	t.selectionStart = t.cursor
+	t.lastAction = taActionOther
+	if t.changed != nil {
+		t.changed()
+	}
+	return true
+}
+
+// redo reapplies the last undone undo step. It returns whether anything was
+// reapplied.
+func (t *TextArea) redo() bool {
+	if t.nextUndo >= len(t.undoStack) {
+		return false
+	}
+	for t.nextUndo < len(t.undoStack) {
+		undo := t.undoStack[t.nextUndo]
+		t.spans[undo.originalBefore], t.spans[undo.before] = t.spans[undo.before], t.spans[undo.originalBefore]
+		t.spans[undo.originalAfter], t.spans[undo.after] = t.spans[undo.after], t.spans[undo.originalAfter]
+		t.cursor.pos, t.undoStack[t.nextUndo].pos = undo.pos, t.cursor.pos
+		t.length, t.undoStack[t.nextUndo].length = undo.length, t.length
+		t.nextUndo++
+		if t.nextUndo < len(t.undoStack) && !t.undoStack[t.nextUndo].continuation {
+			break
+		}
+	}
+	t.cursor.row = -1
+	t.truncateLines(0) // This is why Redo is expensive for large texts. (t.lineStarts can get largely unusable after an undo.)
+	t.findCursor(true, 0)
+	t.selectionStart = t.cursor
+	t.lastAction = taActionOther
+	if t.changed != nil {
+		t.changed()
+	}
+	return true
+}
+
 // THIS FUNCTION WILL BE REMOVED ONCE WE DEEM THE TEXT AREA STABLE! DO NOT USE!
 func (t *TextArea) Dump() string {
 	var buf strings.Builder
@@ -2122,6 +2466,21 @@ func (t *TextArea) Dump() string {
 	return buf.String()
 }
 
+// OnPaste is called when a bracketed paste is finished. The pasted text
+// replaces the current selection. If a handler was installed with
+// [Box.SetOnPaste], that handler is called instead.
+func (t *TextArea) OnPaste(runes []rune) {
+	if t.onPaste != nil {
+		t.onPaste(runes)
+		return
+	}
+	t.lastAction = taActionOther
+	t.paste(string(runes))
+	if t.moved != nil {
+		t.moved()
+	}
+}
+
 // MouseHandler returns the mouse handler for this primitive.
 func (t *TextArea) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
 	return t.WrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
@@ -2130,6 +2489,7 @@ func (t *TextArea) MouseHandler() func(action MouseAction, event *tcell.EventMou
 		if !t.InRect(x, y) {
 			return false, nil
 		}
+		rectX += t.gutterWidth
 
 		// Trigger a "moved" event at the end if requested.
 		if t.moved != nil {
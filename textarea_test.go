@@ -0,0 +1,68 @@
+package tview
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestTextAreaHighlightFunc(t *testing.T) {
+	screen := newTestScreen(t, 20, 3)
+	keyword := tcell.StyleDefault.Foreground(tcell.ColorYellow).Bold(true)
+	var lines []string
+	textArea := NewTextArea().SetText("if x then\ngo if", false).
+		SetHighlightFunc(func(line string) []StyledRange {
+			lines = append(lines, line)
+			var ranges []StyledRange
+			for offset := strings.Index(line, "if"); offset >= 0; {
+				ranges = append(ranges, StyledRange{From: offset, To: offset + 2, Style: keyword})
+				next := strings.Index(line[offset+2:], "if")
+				if next < 0 {
+					break
+				}
+				offset += 2 + next
+			}
+			return ranges
+		})
+	textArea.SetRect(0, 0, 20, 3)
+	textArea.Draw(screen)
+
+	// The function receives every visible line without the line break.
+	if strings.Join(lines, "|") != "if x then|go if" {
+		t.Errorf("highlight function received %q", lines)
+	}
+
+	// Highlighted ranges are drawn in their style, the rest in the text style.
+	styleAt := func(x, y int) tcell.Style {
+		_, _, style, _ := screen.GetContent(x, y)
+		return style
+	}
+	for _, pos := range [][2]int{{0, 0}, {1, 0}, {3, 1}, {4, 1}} {
+		if style := styleAt(pos[0], pos[1]); style != keyword {
+			t.Errorf("cell %v has style %v, expected the keyword style", pos, style)
+		}
+	}
+	for _, pos := range [][2]int{{3, 0}, {0, 1}} {
+		if style := styleAt(pos[0], pos[1]); style != textArea.textStyle {
+			t.Errorf("cell %v has style %v, expected the text style", pos, style)
+		}
+	}
+
+	// Highlights stay on top of the selection.
+	textArea.Select(0, 4)
+	textArea.Draw(screen)
+	if style := styleAt(0, 0); style != keyword {
+		t.Errorf("selected keyword has style %v, expected the keyword style", style)
+	}
+	if style := styleAt(3, 0); style != textArea.selectedStyle {
+		t.Errorf("selected text has style %v, expected the selection style", style)
+	}
+
+	// Without the function, all text is drawn in the text style.
+	textArea.SetHighlightFunc(nil).Select(0, 0)
+	textArea.Draw(screen)
+	if style := styleAt(0, 0); style != textArea.textStyle {
+		t.Errorf("cell without highlighting has style %v, expected the text style", style)
+	}
+}
@@ -3,9 +3,12 @@ package tview
 import (
 	"bytes"
 	"fmt"
+	"io"
 	"regexp"
+	"sort"
 	"strings"
 	"sync"
+	"unicode"
 	"unicode/utf8"
 
 	"github.com/gdamore/tcell/v2"
@@ -33,6 +36,8 @@ type textViewIndex struct {
 	BackgroundColor string // The starting background color ("" = don't change, "-" = reset).
 	Attributes      string // The starting attributes ("" = don't change, "-" = reset).
 	Region          string // The starting region ID.
+	URL             string // The starting link URL ("" = no link).
+	Hyphen          bool   // Whether a hyphen is drawn after this line because it breaks a word.
 }
 
 // textViewRegion contains information about a region.
@@ -45,6 +50,30 @@ type textViewRegion struct {
 	FromX, FromY, ToX, ToY int
 }
 
+// textViewLink is a part of a link which was visible on one row of the screen
+// during the last call to Draw().
+type textViewLink struct {
+	URL   string
+	X, Y  int
+	Width int
+}
+
+// SearchOptions control how TextView.Search() interprets its pattern.
+type SearchOptions struct {
+	// If set to true, upper and lower case letters are distinguished.
+	CaseSensitive bool
+
+	// If set to true, the pattern is a regular expression (see the regexp
+	// package for the syntax). Otherwise, the pattern is matched literally.
+	Regexp bool
+}
+
+// textViewMatch is a search match within a text view.
+type textViewMatch struct {
+	Line     int // The index into the "buffer" slice.
+	From, To int // The byte range of the match within the buffer line with all tags stripped.
+}
+
 // TextViewWriter is a writer that can be used to write to and clear a TextView
 // in batches, i.e. multiple writes with the lock only being aquired once. Don't
 // instantiated this class directly but use the TextView's BatchWriter method
@@ -106,6 +135,17 @@ func (w TextViewWriter) HasFocus() bool {
 // the same way as anywhere else. Please see the package documentation for more
 // information.
 //
+// Links
+//
+// With dynamic colors enabled, the fourth field of a color tag turns the
+// following text into a hyperlink, for example:
+//
+//   See [:::https://example.com]the website[:::-] for details.
+//
+// Terminals which support OSC 8 hyperlinks make such links clickable. If a
+// handler was installed with SetLinkClickedFunc(), clicking on a link with the
+// mouse invokes the handler instead. Links may span multiple lines.
+//
 // Regions and Highlights
 //
 // If regions are enabled via SetRegions(), you can define text regions within
@@ -127,6 +167,13 @@ func (w TextViewWriter) HasFocus() bool {
 // The ScrollToHighlight() function can be used to jump to the currently
 // highlighted region once when the text view is drawn the next time.
 //
+// Search
+//
+// Search() finds all occurrences of a pattern in the text. The matches are
+// drawn with a special style, independently of any regions and highlights.
+// NextMatch() and PrevMatch() move between the matches, centering the current
+// match in the text view.
+//
 // Large Texts
 //
 // This widget is not designed for very large texts as word wrapping, color and
@@ -155,6 +202,9 @@ type TextView struct {
 	// Information about visible regions as of the last call to Draw().
 	regionInfos []*textViewRegion
 
+	// The visible parts of links as of the last call to Draw().
+	linkInfos []textViewLink
+
 	// Indices into the "index" slice which correspond to the first line of the
 	// first highlight and the last line of the last highlight. This is calculated
 	// during re-indexing. Set to -1 if there is no current highlight.
@@ -179,6 +229,10 @@ type TextView struct {
 	// If set to true, the text view will always remain at the end of the content.
 	trackEnd bool
 
+	// If set to true, trackEnd is set again whenever the user scrolls back to
+	// the end of the content.
+	following bool
+
 	// The number of characters to be skipped on each line (not in wrap mode).
 	columnOffset int
 
@@ -202,6 +256,10 @@ type TextView struct {
 	// after punctuation characters.
 	wordWrap bool
 
+	// If set to true and if word wrapping is enabled, words which are longer
+	// than the available width are broken with a hyphen.
+	hyphenate bool
+
 	// The (starting) color of the text.
 	textColor tcell.Color
 
@@ -232,21 +290,70 @@ type TextView struct {
 	// highlighted.
 	highlighted func(added, removed, remaining []string)
 
+	// An optional function which is called when the user clicks on a link.
+	linkClicked func(url string)
+
   styler Styler
+
+	// The text selected with the mouse, from the anchor where the left mouse
+	// button was pressed to the position where it was released (exclusive).
+	// Positions are lines of the index and screen columns within those lines.
+	// The selection is empty if both positions are the same.
+	selectFromLine, selectFromColumn int
+	selectToLine, selectToColumn     int
+
+	// Set to true while the user drags the mouse to select text.
+	selecting bool
+
+	// The style of selected text.
+	selectedStyle tcell.Style
+
+	// The current search pattern or nil if there is no search.
+	searchPattern *regexp.Regexp
+
+	// The matches of the search pattern, in the order they appear in the text.
+	searchMatches []textViewMatch
+
+	// The index of the current match in searchMatches. Set to -1 if there is
+	// no current match.
+	currentMatch int
+
+	// A temporary flag which, when true, will bring the current match into the
+	// visible screen.
+	scrollToMatch bool
+
+	// The styles of search matches and of the current match.
+	matchStyle, currentMatchStyle tcell.Style
+
+	// An optional function which returns the IDs of the regions to highlight
+	// for the word under the cursor.
+	dynamicHighlight func(word string) []string
+
+	// Set to true once the user placed the cursor by clicking into the text.
+	// The cursor is at the end of the selection.
+	hasCursor bool
+
+	// The word under the cursor for which the dynamic highlights were last
+	// determined.
+	dynamicWord string
 }
 
 // NewTextView returns a new text view.
 func NewTextView() *TextView {
 	return &TextView{
-		Box:           NewBox(),
-		highlights:    make(map[string]struct{}),
-		lineOffset:    -1,
-		scrollable:    true,
-		align:         AlignLeft,
-		wrap:          true,
-		textColor:     Styles.PrimaryTextColor,
-		regions:       false,
-		dynamicColors: false,
+		Box:               NewBox(),
+		highlights:        make(map[string]struct{}),
+		lineOffset:        -1,
+		scrollable:        true,
+		align:             AlignLeft,
+		wrap:              true,
+		textColor:         Styles.PrimaryTextColor,
+		regions:           false,
+		dynamicColors:     false,
+		selectedStyle:     tcell.StyleDefault.Foreground(Styles.PrimitiveBackgroundColor).Background(Styles.PrimaryTextColor),
+		currentMatch:      -1,
+		matchStyle:        tcell.StyleDefault.Foreground(Styles.PrimitiveBackgroundColor).Background(Styles.SecondaryTextColor),
+		currentMatchStyle: tcell.StyleDefault.Foreground(Styles.PrimitiveBackgroundColor).Background(Styles.TertiaryTextColor),
 	}
 }
 
@@ -274,7 +381,9 @@ func (t *TextView) SetWrap(wrap bool) *TextView {
 
 // SetWordWrap sets the flag that, if true and if the "wrap" flag is also true
 // (see SetWrap()), wraps the line at spaces or after punctuation marks. Note
-// that trailing spaces will not be printed.
+// that trailing spaces will not be printed. Words which are longer than the
+// available width are broken at the last character which fits, optionally
+// with a hyphen (see SetHyphenation()).
 //
 // This flag is ignored if the "wrap" flag is false.
 func (t *TextView) SetWordWrap(wrapOnWords bool) *TextView {
@@ -285,17 +394,38 @@ func (t *TextView) SetWordWrap(wrapOnWords bool) *TextView {
 	return t
 }
 
+// SetHyphenation sets the flag that, if true and if word wrapping is enabled
+// (see SetWordWrap()), breaks words which don't fit into a single line with a
+// hyphen. Only words made of letters which take up one cell are hyphenated.
+// Other text, e.g. CJK characters, is broken at the last character which fits.
+// At least two cells of width are required for a hyphen.
+func (t *TextView) SetHyphenation(hyphenate bool) *TextView {
+	if t.hyphenate != hyphenate {
+		t.index = nil
+	}
+	t.hyphenate = hyphenate
+	return t
+}
+
 // SetMaxLines sets the maximum number of lines for this text view. Lines at the
 // beginning of the text will be discarded when the text view is drawn, so as to
 // remain below this value. Broken lines via word wrapping are counted
 // individually.
 //
+// Lines are also discarded while text is written, without waiting for the text
+// view to be drawn, so that its memory remains bounded, e.g. for log viewers
+// which are not visible. Here, lines are counted before word wrapping. Only the
+// discarded lines are parsed, not the entire text. Highlights of regions which
+// no longer occur in the text are removed (see SetHighlightedFunc()).
+//
 // Note that GetText() will return the shortened text and may start with color
 // and/or region tags that were open at the cutoff point.
 //
 // A value of 0 (the default) will keep all lines in place.
 func (t *TextView) SetMaxLines(maxLines int) *TextView {
 	t.maxLines = maxLines
+	t.trimBuffer()
+	t.index = nil
 	return t
 }
 
@@ -314,9 +444,263 @@ func (t *TextView) SetTextAlign(align int) *TextView {
 // dynamic colors are enabled).
 func (t *TextView) SetTextColor(color tcell.Color) *TextView {
 	t.textColor = color
+	t.overrides.set(&t.textColor)
 	return t
 }
 
+// SetSelectedStyle sets the style of text selected with the mouse.
+func (t *TextView) SetSelectedStyle(style tcell.Style) *TextView {
+	t.selectedStyle = style
+	t.overrides.set(&t.selectedStyle)
+	return t
+}
+
+// GetSelectedText returns the text the user selected by dragging the mouse or
+// an empty string if there is no selection. Color and region tags are not
+// included. Lines which were wrapped are joined again.
+func (t *TextView) GetSelectedText() string {
+	t.Lock()
+	defer t.Unlock()
+
+	fromLine, fromColumn, toLine, toColumn := t.selection()
+	if fromLine == toLine && fromColumn == toColumn {
+		return ""
+	}
+
+	var selected strings.Builder
+	for line := fromLine; line <= toLine && line < len(t.index); line++ {
+		if line < 0 {
+			continue
+		}
+		index := t.index[line]
+		if line > fromLine && index.Line != t.index[line-1].Line {
+			selected.WriteString("\n")
+		}
+		text := t.buffer[index.Line][index.Pos:index.NextPos]
+		_, _, _, _, _, strippedText, _ := decomposeString(text, t.dynamicColors, t.regions)
+		iterateString(strippedText, func(main rune, comb []rune, textPos, textWidth, screenPos, screenWidth int) bool {
+			if t.isSelected(line, screenPos) {
+				selected.WriteRune(main)
+				for _, r := range comb {
+					selected.WriteRune(r)
+				}
+			}
+			return false
+		})
+	}
+
+	return selected.String()
+}
+
+// ClearSelection removes the text selection and the cursor (see
+// SetDynamicHighlightFunc()).
+func (t *TextView) ClearSelection() *TextView {
+	t.selectFromLine, t.selectFromColumn = 0, 0
+	t.selectToLine, t.selectToColumn = 0, 0
+	t.selecting = false
+	t.hasCursor = false
+	return t
+}
+
+// selection returns the selected range, ordered from top to bottom.
+func (t *TextView) selection() (fromLine, fromColumn, toLine, toColumn int) {
+	fromLine, fromColumn = t.selectFromLine, t.selectFromColumn
+	toLine, toColumn = t.selectToLine, t.selectToColumn
+	if toLine < fromLine || toLine == fromLine && toColumn < fromColumn {
+		fromLine, fromColumn, toLine, toColumn = toLine, toColumn, fromLine, fromColumn
+	}
+	return
+}
+
+// isSelected returns whether the character at the given line of the index and
+// the given screen column within that line is part of the selection.
+func (t *TextView) isSelected(line, column int) bool {
+	fromLine, fromColumn, toLine, toColumn := t.selection()
+	if line < fromLine || line > toLine {
+		return false
+	}
+	if line == fromLine && column < fromColumn {
+		return false
+	}
+	if line == toLine && column >= toColumn {
+		return false
+	}
+	return true
+}
+
+// lineStart returns the screen column, relative to the inner rect and without
+// the column offset, at which the given line of the index starts.
+func (t *TextView) lineStart(line, width int) int {
+	if line < 0 || line >= len(t.index) {
+		return 0
+	}
+	switch t.align {
+	case AlignRight:
+		return width - t.index[line].Width
+	case AlignCenter:
+		return (width - t.index[line].Width) / 2
+	}
+	return 0
+}
+
+// textPosAt returns the line of the index and the screen column within that
+// line found at the given screen position. Positions outside the text are
+// clamped to the nearest line.
+func (t *TextView) textPosAt(x, y int) (line, column int) {
+	rectX, rectY, width, _ := t.GetInnerRect()
+	line = y - rectY + t.lineOffset
+	if line < 0 {
+		return 0, 0
+	}
+	if line >= len(t.index) {
+		line = len(t.index) - 1
+		if line < 0 {
+			return 0, 0
+		}
+		return line, t.index[line].Width
+	}
+	column = x - rectX + t.columnOffset - t.lineStart(line, width)
+	if column < 0 {
+		column = 0
+	}
+	return
+}
+
+// Search finds all occurrences of the given pattern in the text, ignoring color
+// and region tags, and returns the number of matches. Matches don't span
+// multiple lines (as separated by newlines) but may span lines which were
+// wrapped. An empty pattern or an invalid regular expression removes the
+// current search. The matches are updated when text is added later.
+//
+// Use NextMatch() and PrevMatch() to scroll to the matches.
+func (t *TextView) Search(pattern string, opts SearchOptions) (matches int) {
+	t.Lock()
+	defer t.Unlock()
+
+	t.searchPattern, t.searchMatches, t.currentMatch = nil, nil, -1
+	if pattern == "" {
+		return 0
+	}
+	if !opts.Regexp {
+		pattern = regexp.QuoteMeta(pattern)
+	}
+	if !opts.CaseSensitive {
+		pattern = "(?i)" + pattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return 0
+	}
+	t.searchPattern = re
+	t.findMatches()
+	return len(t.searchMatches)
+}
+
+// ClearSearch removes the current search and its matches.
+func (t *TextView) ClearSearch() *TextView {
+	t.Lock()
+	defer t.Unlock()
+	t.searchPattern, t.searchMatches, t.currentMatch = nil, nil, -1
+	return t
+}
+
+// NextMatch makes the match following the current match the current match and
+// scrolls the text view such that it is centered. After the last match, it
+// wraps around to the first match. Nothing happens if there are no matches.
+func (t *TextView) NextMatch() *TextView {
+	t.Lock()
+	defer t.Unlock()
+	if len(t.searchMatches) == 0 {
+		return t
+	}
+	t.currentMatch = (t.currentMatch + 1) % len(t.searchMatches)
+	t.scrollToMatch = true
+	return t
+}
+
+// PrevMatch makes the match preceding the current match the current match and
+// scrolls the text view such that it is centered. Before the first match, it
+// wraps around to the last match. Nothing happens if there are no matches.
+func (t *TextView) PrevMatch() *TextView {
+	t.Lock()
+	defer t.Unlock()
+	if len(t.searchMatches) == 0 {
+		return t
+	}
+	if t.currentMatch <= 0 {
+		t.currentMatch = len(t.searchMatches)
+	}
+	t.currentMatch--
+	t.scrollToMatch = true
+	return t
+}
+
+// GetCurrentMatch returns the index of the current match and the total number
+// of matches. The index is -1 if there is no current match.
+func (t *TextView) GetCurrentMatch() (index, matches int) {
+	t.Lock()
+	defer t.Unlock()
+	return t.currentMatch, len(t.searchMatches)
+}
+
+// SetMatchStyles sets the style of search matches and the style of the current
+// match.
+func (t *TextView) SetMatchStyles(match, current tcell.Style) *TextView {
+	t.matchStyle, t.currentMatchStyle = match, current
+	t.overrides.set(&t.matchStyle, &t.currentMatchStyle)
+	return t
+}
+
+// findMatches finds all matches of the search pattern in the buffer. The
+// current match is kept if it still exists.
+func (t *TextView) findMatches() {
+	t.searchMatches = nil
+	if t.searchPattern == nil {
+		t.currentMatch = -1
+		return
+	}
+	for bufferIndex, str := range t.buffer {
+		_, _, _, _, _, strippedStr, _ := decomposeString(str, t.dynamicColors, t.regions)
+		for _, match := range t.searchPattern.FindAllStringIndex(strippedStr, -1) {
+			if match[0] == match[1] {
+				continue // Ignore empty matches.
+			}
+			t.searchMatches = append(t.searchMatches, textViewMatch{
+				Line: bufferIndex,
+				From: match[0],
+				To:   match[1],
+			})
+		}
+	}
+	if t.currentMatch >= len(t.searchMatches) {
+		t.currentMatch = len(t.searchMatches) - 1
+	}
+}
+
+// strippedOffset returns the byte position, within the buffer line with all
+// tags stripped, at which the given line of the index starts.
+func (t *TextView) strippedOffset(line int) int {
+	index := t.index[line]
+	_, _, _, _, _, strippedPrefix, _ := decomposeString(t.buffer[index.Line][:index.Pos], t.dynamicColors, t.regions)
+	return len(strippedPrefix)
+}
+
+// matchAt returns the index into searchMatches of the match which covers the
+// given byte position of the given buffer line (tags stripped), or -1 if there
+// is no such match.
+func (t *TextView) matchAt(bufferLine, pos int) int {
+	index := sort.Search(len(t.searchMatches), func(i int) bool {
+		match := t.searchMatches[i]
+		return match.Line > bufferLine || match.Line == bufferLine && match.To > pos
+	})
+	if index < len(t.searchMatches) {
+		if match := t.searchMatches[index]; match.Line == bufferLine && match.From <= pos {
+			return index
+		}
+	}
+	return -1
+}
+
 // SetText sets the text of this text view to the provided string. Previously
 // contained text will be removed.
 func (t *TextView) SetText(text string) *TextView {
@@ -378,6 +762,15 @@ func (t *TextView) SetDynamicColors(dynamic bool) *TextView {
 	return t
 }
 
+// ANSIWriter enables dynamic colors (see SetDynamicColors()) and returns a
+// writer which translates ANSI escape codes, e.g. from the output of a command,
+// into color tags before writing to the text view. See the package function
+// ANSIWriter() for details. Escape sequences may be split across writes.
+func (t *TextView) ANSIWriter() io.Writer {
+	t.SetDynamicColors(true)
+	return ANSIWriter(t)
+}
+
 // SetRegions sets the flag that allows to define regions in the text. See class
 // description for details.
 func (t *TextView) GetStyler() Styler {
@@ -427,6 +820,17 @@ func (t *TextView) SetDoneFunc(handler func(key tcell.Key)) *TextView {
 	return t
 }
 
+// SetLinkClickedFunc sets a handler which is called with a link's URL when the
+// user clicks on the link with the left mouse button. See the Links section of
+// the TextView documentation for how links are defined.
+//
+// Terminals which support OSC 8 hyperlinks may handle clicks on links
+// themselves without passing them on to the application.
+func (t *TextView) SetLinkClickedFunc(handler func(url string)) *TextView {
+	t.linkClicked = handler
+	return t
+}
+
 // SetHighlightedFunc sets a handler which is called when the list of currently
 // highlighted regions change. It receives a list of region IDs which were newly
 // highlighted, those that are not highlighted anymore, and those that remain
@@ -439,6 +843,101 @@ func (t *TextView) SetHighlightedFunc(handler func(added, removed, remaining []s
 	return t
 }
 
+// SetDynamicHighlightFunc sets a function which determines the regions to be
+// highlighted for the word under the cursor, e.g. to highlight all occurrences
+// of that word. The cursor is placed by clicking into the text and follows the
+// end of the selection while the mouse is dragged. The function receives the
+// word under the cursor (letters, digits, and underscores, with tags stripped)
+// and returns the IDs of the regions to highlight, replacing the current
+// highlights (see Highlight()). The function is called when the text view is
+// drawn, only if the word under the cursor has changed. If the cursor is not on
+// a word or if it is more than a page away from the visible text, all
+// highlights are removed without calling the function. Clicking on a region
+// does not highlight it while such a function is installed.
+//
+// Regions must be enabled (see SetRegions()). The function is called while the
+// text view is locked and must therefore not call Write() or functions which
+// change the text. Provide nil to uninstall the function.
+func (t *TextView) SetDynamicHighlightFunc(handler func(word string) []string) *TextView {
+	t.dynamicHighlight = handler
+	t.dynamicWord = ""
+	return t
+}
+
+// updateDynamicHighlight calls the dynamic highlight function if the word
+// under the cursor has changed and highlights the regions it returns. The
+// given height is the number of visible lines. The text view must be locked
+// and indexed.
+func (t *TextView) updateDynamicHighlight(height int) {
+	var word string
+	if t.hasCursor && t.selectToLine >= t.lineOffset-height && t.selectToLine < t.lineOffset+2*height {
+		word = t.wordAt(t.selectToLine, t.selectToColumn)
+	}
+	if word == t.dynamicWord {
+		return
+	}
+	t.dynamicWord = word
+
+	var regionIDs []string
+	if word != "" {
+		regionIDs = t.dynamicHighlight(word)
+	}
+	toggle := t.toggleHighlights
+	t.toggleHighlights = false // Always replace the highlights.
+	t.Highlight(regionIDs...)
+	t.toggleHighlights = toggle
+	t.reindexBuffer(t.lastWidth)
+}
+
+// wordAt returns the word (letters, digits, and underscores) found at the
+// given line of the index and screen column within that line, with all tags
+// stripped. It returns an empty string if there is no word at that position.
+func (t *TextView) wordAt(line, column int) string {
+	if line < 0 || line >= len(t.index) || column < 0 || column >= t.index[line].Width {
+		return ""
+	}
+	index := t.index[line]
+	_, _, _, _, _, stripped, _ := decomposeString(t.buffer[index.Line], t.dynamicColors, t.regions)
+	offset := t.strippedOffset(line)
+
+	// Find the character at the given column.
+	pos := -1
+	iterateString(stripped[offset:], func(main rune, comb []rune, textPos, textWidth, screenPos, screenWidth int) bool {
+		if column < screenPos+screenWidth {
+			pos = offset + textPos
+			return true
+		}
+		return false
+	})
+	isWordRune := func(r rune) bool {
+		return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+	}
+	if pos < 0 {
+		return ""
+	}
+	if r, _ := utf8.DecodeRuneInString(stripped[pos:]); !isWordRune(r) {
+		return ""
+	}
+
+	// Expand to the entire word.
+	from, to := pos, pos
+	for from > 0 {
+		r, size := utf8.DecodeLastRuneInString(stripped[:from])
+		if !isWordRune(r) {
+			break
+		}
+		from -= size
+	}
+	for to < len(stripped) {
+		r, size := utf8.DecodeRuneInString(stripped[to:])
+		if !isWordRune(r) {
+			break
+		}
+		to += size
+	}
+	return stripped[from:to]
+}
+
 // ScrollTo scrolls to the specified row and column (both starting with 0).
 func (t *TextView) ScrollTo(row, column int) *TextView {
 	if !t.scrollable {
@@ -474,6 +973,33 @@ func (t *TextView) ScrollToEnd() *TextView {
 	return t
 }
 
+// SetFollowing sets whether the text view follows new text, e.g. for tailing
+// logs. If enabled, the text view scrolls to the end and keeps scrolling as
+// text is added. Following pauses as soon as the user scrolls up and resumes
+// when the user scrolls back to the end of the text.
+func (t *TextView) SetFollowing(follow bool) *TextView {
+	t.following = follow
+	if follow && t.scrollable {
+		t.trackEnd = true
+		t.columnOffset = 0
+	}
+	return t
+}
+
+// IsFollowing returns whether following was enabled with SetFollowing().
+func (t *TextView) IsFollowing() bool {
+	return t.following
+}
+
+// IsAtBottom returns whether the end of the text was visible the last time the
+// text view was drawn or whether the text view will scroll to the end when it
+// is drawn next, e.g. after ScrollToEnd() was called.
+func (t *TextView) IsAtBottom() bool {
+	t.Lock()
+	defer t.Unlock()
+	return t.trackEnd || t.lineOffset+t.pageSize >= len(t.index)
+}
+
 // GetScrollOffset returns the number of rows and columns that are skipped at
 // the top left corner when the text view has been scrolled.
 func (t *TextView) GetScrollOffset() (row, column int) {
@@ -495,6 +1021,8 @@ func (t *TextView) clear() {
 	t.buffer = nil
 	t.recentBytes = nil
 	t.index = nil
+	t.searchMatches, t.currentMatch = nil, -1
+	t.ClearSelection()
 }
 
 // Highlight specifies which regions should be highlighted. If highlight
@@ -676,9 +1204,14 @@ func (t *TextView) GetRegionText(regionID string) string {
 // Focus is called when this primitive receives focus.
 func (t *TextView) Focus(delegate func(p Primitive)) {
 	// Implemented here with locking because this is used by layout primitives.
+	// The handler is called without the lock so it may use the text view.
 	t.Lock()
-	defer t.Unlock()
-	t.Box.Focus(delegate)
+	hadFocus := t.hasFocus
+	t.hasFocus = true
+	t.Unlock()
+	if !hadFocus && t.onFocus != nil {
+		t.onFocus()
+	}
 }
 
 // HasFocus returns whether or not this primitive has focus.
@@ -755,12 +1288,105 @@ func (t *TextView) write(p []byte) (n int, err error) {
 		}
 	}
 
-	// Reset the index.
+	// Drop the oldest lines and reset the index.
+	t.trimBuffer()
 	t.index = nil
 
 	return len(p), nil
 }
 
+// stateTags returns the color and region tags which restore the given state
+// at the start of a line.
+func stateTags(foregroundColor, backgroundColor, attributes, url, region string) (tags string) {
+	if url != "" {
+		tags = fmt.Sprintf("[%s:%s:%s:%s]", foregroundColor, backgroundColor, attributes, url)
+	} else if foregroundColor != "" || backgroundColor != "" || attributes != "" {
+		tags = fmt.Sprintf("[%s:%s:%s]", foregroundColor, backgroundColor, attributes)
+	}
+	if region != "" {
+		tags += fmt.Sprintf(`["%s"]`, region)
+	}
+	return
+}
+
+// trimBuffer drops the oldest lines of the buffer if it has more lines than
+// allowed by SetMaxLines(). Only the dropped lines are parsed, to carry their
+// open color and region tags over to the first remaining line. Highlights of
+// regions which no longer occur in the buffer are removed. The line offset and
+// the selection are shifted by the number of dropped lines.
+func (t *TextView) trimBuffer() {
+	dropped := len(t.buffer) - t.maxLines
+	if t.maxLines <= 0 || dropped <= 0 {
+		return
+	}
+
+	// Determine the state at the start of the first remaining line.
+	var foregroundColor, backgroundColor, attributes, url, region string
+	droppedRegions := make(map[string]struct{})
+	for _, str := range t.buffer[:dropped] {
+		_, colorTags, _, regions, _, _, _ := decomposeString(str, t.dynamicColors, t.regions)
+		for _, tag := range colorTags {
+			foregroundColor, backgroundColor, attributes = styleFromTag(foregroundColor, backgroundColor, attributes, tag, t.styler)
+			url = urlFromTag(url, tag)
+		}
+		for _, r := range regions {
+			region = r[1]
+			droppedRegions[region] = struct{}{}
+		}
+	}
+
+	// The number of screen lines which are dropped is only known if the
+	// buffer was indexed since the last change.
+	removedLines := dropped
+	if t.index != nil {
+		removedLines = sort.Search(len(t.index), func(i int) bool {
+			return t.index[i].Line >= dropped
+		})
+	}
+	t.lineOffset -= removedLines
+	if t.lineOffset < 0 {
+		t.lineOffset = 0
+	}
+	if t.selectFromLine < removedLines || t.selectToLine < removedLines {
+		t.ClearSelection()
+	} else {
+		t.selectFromLine -= removedLines
+		t.selectToLine -= removedLines
+	}
+
+	// Drop the lines.
+	t.buffer = t.buffer[dropped:]
+	t.buffer[0] = stateTags(foregroundColor, backgroundColor, attributes, url, region) + t.buffer[0]
+
+	// Remove the highlights of regions which are gone.
+	var removed []string
+	for id := range droppedRegions {
+		if _, ok := t.highlights[id]; !ok || id == region {
+			continue
+		}
+		tag := fmt.Sprintf(`["%s"]`, id)
+		var found bool
+		for _, str := range t.buffer {
+			if strings.Contains(str, tag) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			delete(t.highlights, id)
+			removed = append(removed, id)
+		}
+	}
+	if len(removed) > 0 && t.highlighted != nil {
+		var remaining []string
+		for id := range t.highlights {
+			remaining = append(remaining, id)
+		}
+		highlighted := t.highlighted
+		go highlighted(nil, removed, remaining) // Like "changed", to avoid deadlocks.
+	}
+}
+
 // BatchWriter returns a new writer that can be used to write into the buffer
 // but without Locking/Unlocking the buffer on every write, as TextView's
 // Write() and Clear() functions do. The lock will be aquired once when
@@ -785,6 +1411,26 @@ func (t *TextView) BatchWriter() TextViewWriter {
 	}
 }
 
+// hyphenateWord returns the byte length of the beginning of the given text
+// which, followed by a hyphen, fits into the given width. 0 is returned if the
+// text cannot be hyphenated there, i.e. if the width is too small or if the
+// characters on either side of the break are not letters of width 1.
+func hyphenateWord(text string, width int) int {
+	if width < 2 {
+		return 0
+	}
+	prefix := runewidth.Truncate(text, width-1, "")
+	if len(prefix) == 0 || len(prefix) >= len(text) {
+		return 0
+	}
+	before, _ := utf8.DecodeLastRuneInString(prefix)
+	after, _ := utf8.DecodeRuneInString(text[len(prefix):])
+	if !unicode.IsLetter(before) || !unicode.IsLetter(after) || runewidth.RuneWidth(before) != 1 || runewidth.RuneWidth(after) != 1 {
+		return 0
+	}
+	return len(prefix)
+}
+
 // reindexBuffer re-indexes the buffer such that we can use it to easily draw
 // the buffer onto the screen. Each line in the index will contain a pointer
 // into the buffer from which on we will print text. It will also contain the
@@ -807,8 +1453,8 @@ func (t *TextView) reindexBuffer(width int) {
 	// Initial states.
 	regionID := ""
 	var (
-		highlighted                                  bool
-		foregroundColor, backgroundColor, attributes string
+		highlighted                                       bool
+		foregroundColor, backgroundColor, attributes, url string
 	)
 
 	// Go through each line in the buffer.
@@ -816,10 +1462,14 @@ func (t *TextView) reindexBuffer(width int) {
 		colorTagIndices, colorTags, regionIndices, regions, escapeIndices, strippedStr, _ := decomposeString(str, t.dynamicColors, t.regions)
 
 		// Split the line if required.
-		var splitLines []string
+		var (
+			splitLines []string
+			hyphens    []bool
+		)
 		str = strippedStr
 		if t.wrap && len(str) > 0 {
 			for len(str) > 0 {
+				var hyphen bool
 				extract := runewidth.Truncate(str, width, "")
 				if len(extract) == 0 {
 					// We'll extract at least one grapheme cluster.
@@ -839,19 +1489,27 @@ func (t *TextView) reindexBuffer(width int) {
 					if len(matches) > 0 {
 						// Yes. Let's split there.
 						extract = extract[:matches[len(matches)-1][1]]
+					} else if t.hyphenate {
+						// No. Break the word with a hyphen.
+						if hyphenated := hyphenateWord(str, width); hyphenated > 0 {
+							extract = str[:hyphenated]
+							hyphen = true
+						}
 					}
 				}
 				splitLines = append(splitLines, extract)
+				hyphens = append(hyphens, hyphen)
 				str = str[len(extract):]
 			}
 		} else {
 			// No need to split the line.
 			splitLines = []string{str}
+			hyphens = []bool{false}
 		}
 
 		// Create index from split lines.
 		var originalPos, colorPos, regionPos, escapePos int
-		for _, splitLine := range splitLines {
+		for splitIndex, splitLine := range splitLines {
 			line := &textViewIndex{
 				Line:            bufferIndex,
 				Pos:             originalPos,
@@ -859,6 +1517,8 @@ func (t *TextView) reindexBuffer(width int) {
 				BackgroundColor: backgroundColor,
 				Attributes:      attributes,
 				Region:          regionID,
+				URL:             url,
+				Hyphen:          hyphens[splitIndex],
 			}
 
 			// Shift original position with tags.
@@ -907,6 +1567,7 @@ func (t *TextView) reindexBuffer(width int) {
 				case 0:
 					// Process color tags.
 					foregroundColor, backgroundColor, attributes = styleFromTag(foregroundColor, backgroundColor, attributes, colorTags[colorPos], t.styler)
+					url = urlFromTag(url, colorTags[colorPos])
 					colorPos++
 				case 1:
 					// Process region tags.
@@ -937,6 +1598,9 @@ func (t *TextView) reindexBuffer(width int) {
 			// Append this line.
 			line.NextPos = originalPos
 			line.Width = stringWidth(splitLine)
+			if line.Hyphen {
+				line.Width++
+			}
 			t.index = append(t.index, line)
 		}
 
@@ -979,13 +1643,8 @@ func (t *TextView) reindexBuffer(width int) {
 
 		// Adjust the original buffer.
 		t.buffer = t.buffer[bufferShift:]
-		var prefix string
-		if t.index[0].ForegroundColor != "" || t.index[0].BackgroundColor != "" || t.index[0].Attributes != "" {
-			prefix = fmt.Sprintf("[%s:%s:%s]", t.index[0].ForegroundColor, t.index[0].BackgroundColor, t.index[0].Attributes)
-		}
-		if t.index[0].Region != "" {
-			prefix += fmt.Sprintf(`["%s"]`, t.index[0].Region)
-		}
+		first := t.index[0]
+		prefix := stateTags(first.ForegroundColor, first.BackgroundColor, first.Attributes, first.URL, first.Region)
 		posShift := t.index[0].Pos
 		t.buffer[0] = prefix + t.buffer[0][posShift:]
 		t.lineOffset -= removedLines
@@ -1004,6 +1663,11 @@ func (t *TextView) reindexBuffer(width int) {
 		}
 	}
 
+	// The buffer may have changed. Update the search matches.
+	if t.searchPattern != nil {
+		t.findMatches()
+	}
+
 	// Calculate longest line.
 	t.longestLine = 0
 	for _, line := range t.index {
@@ -1013,6 +1677,15 @@ func (t *TextView) reindexBuffer(width int) {
 	}
 }
 
+// applyTheme implements the themeable interface.
+func (t *TextView) applyTheme(from, to *Theme) {
+	t.Box.applyTheme(from, to)
+	t.overrides.color(&t.textColor, to.PrimaryTextColor)
+	t.overrides.style(&t.selectedStyle, tcell.StyleDefault.Foreground(to.PrimitiveBackgroundColor).Background(to.PrimaryTextColor))
+	t.overrides.style(&t.matchStyle, tcell.StyleDefault.Foreground(to.PrimitiveBackgroundColor).Background(to.SecondaryTextColor))
+	t.overrides.style(&t.currentMatchStyle, tcell.StyleDefault.Foreground(to.PrimitiveBackgroundColor).Background(to.TertiaryTextColor))
+}
+
 // Draw draws this primitive onto the screen.
 func (t *TextView) Draw(screen tcell.Screen) {
 	t.Box.DrawForSubclass(screen, t)
@@ -1035,12 +1708,21 @@ func (t *TextView) Draw(screen tcell.Screen) {
 	if t.regions {
 		t.regionInfos = nil
 	}
+	t.linkInfos = nil
 
 	// If we don't have an index, there's nothing to draw.
 	if t.index == nil {
 		return
 	}
 
+	// Highlight the regions for the word under the cursor.
+	if t.regions && t.dynamicHighlight != nil {
+		t.updateDynamicHighlight(height)
+		if t.index == nil {
+			return
+		}
+	}
+
 	// Move to highlighted regions.
 	if t.regions && t.scrollToHighlights && t.fromHighlight >= 0 {
 		// Do we fit the entire height?
@@ -1064,8 +1746,37 @@ func (t *TextView) Draw(screen tcell.Screen) {
 	}
 	t.scrollToHighlights = false
 
+	// Move to the current search match.
+	if t.scrollToMatch && t.currentMatch >= 0 && t.currentMatch < len(t.searchMatches) {
+		match := t.searchMatches[t.currentMatch]
+		for line := range t.index {
+			if t.index[line].Line != match.Line {
+				continue
+			}
+			offset := t.strippedOffset(line)
+			if line+1 < len(t.index) && t.index[line+1].Line == match.Line && t.strippedOffset(line+1) <= match.From {
+				continue // The match starts on a later line.
+			}
+
+			// Center the match vertically.
+			t.lineOffset = line - height/2
+			t.trackEnd = false
+
+			// Bring the match into view horizontally.
+			if !t.wrap {
+				_, _, _, _, _, strippedText, _ := decomposeString(t.buffer[match.Line], t.dynamicColors, t.regions)
+				posMatch := t.lineStart(line, width) + stringWidth(strippedText[offset:match.From])
+				if posMatch-t.columnOffset < 0 || posMatch-t.columnOffset >= width {
+					t.columnOffset = posMatch - width/2
+				}
+			}
+			break
+		}
+	}
+	t.scrollToMatch = false
+
 	// Adjust line offset.
-	if t.lineOffset+height > len(t.index) {
+	if t.lineOffset+height > len(t.index) || t.following && t.lineOffset+height >= len(t.index) {
 		t.trackEnd = true
 	}
 	if t.trackEnd {
@@ -1118,6 +1829,7 @@ func (t *TextView) Draw(screen tcell.Screen) {
 		foregroundColor := index.ForegroundColor
 		backgroundColor := index.BackgroundColor
 		attributes := index.Attributes
+		url := index.URL
 		regionID := index.Region
 		if t.regions {
 			if len(t.regionInfos) > 0 && t.regionInfos[len(t.regionInfos)-1].ID != regionID {
@@ -1140,6 +1852,12 @@ func (t *TextView) Draw(screen tcell.Screen) {
 		// Process tags.
 		colorTagIndices, colorTags, regionIndices, regions, escapeIndices, strippedText, _ := decomposeString(text, t.dynamicColors, t.regions)
 
+		// Where does this line start for the purpose of search matches?
+		var matchOffset int
+		if len(t.searchMatches) > 0 {
+			matchOffset = t.strippedOffset(line)
+		}
+
 		// Calculate the position of the line.
 		var skip, posX int
 		if t.align == AlignLeft {
@@ -1163,6 +1881,7 @@ func (t *TextView) Draw(screen tcell.Screen) {
 					if colorPos < len(colorTags) && textPos+tagOffset >= colorTagIndices[colorPos][0] && textPos+tagOffset < colorTagIndices[colorPos][1] {
 						// Get the color.
 						foregroundColor, backgroundColor, attributes = styleFromTag(foregroundColor, backgroundColor, attributes, colorTags[colorPos], t.styler)
+						url = urlFromTag(url, colorTags[colorPos])
 						tagOffset += colorTagIndices[colorPos][1] - colorTagIndices[colorPos][0]
 						colorPos++
 					} else if regionPos < len(regionIndices) && textPos+tagOffset >= regionIndices[regionPos][0] && textPos+tagOffset < regionIndices[regionPos][1] {
@@ -1198,6 +1917,9 @@ func (t *TextView) Draw(screen tcell.Screen) {
 
 				// Mix the existing style with the new style.
 				style := overlayStyle(defaultStyle, foregroundColor, backgroundColor, attributes)
+				if url != "" {
+					style = style.Url(url)
+				}
 
 				// Do we highlight this character?
 				var highlighted bool
@@ -1221,6 +1943,22 @@ func (t *TextView) Draw(screen tcell.Screen) {
 					style = style.Background(fg).Foreground(bg)
 				}
 
+				// Is this character part of a search match?
+				if len(t.searchMatches) > 0 {
+					if match := t.matchAt(index.Line, matchOffset+textPos); match >= 0 {
+						if match == t.currentMatch {
+							style = t.currentMatchStyle
+						} else {
+							style = t.matchStyle
+						}
+					}
+				}
+
+				// Is this character selected?
+				if t.isSelected(line, screenPos) {
+					style = t.selectedStyle
+				}
+
 				// Skip to the right.
 				if !t.wrap && skipped < skip {
 					skipped += screenWidth
@@ -1232,6 +1970,20 @@ func (t *TextView) Draw(screen tcell.Screen) {
 					return true
 				}
 
+				// Remember where links are.
+				if url != "" {
+					if last := len(t.linkInfos) - 1; last >= 0 && t.linkInfos[last].URL == url && t.linkInfos[last].Y == y+line-t.lineOffset && t.linkInfos[last].X+t.linkInfos[last].Width == x+posX {
+						t.linkInfos[last].Width += screenWidth
+					} else {
+						t.linkInfos = append(t.linkInfos, textViewLink{
+							URL:   url,
+							X:     x + posX,
+							Y:     y + line - t.lineOffset,
+							Width: screenWidth,
+						})
+					}
+				}
+
 				// Draw the character.
 				for offset := screenWidth - 1; offset >= 0; offset-- {
 					if offset == 0 {
@@ -1245,6 +1997,12 @@ func (t *TextView) Draw(screen tcell.Screen) {
 				posX += screenWidth
 				return false
 			})
+
+			// Draw the hyphen of a broken word.
+			if index.Hyphen && posX < width && x+posX < totalWidth {
+				style := overlayStyle(defaultStyle, foregroundColor, backgroundColor, attributes)
+				screen.SetContent(x+posX, y+line-t.lineOffset, '-', nil, style)
+			}
 		}
 	}
 
@@ -1330,13 +2088,55 @@ func (t *TextView) InputHandler() func(event *tcell.EventKey, setFocus func(p Pr
 func (t *TextView) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
 	return t.WrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
 		x, y := event.Position()
-		if !t.InRect(x, y) {
+		if !t.InRect(x, y) && !t.selecting {
 			return false, nil
 		}
 
 		switch action {
+		case MouseLeftDown:
+			// Start a new selection.
+			t.Lock()
+			t.selectFromLine, t.selectFromColumn = t.textPosAt(x, y)
+			t.selectToLine, t.selectToColumn = t.selectFromLine, t.selectFromColumn
+			t.hasCursor = true
+			t.Unlock()
+			t.selecting = true
+			setFocus(t)
+			consumed, capture = true, t
+		case MouseMove:
+			if t.selecting {
+				t.Lock()
+				t.selectToLine, t.selectToColumn = t.textPosAt(x, y)
+				t.Unlock()
+				consumed, capture = true, t
+			}
+		case MouseLeftUp:
+			if t.selecting {
+				t.Lock()
+				t.selectToLine, t.selectToColumn = t.textPosAt(x, y)
+				t.Unlock()
+				t.selecting = false
+				consumed = true
+			}
 		case MouseLeftClick:
-			if t.regions {
+			if t.linkClicked != nil {
+				// Find a link to follow.
+				t.Lock()
+				var url string
+				for _, link := range t.linkInfos {
+					if y == link.Y && x >= link.X && x < link.X+link.Width {
+						url = link.URL
+						break
+					}
+				}
+				t.Unlock()
+				if url != "" {
+					t.linkClicked(url)
+					setFocus(t)
+					return true, nil
+				}
+			}
+			if t.regions && t.dynamicHighlight == nil {
 				// Find a region to highlight.
 				for _, region := range t.regionInfos {
 					if y == region.FromY && x < region.FromX ||
@@ -1358,6 +2158,16 @@ func (t *TextView) MouseHandler() func(action MouseAction, event *tcell.EventMou
 		case MouseScrollDown:
 			t.lineOffset++
 			consumed = true
+		case MouseScrollLeft:
+			if !t.wrap {
+				t.columnOffset--
+			}
+			consumed = true
+		case MouseScrollRight:
+			if !t.wrap {
+				t.columnOffset++
+			}
+			consumed = true
 		}
 
 		return
@@ -0,0 +1,59 @@
+package tview
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTextViewSetMaxLines(t *testing.T) {
+	removed := make(chan []string, 10)
+	textView := NewTextView().
+		SetDynamicColors(true).
+		SetRegions(true).
+		SetScrollable(true).
+		SetMaxLines(4).
+		SetHighlightedFunc(func(added, removedIDs, remaining []string) {
+			removed <- removedIDs
+		})
+	textView.SetRect(0, 0, 20, 1)
+	textView.Highlight("a", "b")
+	<-removed // The added highlights.
+
+	// Lines are dropped while writing. The highlight of a region which is
+	// gone is removed.
+	fmt.Fprint(textView, `["a"]zero[""]`+"\n[red]one\n"+`["b"]two`+"\nthree\nfour")
+	if text := textView.GetText(false); text != "[red]one\n"+`["b"]two`+"\nthree\nfour\n" {
+		t.Errorf("text is %q after writing five lines", text)
+	}
+	select {
+	case ids := <-removed:
+		if !reflect.DeepEqual(ids, []string{"a"}) {
+			t.Errorf("highlights %v were removed, expected [a]", ids)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("removed highlight was not reported")
+	}
+	if highlights := textView.GetHighlights(); !reflect.DeepEqual(highlights, []string{"b"}) {
+		t.Errorf("highlights are %v, expected [b]", highlights)
+	}
+
+	// The color and the region which are open at the cutoff are carried over
+	// to the first remaining line. The highlight of the open region is kept.
+	// The scroll offset moves with the text.
+	textView.ScrollTo(3, 0)
+	fmt.Fprint(textView, "\nfive\nsix")
+	if text := textView.GetText(false); text != `[red::]["b"]three`+"\nfour\nfive\nsix\n" {
+		t.Errorf("text is %q after dropping two more lines", text)
+	}
+	if text := textView.GetText(true); text != "three\nfour\nfive\nsix" {
+		t.Errorf("text without tags is %q", text)
+	}
+	if highlights := textView.GetHighlights(); !reflect.DeepEqual(highlights, []string{"b"}) {
+		t.Errorf("highlights are %v, expected the open region to remain highlighted", highlights)
+	}
+	if row, _ := textView.GetScrollOffset(); row != 1 {
+		t.Errorf("scroll offset is %d, expected 1", row)
+	}
+}
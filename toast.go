@@ -0,0 +1,150 @@
+package tview
+
+import (
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// Corner identifies a corner of the screen, see Application.ShowNotification().
+type Corner int
+
+// Screen corners.
+const (
+	CornerTopLeft Corner = iota
+	CornerTopRight
+	CornerBottomLeft
+	CornerBottomRight
+)
+
+// Toast is a small, transient message box which is drawn over the
+// application's root primitive in one of the screen's corners. It never
+// receives focus and does not block user input. Toasts are usually created
+// with Application.ShowNotification() which also removes them after a timeout.
+// Multiple toasts in the same corner are stacked, the oldest one being closest
+// to the corner.
+type Toast struct {
+	*Box
+
+	// The message text.
+	text string
+
+	// The color of the message text.
+	textColor tcell.Color
+
+	// The corner the toast is shown in.
+	corner Corner
+
+	// The application which showed the toast, nil if none did. It is set once
+	// and never changes.
+	app *Application
+
+	// Stops the automatic removal of the toast. nil if there is none. Guarded
+	// by the application's lock.
+	timer *time.Timer
+}
+
+// NewToast returns a new toast with the given message text.
+func NewToast(text string) *Toast {
+	t := &Toast{
+		Box:       NewBox(),
+		text:      text,
+		textColor: Styles.PrimaryTextColor,
+	}
+	t.SetBorder(true).
+		SetBackgroundColor(Styles.ContrastBackgroundColor).
+		SetBorderPadding(0, 0, 1, 1)
+	return t
+}
+
+// SetText sets the message text of the toast.
+func (t *Toast) SetText(text string) *Toast {
+	t.text = text
+	return t
+}
+
+// GetText returns the message text of the toast.
+func (t *Toast) GetText() string {
+	return t.text
+}
+
+// SetTextColor sets the color of the message text.
+func (t *Toast) SetTextColor(color tcell.Color) *Toast {
+	t.textColor = color
+	return t
+}
+
+// Dismiss removes the toast from the application it is shown in before its
+// timeout has passed. Nothing happens if the toast is not shown.
+func (t *Toast) Dismiss() {
+	if t.app != nil {
+		t.app.DismissNotification(t)
+	}
+}
+
+// lines returns the message text wrapped to the given width.
+func (t *Toast) lines(width int) (lines []string) {
+	for _, line := range strings.Split(t.text, "\n") {
+		if len(line) == 0 {
+			lines = append(lines, "")
+			continue
+		}
+		lines = append(lines, WordWrap(line, width)...)
+	}
+	return
+}
+
+// Draw draws this primitive onto the screen.
+func (t *Toast) Draw(screen tcell.Screen) {
+	t.Box.DrawForSubclass(screen, t)
+	x, y, width, height := t.GetInnerRect()
+	for index, line := range t.lines(width) {
+		if index >= height {
+			break
+		}
+		Print(screen, line, x, y+index, width, AlignLeft, t.textColor)
+	}
+}
+
+// Focus is called when this primitive receives focus. Toasts never keep the
+// focus.
+func (t *Toast) Focus(delegate func(p Primitive)) {}
+
+// drawToasts draws the given toasts into the corners of the screen, stacking
+// toasts which share a corner. Toasts take at most a third of the screen width.
+func drawToasts(screen tcell.Screen, toasts []*Toast) {
+	screenWidth, screenHeight := screen.Size()
+	maxWidth := screenWidth / 3
+	if maxWidth < 10 {
+		maxWidth = 10
+	}
+	var offsets [4]int // The rows taken in each corner.
+	for _, toast := range toasts {
+		// Determine the size.
+		textWidth := 0
+		for _, line := range strings.Split(toast.text, "\n") {
+			if w := TaggedStringWidth(line); w > textWidth {
+				textWidth = w
+			}
+		}
+		if textWidth > maxWidth-4 {
+			textWidth = maxWidth - 4
+		}
+		width := textWidth + 4
+		height := len(toast.lines(textWidth)) + 2
+
+		// Determine the position.
+		x, y := 0, offsets[toast.corner]
+		if toast.corner == CornerTopRight || toast.corner == CornerBottomRight {
+			x = screenWidth - width
+		}
+		if toast.corner == CornerBottomLeft || toast.corner == CornerBottomRight {
+			y = screenHeight - offsets[toast.corner] - height
+		}
+		offsets[toast.corner] += height
+
+		toast.SetRect(x, y, width, height)
+		toast.Draw(screen)
+	}
+}
@@ -0,0 +1,132 @@
+package tview
+
+import (
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// DefaultTooltipDelay is the time the mouse pointer must rest on a primitive
+// before its tooltip is shown, by default. See Application.SetTooltipDelay().
+var DefaultTooltipDelay = 500 * time.Millisecond
+
+// tooltip is a tooltip (see Box.SetTooltip()) shown at a mouse position.
+type tooltip struct {
+	text string
+	x, y int
+
+	// The earliest time at which the tooltip may be shown.
+	due time.Time
+}
+
+// draw draws the tooltip below and to the right of its mouse position, or on
+// the other side of the position if it would leave the screen otherwise.
+func (t *tooltip) draw(screen tcell.Screen, style tcell.Style) {
+	screenWidth, screenHeight := screen.Size()
+	lines := strings.Split(t.text, "\n")
+	width := 0
+	for _, line := range lines {
+		if w := TaggedStringWidth(line); w > width {
+			width = w
+		}
+	}
+	width += 2
+	height := len(lines)
+	if width > screenWidth {
+		width = screenWidth
+	}
+	if height > screenHeight {
+		height = screenHeight
+	}
+
+	x, y := t.x+1, t.y+1
+	if x+width > screenWidth {
+		x = screenWidth - width
+	}
+	if y+height > screenHeight {
+		y = t.y - height
+	}
+	if x < 0 {
+		x = 0
+	}
+	if y < 0 {
+		y = 0
+	}
+
+	for row := 0; row < height; row++ {
+		for column := 0; column < width; column++ {
+			screen.SetContent(x+column, y+row, ' ', nil, style)
+		}
+		printWithEllipsis(screen, lines[row], x+1, y+row, width-2, AlignLeft, style, false)
+	}
+}
+
+// SetTooltipDelay sets the time the mouse pointer must rest on a primitive
+// before its tooltip is shown (see Box.SetTooltip()). The default is
+// DefaultTooltipDelay.
+func (a *Application) SetTooltipDelay(delay time.Duration) *Application {
+	a.Lock()
+	defer a.Unlock()
+	a.tooltipDelay = delay
+	return a
+}
+
+// SetTooltipStyle sets the style of tooltips (see Box.SetTooltip()).
+func (a *Application) SetTooltipStyle(style tcell.Style) *Application {
+	a.Lock()
+	defer a.Unlock()
+	a.tooltipStyle = style
+	a.tooltipStyleSet = true
+	return a
+}
+
+// updateTooltip hides the current tooltip, if any, after the given mouse
+// event. If the mouse pointer only moved, the tooltip of the innermost hovered
+// primitive is scheduled to be shown after the tooltip delay unless another
+// mouse event occurs first. It returns whether a tooltip was hidden. It must be
+// called after updateHover().
+func (a *Application) updateTooltip(event *tcell.EventMouse) (hidden bool) {
+	hidden = a.tooltip != nil
+	a.tooltip, a.tooltipPending = nil, nil
+	if a.tooltipTimer != nil {
+		a.tooltipTimer.Stop()
+	}
+	if event.Buttons() != tcell.ButtonNone {
+		return // Clicks and wheel movements only hide the tooltip.
+	}
+
+	var text string
+	for index := len(a.hovered) - 1; index >= 0; index-- {
+		if text = a.hovered[index].(hoverable).GetTooltip(); text != "" {
+			break
+		}
+	}
+	if text == "" {
+		return
+	}
+
+	// Reuse the timer, mouse motion events may arrive in quick succession.
+	a.RLock()
+	delay := a.tooltipDelay
+	a.RUnlock()
+	x, y := event.Position()
+	a.tooltipPending = &tooltip{text: text, x: x, y: y, due: time.Now().Add(delay)}
+	if a.tooltipTimer == nil {
+		a.tooltipTimer = time.AfterFunc(delay, a.showTooltip)
+	} else {
+		a.tooltipTimer.Reset(delay)
+	}
+	return
+}
+
+// showTooltip is called by the tooltip timer. It shows the pending tooltip
+// unless it was replaced by a newer one in the meantime, i.e. if the timer
+// fired just before it was reset.
+func (a *Application) showTooltip() {
+	a.QueueUpdateDraw(func() {
+		if t := a.tooltipPending; t != nil && !time.Now().Before(t.due) {
+			a.tooltip, a.tooltipPending = t, nil
+		}
+	})
+}
@@ -1,6 +1,8 @@
 package tview
 
 import (
+	"strings"
+
 	"github.com/gdamore/tcell/v2"
 )
 
@@ -30,8 +32,10 @@ type TreeNode struct {
 	// The item's text.
 	text string
 
-	// The text color.
-	color tcell.Color
+	// The text color and whether it was set with SetColor(), in which case it
+	// is kept by Application.SetTheme().
+	color    tcell.Color
+	colorSet bool
 
 	// Whether or not this node can be selected.
 	selectable bool
@@ -219,6 +223,7 @@ func (n *TreeNode) GetColor() tcell.Color {
 // SetColor sets the node's text color.
 func (n *TreeNode) SetColor(color tcell.Color) *TreeNode {
 	n.color = color
+	n.colorSet = true
 	return n
 }
 
@@ -252,15 +257,29 @@ func (n *TreeNode) GetLevel() int {
 //
 //   - j, down arrow, right arrow: Move (the selection) down by one node.
 //   - k, up arrow, left arrow: Move (the selection) up by one node.
+//   - left arrow, right arrow: Scroll left/right if horizontal scrolling is
+//     enabled (see SetHorizontalScroll()), instead of the above.
 //   - g, home: Move (the selection) to the top.
 //   - G, end: Move (the selection) to the bottom.
 //   - J: Move (the selection) up one level.
 //   - K: Move (the selection) down one level (if it is shown).
 //   - Ctrl-F, page down: Move (the selection) down by one page.
 //   - Ctrl-B, page up: Move (the selection) up by one page.
+//   - /: Start an incremental search (see below).
+//   - n: Move (the selection) to the next node matching the search text.
+//   - N: Move (the selection) to the previous node matching the search text.
 //
 // Selected nodes can trigger the "selected" callback when the user hits Enter.
 //
+// When the user presses "/", the tree view enters search mode. The text typed
+// by the user is shown in the last row and the selection moves to the first
+// node at or after the current node that matches it. Enter ends search mode,
+// keeping the search text for "n" and "N". Escape ends search mode and clears
+// the search text. Per default, nodes match if their text contains the search
+// text, ignoring case (see SetSearchFunc()). The ancestors of a matching node
+// are expanded so that it becomes visible. Searches can also be performed
+// programmatically with Search(), FindNext(), and FindPrev().
+//
 // The root node corresponds to level 0, its children correspond to level 1,
 // their children to level 2, and so on. Per default, the first level that is
 // displayed is 0, i.e. the root node. You can call SetTopLevel() to hide
@@ -291,9 +310,21 @@ type TreeView struct {
 	// Strings drawn before the nodes, based on their level.
 	prefixes []string
 
+	// An optional function which returns the string drawn before a node. It
+	// takes precedence over the prefixes slice.
+	prefixFunc func(node *TreeNode, expanded bool) string
+
 	// Vertical scroll offset.
 	offsetY int
 
+	// Whether or not the tree can be scrolled horizontally, and the
+	// horizontal scroll offset in cells.
+	horizontalScroll bool
+	offsetX          int
+
+	// The node which was selected during the last call to Draw().
+	lastDrawnNode *TreeNode
+
 	// If set to true, all node texts will be aligned horizontally.
 	align bool
 
@@ -316,14 +347,29 @@ type TreeView struct {
 
 	// The visible nodes, top-down, as set by process().
 	nodes []*TreeNode
+
+	// An optional function which determines whether a node matches the search
+	// text. If nil, a case-insensitive substring match on the node text is
+	// used.
+	searchFunc func(node *TreeNode, text string) bool
+
+	// If set to true, searches include the descendants of collapsed nodes.
+	searchCollapsed bool
+
+	// The current search text.
+	searchText string
+
+	// Set to true while the user is typing the search text.
+	searching bool
 }
 
 // NewTreeView returns a new tree view.
 func NewTreeView() *TreeView {
 	return &TreeView{
-		Box:           NewBox(),
-		graphics:      true,
-		graphicsColor: Styles.GraphicsColor,
+		Box:             NewBox(),
+		graphics:        true,
+		graphicsColor:   Styles.GraphicsColor,
+		searchCollapsed: true,
 	}
 }
 
@@ -377,6 +423,19 @@ func (t *TreeView) SetPrefixes(prefixes []string) *TreeView {
 	return t
 }
 
+// SetPrefixFunc sets a function which returns the string drawn before a node's
+// text, e.g. to show per-node icons ("📁" for folders, "📄" for files) or
+// expand/collapse indicators (">" and "v"). The function receives the node and
+// whether or not its child nodes are currently shown. If set, it takes
+// precedence over the prefixes set with SetPrefixes(). Prefixes may have
+// different widths, the node texts are moved accordingly.
+//
+// Provide nil to uninstall the function.
+func (t *TreeView) SetPrefixFunc(handler func(node *TreeNode, expanded bool) string) *TreeView {
+	t.prefixFunc = handler
+	return t
+}
+
 // SetAlign controls the horizontal alignment of the node texts. If set to true,
 // all texts except that of top-level nodes will be placed in the same column.
 // If set to false, they will indent with the hierarchy.
@@ -395,6 +454,7 @@ func (t *TreeView) SetGraphics(showGraphics bool) *TreeView {
 // SetGraphicsColor sets the colors of the lines used to draw the tree structure.
 func (t *TreeView) SetGraphicsColor(color tcell.Color) *TreeView {
 	t.graphicsColor = color
+	t.overrides.set(&t.graphicsColor)
 	return t
 }
 
@@ -419,6 +479,130 @@ func (t *TreeView) SetDoneFunc(handler func(key tcell.Key)) *TreeView {
 	return t
 }
 
+// SetSearchFunc sets a function which determines whether a node matches the
+// search text (see Search()). The default matches nodes whose text (without
+// style tags) contains the search text, ignoring case. Provide nil to restore
+// the default.
+func (t *TreeView) SetSearchFunc(match func(node *TreeNode, text string) bool) *TreeView {
+	t.searchFunc = match
+	return t
+}
+
+// SetSearchCollapsed sets whether searches include the descendants of
+// collapsed nodes (the default). When a match is found there, its ancestors
+// are expanded. If set to false, only nodes in expanded subtrees are searched.
+// Either way, only child nodes which have already been added to the tree are
+// searched, i.e. subtrees which are loaded lazily (e.g. when the user selects
+// their parent node) are not loaded by a search.
+func (t *TreeView) SetSearchCollapsed(collapsed bool) *TreeView {
+	t.searchCollapsed = collapsed
+	return t
+}
+
+// Search sets the search text and moves the selection to the first selectable
+// node at or after the current node (in depth-first order, wrapping around)
+// which matches it. The ancestors of the matching node are expanded and it is
+// scrolled into view. The matching node is returned, or nil if there is no
+// match, in which case the selection remains unchanged.
+func (t *TreeView) Search(text string) *TreeNode {
+	t.searchText = text
+	return t.find(true, true)
+}
+
+// FindNext moves the selection to the next node after the current node which
+// matches the search text set with Search() or typed by the user, wrapping
+// around at the end. It returns the matching node or nil if there is no match.
+func (t *TreeView) FindNext() *TreeNode {
+	return t.find(true, false)
+}
+
+// FindPrev moves the selection to the previous node before the current node
+// which matches the search text set with Search() or typed by the user,
+// wrapping around at the beginning. It returns the matching node or nil if
+// there is no match.
+func (t *TreeView) FindPrev() *TreeNode {
+	return t.find(false, false)
+}
+
+// GetSearchText returns the current search text.
+func (t *TreeView) GetSearchText() string {
+	return t.searchText
+}
+
+// find moves the selection to the next (forward) or previous node which
+// matches the search text, starting with the current node if includeCurrent
+// is true. It returns the matching node or nil if there is none.
+func (t *TreeView) find(forward, includeCurrent bool) *TreeNode {
+	if t.root == nil || t.searchText == "" {
+		return nil
+	}
+	match := t.searchFunc
+	if match == nil {
+		match = func(node *TreeNode, text string) bool {
+			return strings.Contains(strings.ToLower(stripTags(node.text)), strings.ToLower(text))
+		}
+	}
+
+	// Collect the nodes which may be selected.
+	var candidates []*TreeNode
+	current := -1
+	levels := make(map[*TreeNode]int)
+	t.root.Walk(func(node, parent *TreeNode) bool {
+		if parent != nil {
+			levels[node] = levels[parent] + 1
+		}
+		if levels[node] >= t.topLevel && node.selectable {
+			if node == t.currentNode {
+				current = len(candidates)
+			}
+			candidates = append(candidates, node)
+		}
+		return node.expanded || t.searchCollapsed
+	})
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	// Find the next match.
+	step := 1
+	if !forward {
+		step = -1
+	}
+	if current < 0 {
+		// Start at the beginning or the end.
+		includeCurrent = true
+		current = 0
+		if !forward {
+			current = len(candidates) - 1
+		}
+	}
+	start := 1
+	if includeCurrent {
+		start = 0
+	}
+	for offset := start; offset <= len(candidates); offset++ {
+		node := candidates[((current+step*offset)%len(candidates)+len(candidates))%len(candidates)]
+		if !match(node, t.searchText) {
+			continue
+		}
+
+		// Make it visible and select it.
+		for ancestor := node.parent; ancestor != nil; ancestor = ancestor.parent {
+			ancestor.expanded = true
+		}
+		if node != t.currentNode {
+			t.currentNode = node
+			if t.changed != nil {
+				t.changed(node)
+			}
+		}
+		t.Process()
+		return node
+	}
+
+	return nil
+}
+
 // GetScrollOffset returns the number of node rows that were skipped at the top
 // of the tree view. Note that when the user navigates the tree view, this value
 // is only updated after the tree view has been redrawn.
@@ -426,6 +610,36 @@ func (t *TreeView) GetScrollOffset() int {
 	return t.offsetY
 }
 
+// SetHorizontalScroll sets whether the tree view can be scrolled horizontally
+// to reveal deeply nested nodes and long node texts which would otherwise be
+// cut off. If enabled, the left and right arrow keys and the horizontal mouse
+// wheel scroll the tree instead of moving the selection, and moving the
+// selection up or down scrolls horizontally as needed to show the beginning of
+// the selected node. The graphics and prefixes scroll with the node texts.
+func (t *TreeView) SetHorizontalScroll(scroll bool) *TreeView {
+	t.horizontalScroll = scroll
+	if !scroll {
+		t.offsetX = 0
+	}
+	return t
+}
+
+// GetHorizontalScrollOffset returns the number of cells the tree view is
+// scrolled to the right (see SetHorizontalScroll()).
+func (t *TreeView) GetHorizontalScrollOffset() int {
+	return t.offsetX
+}
+
+// nodePrefix returns the prefix drawn before the given node's text.
+func (t *TreeView) nodePrefix(node *TreeNode) string {
+	if t.prefixFunc != nil {
+		return t.prefixFunc(node, node.expanded && len(node.children) > 0)
+	} else if len(t.prefixes) > 0 {
+		return t.prefixes[(node.level-t.topLevel)%len(t.prefixes)]
+	}
+	return ""
+}
+
 // GetRowCount returns the number of "visible" nodes. This includes nodes which
 // fall outside the tree view's box but notably does not include the children
 // of collapsed nodes. Note that this value is only up to date after the tree
@@ -619,6 +833,20 @@ func (t *TreeView) Process() {
 	}
 }
 
+// applyTheme implements the themeable interface.
+func (t *TreeView) applyTheme(from, to *Theme) {
+	t.Box.applyTheme(from, to)
+	t.overrides.color(&t.graphicsColor, to.GraphicsColor)
+	if t.root != nil {
+		t.root.Walk(func(node, parent *TreeNode) bool {
+			if !node.colorSet {
+				node.color = to.PrimaryTextColor
+			}
+			return true
+		})
+	}
+}
+
 // Draw draws this primitive onto the screen.
 func (t *TreeView) Draw(screen tcell.Screen) {
 	t.Box.DrawForSubclass(screen, t)
@@ -655,6 +883,62 @@ func (t *TreeView) Draw(screen tcell.Screen) {
 		t.offsetY = 0
 	}
 
+	// Scroll horizontally.
+	if !t.horizontalScroll {
+		t.offsetX = 0
+	} else {
+		// Bring a newly selected node into view.
+		if node := t.currentNode; node != nil && node != t.lastDrawnNode {
+			if node.textX < t.offsetX {
+				t.offsetX = node.textX
+			} else if node.textX >= t.offsetX+width {
+				t.offsetX = node.textX - width/2
+			}
+		}
+
+		// Don't scroll past the longest line.
+		var maxWidth int
+		for _, node := range t.nodes {
+			if w := node.textX + TaggedStringWidth(t.nodePrefix(node)) + TaggedStringWidth(node.text); w > maxWidth {
+				maxWidth = w
+			}
+		}
+		if t.offsetX > maxWidth-width {
+			t.offsetX = maxWidth - width
+		}
+		if t.offsetX < 0 {
+			t.offsetX = 0
+		}
+	}
+	t.lastDrawnNode = t.currentNode
+
+	// setContent draws a graphics character at the given column of the tree,
+	// unless it is scrolled out of view.
+	setContent := func(column, posY int, r rune, style tcell.Style, joined bool) {
+		column -= t.offsetX
+		if column < 0 || column >= width {
+			return
+		}
+		if joined {
+			PrintJoinedSemigraphics(screen, x+column, posY, r, style)
+		} else {
+			screen.SetContent(x+column, posY, r, nil, style)
+		}
+	}
+
+	// printAt prints text starting at the given column of the tree, skipping
+	// the part which is scrolled out of view.
+	printAt := func(text string, column, posY int, style tcell.Style, maintainBackground bool) {
+		column -= t.offsetX
+		var skip int
+		if column < 0 {
+			skip, column = -column, 0
+		}
+		if column < width {
+			printWithStyle(screen, text, x+column, posY, skip, width-column, AlignLeft, style, maintainBackground)
+		}
+	}
+
 	// Draw the tree.
 	posY := y
 	lineStyle := tcell.StyleDefault.Background(t.backgroundColor).Foreground(t.graphicsColor)
@@ -672,59 +956,62 @@ func (t *TreeView) Draw(screen tcell.Screen) {
 			// Draw ancestor branches.
 			ancestor := node.parent
 			for ancestor != nil && ancestor.parent != nil && ancestor.parent.level >= t.topLevel {
-				if ancestor.graphicsX >= width {
-					continue
-				}
-
 				// Draw a branch if this ancestor is not a last child.
 				if ancestor.parent.children[len(ancestor.parent.children)-1] != ancestor {
 					if posY-1 >= y && ancestor.textX > ancestor.graphicsX {
-						PrintJoinedSemigraphics(screen, x+ancestor.graphicsX, posY-1, Borders.Vertical, lineStyle)
+						setContent(ancestor.graphicsX, posY-1, Borders.Vertical, lineStyle, true)
 					}
 					if posY < y+height {
-						screen.SetContent(x+ancestor.graphicsX, posY, Borders.Vertical, nil, lineStyle)
+						setContent(ancestor.graphicsX, posY, Borders.Vertical, lineStyle, false)
 					}
 				}
 				ancestor = ancestor.parent
 			}
 
-			if node.textX > node.graphicsX && node.graphicsX < width {
+			if node.textX > node.graphicsX {
 				// Connect to the node above.
 				if posY-1 >= y && t.nodes[index-1].graphicsX <= node.graphicsX && t.nodes[index-1].textX > node.graphicsX {
-					PrintJoinedSemigraphics(screen, x+node.graphicsX, posY-1, Borders.TopLeft, lineStyle)
+					setContent(node.graphicsX, posY-1, Borders.TopLeft, lineStyle, true)
 				}
 
 				// Join this node.
 				if posY < y+height {
-					screen.SetContent(x+node.graphicsX, posY, Borders.BottomLeft, nil, lineStyle)
-					for pos := node.graphicsX + 1; pos < node.textX && pos < width; pos++ {
-						screen.SetContent(x+pos, posY, Borders.Horizontal, nil, lineStyle)
+					setContent(node.graphicsX, posY, Borders.BottomLeft, lineStyle, false)
+					for pos := node.graphicsX + 1; pos < node.textX; pos++ {
+						setContent(pos, posY, Borders.Horizontal, lineStyle, false)
 					}
 				}
 			}
 		}
 
 		// Draw the prefix and the text.
-		if node.textX < width && posY < y+height {
+		if posY < y+height {
 			// Prefix.
-			var prefixWidth int
-			if len(t.prefixes) > 0 {
-				_, prefixWidth = Print(screen, t.prefixes[(node.level-t.topLevel)%len(t.prefixes)], x+node.textX, posY, width-node.textX, AlignLeft, node.color)
+			prefix := t.nodePrefix(node)
+			if prefix != "" {
+				printAt(prefix, node.textX, posY, tcell.StyleDefault.Foreground(node.color), true)
 			}
 
 			// Text.
-			if node.textX+prefixWidth < width {
-				style := tcell.StyleDefault.Background(t.backgroundColor).Foreground(node.color)
-				if node == t.currentNode {
-					style = tcell.StyleDefault.Background(node.color).Foreground(t.backgroundColor)
-				}
-				printWithStyle(screen, node.text, x+node.textX+prefixWidth, posY, 0, width-node.textX-prefixWidth, AlignLeft, style, false)
+			style := tcell.StyleDefault.Background(t.backgroundColor).Foreground(node.color)
+			if node == t.currentNode {
+				style = tcell.StyleDefault.Background(node.color).Foreground(t.backgroundColor)
 			}
+			printAt(node.text, node.textX+TaggedStringWidth(prefix), posY, style, false)
 		}
 
 		// Advance.
 		posY++
 	}
+
+	// Draw the search text.
+	if t.searching && height > 0 {
+		style := tcell.StyleDefault.Background(t.backgroundColor).Foreground(Styles.PrimaryTextColor)
+		for index := 0; index < width; index++ {
+			screen.SetContent(x+index, y+height-1, ' ', nil, style)
+		}
+		printWithStyle(screen, Escape("/"+t.searchText), x, y+height-1, 0, width, AlignLeft, style, false)
+	}
   	t.DrawOverflow(screen, t.offsetY != 0, (t.offsetY != len(t.nodes)-t.innerHeight) && len(t.nodes) > t.innerHeight)
 
 }
@@ -744,6 +1031,28 @@ func (t *TreeView) InputHandler() func(event *tcell.EventKey, setFocus func(p Pr
 			}
 		}
 
+		// Process keys for the search text.
+		if t.searching {
+			switch key := event.Key(); key {
+			case tcell.KeyRune:
+				t.Search(t.searchText + string(event.Rune()))
+				return
+			case tcell.KeyBackspace, tcell.KeyBackspace2:
+				if runes := []rune(t.searchText); len(runes) > 0 {
+					t.Search(string(runes[:len(runes)-1]))
+				}
+				return
+			case tcell.KeyEnter:
+				t.searching = false
+				return
+			case tcell.KeyEscape:
+				t.searching = false
+				t.searchText = ""
+				return
+			}
+			t.searching = false
+		}
+
 		// Because the tree is flattened into a list only at drawing time, we also
 		// postpone the (selection) movement to drawing time.
 		switch key := event.Key(); key {
@@ -751,9 +1060,21 @@ func (t *TreeView) InputHandler() func(event *tcell.EventKey, setFocus func(p Pr
 			if t.done != nil {
 				t.done(key)
 			}
-		case tcell.KeyDown, tcell.KeyRight:
+		case tcell.KeyRight:
+			if t.horizontalScroll {
+				t.offsetX += 2 // We shift by 2 to account for two-cell characters.
+			} else {
+				t.movement = treeDown
+			}
+		case tcell.KeyLeft:
+			if t.horizontalScroll {
+				t.offsetX -= 2
+			} else {
+				t.movement = treeUp
+			}
+		case tcell.KeyDown:
 			t.movement = treeDown
-		case tcell.KeyUp, tcell.KeyLeft:
+		case tcell.KeyUp:
 			t.movement = treeUp
 		case tcell.KeyHome:
 			t.movement = treeHome
@@ -777,6 +1098,13 @@ func (t *TreeView) InputHandler() func(event *tcell.EventKey, setFocus func(p Pr
 				t.movement = treeUp
 			case 'K':
 				t.movement = treeParent
+			case '/':
+				t.searching = true
+				t.searchText = ""
+			case 'n':
+				t.FindNext()
+			case 'N':
+				t.FindPrev()
 			case ' ':
 				selectNode()
 			}
@@ -788,6 +1116,21 @@ func (t *TreeView) InputHandler() func(event *tcell.EventKey, setFocus func(p Pr
 	})
 }
 
+// nodeIndexAtPoint returns the index of the visible node (see GetRowCount())
+// found at the given position or a negative value if there is no such node.
+func (t *TreeView) nodeIndexAtPoint(x, y int) int {
+	rectX, rectY, width, height := t.GetInnerRect()
+	if x < rectX || x >= rectX+width || y < rectY || y >= rectY+height {
+		return -1
+	}
+
+	index := y - rectY + t.offsetY
+	if index < 0 || index >= len(t.nodes) {
+		return -1
+	}
+	return index
+}
+
 // MouseHandler returns the mouse handler for this primitive.
 func (t *TreeView) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
 	return t.WrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
@@ -799,10 +1142,8 @@ func (t *TreeView) MouseHandler() func(action MouseAction, event *tcell.EventMou
 		switch action {
 		case MouseLeftClick:
 			setFocus(t)
-			_, rectY, _, _ := t.GetInnerRect()
-			y += t.offsetY - rectY
-			if y >= 0 && y < len(t.nodes) {
-				node := t.nodes[y]
+			if index := t.nodeIndexAtPoint(x, y); index >= 0 {
+				node := t.nodes[index]
 				if node.selectable {
 					previousNode := t.currentNode
 					t.currentNode = node
@@ -824,6 +1165,16 @@ func (t *TreeView) MouseHandler() func(action MouseAction, event *tcell.EventMou
 		case MouseScrollDown:
 			t.movement = treeScrollDown
 			consumed = true
+		case MouseScrollLeft:
+			if t.horizontalScroll {
+				t.offsetX--
+				consumed = true
+			}
+		case MouseScrollRight:
+			if t.horizontalScroll {
+				t.offsetX++
+				consumed = true
+			}
 		}
 
 		return
@@ -0,0 +1,36 @@
+package tview
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTreeViewPrefixFunc(t *testing.T) {
+	root := NewTreeNode("root").
+		AddChild(NewTreeNode("closed").AddChild(NewTreeNode("hidden")).Collapse()).
+		AddChild(NewTreeNode("open").AddChild(NewTreeNode("child"))).
+		AddChild(NewTreeNode("leaf"))
+	tree := NewTreeView().SetRoot(root).SetGraphics(false).
+		SetPrefixFunc(func(node *TreeNode, expanded bool) string {
+			switch {
+			case len(node.GetChildren()) == 0:
+				return "- "
+			case expanded:
+				return "v "
+			default:
+				return "> "
+			}
+		})
+	tree.SetRect(0, 0, 20, 6)
+	screen := newTestScreen(t, 20, 6)
+	tree.Draw(screen)
+
+	for row, expected := range []string{"v root", "> closed", "v open", "- child", "- leaf"} {
+		if line := screenLine(screen, row); strings.TrimSpace(line) != expected {
+			t.Errorf("row %d is %q, expected %q", row, line, expected)
+		}
+	}
+	if line := screenLine(screen, 5); strings.TrimSpace(line) != "" {
+		t.Errorf("row 5 is %q, expected the collapsed child to be hidden", line)
+	}
+}
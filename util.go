@@ -23,10 +23,10 @@ const (
 
 // Common regular expressions.
 var (
-	colorPattern     = regexp.MustCompile(`\[([a-zA-Z]+|#[0-9a-zA-Z]{6}|\-)?(:([a-zA-Z]+|#[0-9a-zA-Z]{6}|\-)?(:([lbidrus]+|\-)?)?)?\]`)
+	colorPattern     = regexp.MustCompile(`\[([a-zA-Z]+|#[0-9a-zA-Z]{6}|\-)?(:([a-zA-Z]+|#[0-9a-zA-Z]{6}|\-)?(:([lbidrus]+|\-)?(:([a-zA-Z][a-zA-Z0-9+.\-]*:[a-zA-Z0-9_,;:\-\.#/?@!$&'()*+=%~]+|\-)?)?)?)?\]`)
 	regionPattern    = regexp.MustCompile(`\["([a-zA-Z0-9_,;: \-\.]*)"\]`)
-	escapePattern    = regexp.MustCompile(`\[([a-zA-Z0-9_,;: \-\."#]+)\[(\[*)\]`)
-	nonEscapePattern = regexp.MustCompile(`(\[[a-zA-Z0-9_,;: \-\."#]+\[*)\]`)
+	escapePattern    = regexp.MustCompile(`\[([a-zA-Z0-9_,;: \-\."#/?@!$&'()*+=%~]+)\[(\[*)\]`)
+	nonEscapePattern = regexp.MustCompile(`(\[[a-zA-Z0-9_,;: \-\."#/?@!$&'()*+=%~]+\[*)\]`)
 	boundaryPattern  = regexp.MustCompile(`(([,\.\-:;!\?&#+]|\n)[ \t\f\r]*|([ \t\f\r]+))`)
 	spacePattern     = regexp.MustCompile(`\s+`)
 )
@@ -49,6 +49,7 @@ const (
 	colorForegroundPos = 1
 	colorBackgroundPos = 3
 	colorFlagPos       = 5
+	colorURLPos        = 7
 )
 var availableColors = 256
 
@@ -157,6 +158,23 @@ func styleFromTag(fgColor, bgColor, attributes string, tagSubstrings []string, s
 	return fgColor, bgColor, attributes
 }
 
+// urlFromTag returns the link URL which applies after the given color tag,
+// given the URL before it (see styleFromTag()). An empty string means that
+// there is no link.
+func urlFromTag(url string, tagSubstrings []string) string {
+	if tagSubstrings[colorURLPos-1] == "" {
+		return url
+	}
+	switch newURL := tagSubstrings[colorURLPos]; newURL {
+	case "":
+		return url
+	case "-":
+		return ""
+	default:
+		return newURL
+	}
+}
+
 // overlayStyle calculates a new style based on "style" and applying tag-based
 // colors/attributes to it (see also styleFromTag()).
 func overlayStyle(style tcell.Style, fgColor, bgColor, attributes string) tcell.Style {
@@ -202,6 +220,29 @@ func overlayStyle(style tcell.Style, fgColor, bgColor, attributes string) tcell.
 	return style
 }
 
+// blendStyle blends the colors of "style" halfway towards the foreground and
+// background colors of "overlay" and adds the overlay's attributes. Overlay
+// colors set to tcell.ColorDefault leave the corresponding colors unchanged, as
+// do colors which have no RGB value.
+func blendStyle(style, overlay tcell.Style) tcell.Style {
+	overlayFg, overlayBg, overlayAttr := overlay.Decompose()
+	blend := func(color, target tcell.Color) tcell.Color {
+		if target == tcell.ColorDefault {
+			return color
+		}
+		r, g, b := color.RGB()
+		tr, tg, tb := target.RGB()
+		if r < 0 || tr < 0 {
+			return color
+		}
+		return tcell.NewRGBColor((r+tr)/2, (g+tg)/2, (b+tb)/2)
+	}
+	fg, bg, attr := style.Decompose()
+	return style.Foreground(blend(fg, overlayFg)).
+		Background(blend(bg, overlayBg)).
+		Attributes(attr | overlayAttr)
+}
+
 // decomposeString returns information about a string which may contain color
 // tags or region tags, depending on which ones are requested to be found. It
 // returns the indices of the color tags (as returned by
@@ -506,6 +547,66 @@ func stringWidth(text string) (width int) {
 	return
 }
 
+// StringWidth returns the number of screen cells needed to print the given
+// text. Wide characters (e.g. CJK characters) count as two cells, grapheme
+// clusters (e.g. characters with combining marks or emoji sequences) are
+// counted as one character. Color tags are not interpreted, use
+// TaggedStringWidth() for text containing color tags.
+func StringWidth(s string) int {
+	return stringWidth(s)
+}
+
+// TruncateToWidth shortens the given text such that it fits into the given
+// number of screen cells. If the text is shortened and the ellipsis is not 0,
+// the ellipsis is appended to the shortened text (and included in the width).
+// Grapheme clusters are never split and wide characters which only fit
+// partially are dropped so the result may be narrower than the given width.
+// Color tags are not interpreted.
+func TruncateToWidth(s string, width int, ellipsis rune) string {
+	if stringWidth(s) <= width {
+		return s
+	}
+	var suffix string
+	if ellipsis != 0 {
+		suffix = string(ellipsis)
+		width -= stringWidth(suffix)
+	}
+	if width < 0 {
+		return ""
+	}
+	end := 0
+	iterateString(s, func(main rune, comb []rune, textPos, textWidth, screenPos, screenWidth int) bool {
+		if screenPos+screenWidth > width {
+			return true
+		}
+		end = textPos + textWidth
+		return false
+	})
+	return s[:end] + suffix
+}
+
+// printWithEllipsis works like printWithStyle() without skipping any cells. If
+// the text doesn't fit into the given width, it is truncated and followed by an
+// ellipsis, regardless of the alignment. Wide characters which only fit
+// partially are dropped so the ellipsis immediately follows the last printed
+// character. It returns the width used.
+func printWithEllipsis(screen tcell.Screen, text string, x, y, maxWidth, align int, style tcell.Style, maintainBackground bool) int {
+	if maxWidth <= 0 {
+		return 0
+	}
+	if TaggedStringWidth(text) <= maxWidth {
+		_, printed, _, _ := printWithStyle(screen, text, x, y, 0, maxWidth, align, style, maintainBackground)
+		return printed
+	}
+	ellipsis := string(SemigraphicsHorizontalEllipsis)
+	_, printed, _, _ := printWithStyle(screen, text, x, y, 0, maxWidth-stringWidth(ellipsis), AlignLeft, style, maintainBackground)
+	if printed > 0 {
+		_, _, style, _ = screen.GetContent(x+printed-1, y)
+	}
+	_, ellipsisWidth, _, _ := printWithStyle(screen, ellipsis, x+printed, y, 0, maxWidth-printed, AlignLeft, style, maintainBackground)
+	return printed + ellipsisWidth
+}
+
 // WordWrap splits a text such that each resulting line does not exceed the
 // given screen width. Possible split points are after any punctuation or
 // whitespace. Whitespace after split points will be dropped.
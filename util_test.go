@@ -0,0 +1,227 @@
+package tview
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// newTestScreen returns an initialized simulation screen of the given size.
+func newTestScreen(t testing.TB, width, height int) tcell.SimulationScreen {
+	t.Helper()
+	screen := tcell.NewSimulationScreen("UTF-8")
+	if err := screen.Init(); err != nil {
+		t.Fatal(err)
+	}
+	screen.SetSize(width, height)
+	return screen
+}
+
+// screenLine returns the contents of the given row of the screen's buffer.
+// Empty cells are returned as spaces.
+func screenLine(screen tcell.Screen, y int) string {
+	var b strings.Builder
+	width, _ := screen.Size()
+	for x := 0; x < width; x++ {
+		mainc, _, _, _ := screen.GetContent(x, y)
+		if mainc == 0 {
+			mainc = ' '
+		}
+		b.WriteRune(mainc)
+	}
+	return b.String()
+}
+
+// startApp runs the application with RunSimulation() on a simulation screen of
+// the given size and returns the screen once it has been drawn for the first
+// time. The application is stopped when the test ends.
+func startApp(t *testing.T, app *Application, width, height int) tcell.SimulationScreen {
+	t.Helper()
+	screens := make(chan tcell.SimulationScreen, 1)
+	release := make(chan struct{})
+	runErr := make(chan error, 1)
+	go func() {
+		_, err := app.RunSimulation(width, height, func(sim tcell.SimulationScreen) {
+			screens <- sim
+			<-release
+		})
+		runErr <- err
+	}()
+	t.Cleanup(func() {
+		close(release)
+		select {
+		case err := <-runErr:
+			if err != nil {
+				t.Error(err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Error("application did not stop")
+		}
+	})
+	select {
+	case screen := <-screens:
+		return screen
+	case err := <-runErr:
+		runErr <- err
+		t.Fatalf("application stopped before it was drawn: %v", err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("application was not drawn")
+	}
+	return nil
+}
+
+// waitForEvents waits until the application running on the given screen has
+// handled all events posted to the screen so far.
+func waitForEvents(t *testing.T, screen tcell.Screen) {
+	t.Helper()
+	barrier := make(simulationBarrier)
+	screen.PostEventWait(tcell.NewEventInterrupt(barrier))
+	select {
+	case <-barrier:
+	case <-time.After(5 * time.Second):
+		t.Fatal("event loop is not responding")
+	}
+}
+
+func TestEscapeRoundTrip(t *testing.T) {
+	for _, text := range []string{
+		"[red]text",
+		"[:::https://evil.example]click me",
+		"[red::b:https://example.com/?a=1&b=2]link",
+		`["region"]text`,
+		"[a b]",
+		"[::]",
+		"[[]]",
+		"[:::https://a.example/~user/%20?a=(1)&b=*#top]",
+		"[a|b]",
+		"[日本]",
+	} {
+		if stripped := stripTags(Escape(text)); stripped != text {
+			t.Errorf("escaped %q is shown as %q", text, stripped)
+		}
+	}
+}
+
+func TestEscape(t *testing.T) {
+	for text, expected := range map[string]string{
+		"[red]text":                 "[red[]text",
+		`["region"]text`:            `["region"[]text`,
+		"[:::https://a.example/?q]": "[:::https://a.example/?q[]",
+		"[red[]":                    "[red[[]",
+		"[a b]":                     "[a b[]",
+		"[]":                        "[]",     // Not a tag.
+		"[a|b]":                     "[a|b]",  // Not a tag.
+		"[日本]":                      "[日本]",   // Not a tag.
+		"[a\nb]":                    "[a\nb]", // Not a tag.
+	} {
+		if escaped := Escape(text); escaped != expected {
+			t.Errorf("%q is escaped as %q, expected %q", text, escaped, expected)
+		}
+	}
+}
+
+func TestLinkTags(t *testing.T) {
+	// Links need a scheme and may not contain spaces or non-URL characters.
+	for _, text := range []string{"[red::b:foo bar]x", "[:::foo]x", "[:::nothing here]x", "[:::https://例え.jp]x", "[:::https://a.example/|]x"} {
+		if stripped := stripTags(text); stripped != text {
+			t.Errorf("%q is parsed as a tag, shown as %q", text, stripped)
+		}
+	}
+
+	// Valid links and link terminators are still tags.
+	for text, expected := range map[string]string{
+		"[::b:https://example.com]link[:::-]": "https://example.com",
+		"[:::mailto:a@example.com]mail":       "mailto:a@example.com",
+		"[:::-]text":                          "-",
+	} {
+		match := colorPattern.FindStringSubmatch(text)
+		if match == nil || match[colorURLPos] != expected {
+			t.Errorf("link in %q is %q, expected %q", text, match, expected)
+		}
+	}
+}
+
+func TestStringWidth(t *testing.T) {
+	for _, test := range []struct {
+		text  string
+		width int
+	}{
+		{text: "", width: 0},
+		{text: "abc", width: 3},
+		{text: "日本語", width: 6},
+		{text: "e\u0301", width: 1},              // Combining character.
+		{text: "a\u200bb", width: 2},             // Zero-width space.
+		{text: "[red]abc", width: 8},             // Tags are not interpreted.
+		{text: "\U0001F44D\U0001F3FD", width: 2}, // Emoji with modifier.
+	} {
+		if width := StringWidth(test.text); width != test.width {
+			t.Errorf("%q is %d cells wide, expected %d", test.text, width, test.width)
+		}
+	}
+}
+
+func TestTruncateToWidth(t *testing.T) {
+	for _, test := range []struct {
+		text     string
+		width    int
+		ellipsis rune
+		expected string
+	}{
+		{text: "abc", width: 3, ellipsis: '…', expected: "abc"},
+		{text: "abcd", width: 3, ellipsis: '…', expected: "ab…"},
+		{text: "abcd", width: 3, ellipsis: 0, expected: "abc"},
+		{text: "日本語", width: 4, ellipsis: '…', expected: "日…"}, // Partial wide character is dropped.
+		{text: "日本語", width: 5, ellipsis: 0, expected: "日本"},
+		{text: "e\u0301e\u0301e\u0301", width: 2, ellipsis: '…', expected: "e\u0301…"},
+		{text: "a\u200bbc", width: 2, ellipsis: '…', expected: "a\u200b…"},
+		{text: "abc", width: 0, ellipsis: '…', expected: ""},
+		{text: "abc", width: 0, ellipsis: 0, expected: ""},
+		{text: "abc", width: -1, ellipsis: 0, expected: ""},
+		{text: "abc", width: 1, ellipsis: '中', expected: ""}, // Ellipsis doesn't fit.
+		{text: "abc", width: 2, ellipsis: '中', expected: "中"},
+	} {
+		if truncated := TruncateToWidth(test.text, test.width, test.ellipsis); truncated != test.expected {
+			t.Errorf("%q truncated to %d cells with %q is %q, expected %q", test.text, test.width, test.ellipsis, truncated, test.expected)
+		}
+	}
+}
+
+func TestPrintWithEllipsis(t *testing.T) {
+	for _, test := range []struct {
+		text     string
+		width    int
+		align    int
+		expected string
+		printed  int
+		colors   string // One letter per printed cell: r(ed), b(lue), w(hite).
+	}{
+		{text: "abc", width: 5, align: AlignLeft, expected: "abc       ", printed: 3, colors: "www"},
+		{text: "abc", width: 5, align: AlignRight, expected: "  abc     ", printed: 3, colors: "  www"},
+		{text: "abcdef", width: 4, align: AlignRight, expected: "abc…      ", printed: 4, colors: "wwww"},
+		{text: "[red]abc[blue]def", width: 4, align: AlignLeft, expected: "abc…      ", printed: 4, colors: "rrrr"},
+		{text: "[red]ab[blue]cdef", width: 4, align: AlignLeft, expected: "abc…      ", printed: 4, colors: "rrbb"},
+		{text: "日本語", width: 4, align: AlignLeft, expected: "日 …       ", printed: 3, colors: "w w"},
+		{text: "abc", width: 1, align: AlignLeft, expected: "…         ", printed: 1, colors: "w"},
+		{text: "abc", width: 0, align: AlignLeft, expected: "          ", printed: 0},
+	} {
+		screen := newTestScreen(t, 10, 1)
+		printed := printWithEllipsis(screen, test.text, 0, 0, test.width, test.align, tcell.StyleDefault.Foreground(tcell.ColorWhite), false)
+		if line := screenLine(screen, 0); line != test.expected || printed != test.printed {
+			t.Errorf("%q printed into %d cells is %q (%d cells), expected %q (%d cells)", test.text, test.width, line, printed, test.expected, test.printed)
+			continue
+		}
+		for x, letter := range test.colors {
+			if letter == ' ' {
+				continue
+			}
+			_, _, style, _ := screen.GetContent(x, 0)
+			fg, _, _ := style.Decompose()
+			expected := map[rune]tcell.Color{'r': tcell.ColorRed, 'b': tcell.ColorBlue, 'w': tcell.ColorWhite}[letter]
+			if fg != expected {
+				t.Errorf("%q: cell %d has color %v, expected %v", test.text, x, fg, expected)
+			}
+		}
+	}
+}